@@ -1,12 +1,19 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/bug-breeder/quip-mcp/pkg/config"
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
 	"github.com/bug-breeder/quip-mcp/pkg/server"
 )
 
@@ -17,6 +24,9 @@ var (
 	date    = "unknown"
 )
 
+// diskCacheUserTTL is how long user lookups stay in the disk cache
+const diskCacheUserTTL = 24 * time.Hour
+
 func main() {
 	// Command line flags
 	var (
@@ -25,6 +35,13 @@ func main() {
 		setupConfig = flag.Bool("setup", false, "Run interactive configuration setup")
 		showConfig  = flag.Bool("config", false, "Show current configuration")
 		configPath  = flag.String("config-path", "", "Path to configuration file")
+		noConfig    = flag.Bool("no-config", false, "Read configuration only from environment variables, never from a file")
+		validate    = flag.Bool("validate", false, "Check the API token and connectivity against Quip")
+		selftestRun = flag.Bool("selftest", false, "Run a read-only sequence of Quip calls and report each one's result and timing")
+		httpAddr    = flag.String("http", "", "Serve MCP over HTTP on this address (e.g. :8080) instead of stdio")
+		readyTTL    = flag.Duration("readiness-ttl", server.DefaultReadinessTTL, "How long /readyz reuses its token check (0 disables it)")
+		skipCheck   = flag.Bool("skip-token-check", false, "Start without verifying the API token against Quip")
+		listTools   = flag.Bool("list-tools", false, "Print the available tools and their parameters as JSON")
 	)
 	flag.Parse()
 
@@ -42,9 +59,13 @@ func main() {
 
 	// Initialize config manager
 	var configManager *config.ConfigManager
-	if *configPath != "" {
-		// TODO: Support custom config path
-		configManager = config.New()
+	if *noConfig && *configPath != "" {
+		log.Fatal("-no-config and -config-path can't be used together")
+	}
+	if *noConfig {
+		configManager = config.NewEnvOnly()
+	} else if *configPath != "" {
+		configManager = config.NewWithPath(*configPath)
 	} else {
 		configManager = config.New()
 	}
@@ -63,39 +84,244 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle validate flag
+	if *validate {
+		if !validateConnection(configManager) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle selftest flag
+	if *selftestRun {
+		if !selftest(configManager) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Load configuration
 	cfg, err := configManager.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Check if we have a valid token
+	// Listing tools needs the configuration (the tool filter shapes it) but not a token
+	if *listTools {
+		if err := printTools(cfg, configManager); err != nil {
+			log.Fatalf("Failed to list tools: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	// Check if we have a valid token. Diagnostics go to stderr so an MCP client
+	// reading stdout never sees anything but protocol frames.
 	if cfg.QuipAPIToken == "" {
-		fmt.Println("❌ No Quip API token found!")
-		fmt.Println()
-		fmt.Println("You can set up your token in one of these ways:")
-		fmt.Println()
-		fmt.Println("1. 🔧 Interactive setup (recommended):")
-		fmt.Println("   quip-mcp --setup")
-		fmt.Println()
-		fmt.Println("2. 🌍 Environment variable:")
-		fmt.Println("   export QUIP_API_TOKEN=\"your-token-here\"")
-		fmt.Println("   quip-mcp")
-		fmt.Println()
-		fmt.Printf("3. 📁 Configuration file (%s):\n", configManager.GetConfigPath())
-		fmt.Println("   quip_api_token: your-token-here")
-		fmt.Println()
-		fmt.Println("Get your token from: https://quip.com/dev/token")
+		fmt.Fprintln(os.Stderr, "❌ No Quip API token found!")
+		fmt.Fprintln(os.Stderr)
+		if configManager.EnvOnly() {
+			fmt.Fprintln(os.Stderr, "Configuration files are disabled, so set the token in the environment:")
+			fmt.Fprintln(os.Stderr, "   export QUIP_API_TOKEN=\"your-token-here\"")
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintln(os.Stderr, "Get your token from: https://quip.com/dev/token")
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "You can set up your token in one of these ways:")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "1. 🔧 Interactive setup (recommended):")
+		fmt.Fprintln(os.Stderr, "   quip-mcp --setup")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "2. 🌍 Environment variable:")
+		fmt.Fprintln(os.Stderr, "   export QUIP_API_TOKEN=\"your-token-here\"")
+		fmt.Fprintln(os.Stderr, "   quip-mcp")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintf(os.Stderr, "3. 📁 Configuration file (%s):\n", configManager.GetConfigPath())
+		fmt.Fprintln(os.Stderr, "   quip_api_token: your-token-here")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Get your token from: https://quip.com/dev/token")
 		os.Exit(1)
 	}
 
+	// Catch paste mistakes before they turn into confusing 401s from Quip
+	if err := config.ValidateToken(cfg.QuipAPIToken, cfg.MinTokenLength); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ The Quip API token looks malformed: %v\n", err)
+		fmt.Fprintln(os.Stderr)
+		if errors.Is(err, config.ErrTokenTooShort) {
+			fmt.Fprintln(os.Stderr, "Check that the whole token was copied, get a new one from https://quip.com/dev/token, or set min_token_length if yours is genuinely shorter.")
+		} else {
+			fmt.Fprintln(os.Stderr, "Set only the token itself, as shown at https://quip.com/dev/token, or get a new one there.")
+		}
+		os.Exit(1)
+	}
+	if err := config.CheckTokenCharacters(cfg.QuipAPIToken); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v; continuing, but check the token if Quip rejects it\n", err)
+	}
+
+	// Structured logs go to stderr; stdout is reserved for the MCP protocol
+	level, _ := config.ParseLogLevel(cfg.LogLevel) // already validated by Load
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+
+	if cfg.DryRun {
+		logger.Warn("Dry-run mode: mutating tools will report their requests without sending them")
+	}
+
+	clientOpts := append(clientOptions(cfg, configManager), quip.WithConditionalRequests())
+
+	timezone, _ := config.ParseTimezone(cfg.Timezone) // already validated by Load
+
+	serverOpts := []server.Option{
+		server.WithLogger(logger),
+		server.WithVersion(version, commit),
+		server.WithReadinessTTL(*readyTTL),
+		server.WithClientOptions(clientOpts...),
+		server.WithDryRun(cfg.DryRun),
+		server.WithToolFilter(cfg.EnabledTools, cfg.DisabledTools),
+		server.WithDefaultLimits(cfg.DefaultSearchLimit, cfg.DefaultRecentLimit),
+		server.WithDeleteConfirmation(cfg.DeleteConfirmation, cfg.DeleteRequireTitle),
+		server.WithContentDir(cfg.ContentDir),
+		server.WithExportDir(cfg.ExportDir),
+		server.WithMarkdownOptions(markdownOptions(cfg)),
+		server.WithMarkdownConversion(cfg.Markdown.Conversion),
+		server.WithTimeFormat(timezone, cfg.DateFormat),
+	}
+	serverOpts = append(serverOpts, toolTimeoutOptions(cfg)...)
+	if cfg.DiskCacheMB > 0 {
+		cache, err := quip.NewDiskCache(cfg.DiskCacheDir, int64(cfg.DiskCacheMB)<<20)
+		if err != nil {
+			log.Fatalf("Failed to open disk cache: %v", err)
+		}
+		// The client's entries are scoped to the token and instance so a cache directory shared
+		// between accounts never serves one account's documents to another; the markdown cache
+		// scopes its own keys. Documents are keyed by updated_usec and never go stale; the TTL
+		// only bounds user lookups.
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = quip.BaseURL
+		}
+		clientCache := quip.NewScopedCache(cache, quip.CacheScope(cfg.QuipAPIToken, baseURL))
+		serverOpts = append(serverOpts,
+			server.WithClientOptions(quip.WithCache(clientCache, diskCacheUserTTL)),
+			server.WithMarkdownCache(cache),
+		)
+	}
+	if *skipCheck {
+		serverOpts = append(serverOpts, server.WithStartupCheck(0))
+	}
+	if cfg.MaxResponseBytes != 0 {
+		serverOpts = append(serverOpts, server.WithMaxResponseBytes(cfg.MaxResponseBytes))
+	}
+
 	// Start the MCP server
-	srv := server.New(cfg.QuipAPIToken)
-	if err := srv.Start(); err != nil {
+	srv := server.New(cfg.QuipAPIToken, serverOpts...)
+	if *httpAddr != "" {
+		err = srv.StartHTTP(*httpAddr)
+	} else {
+		err = srv.Start()
+	}
+	if err != nil {
 		log.Fatalf("Failed to start MCP server: %v", err)
 	}
 }
 
+// toolTimeoutOptions returns the server options for the configured tool timeouts
+func toolTimeoutOptions(cfg *config.Config) []server.Option {
+	var opts []server.Option
+	if cfg.ToolTimeout != "" {
+		timeout, _ := config.ParseTimeout(cfg.ToolTimeout) // already validated by Load
+		opts = append(opts, server.WithToolTimeout(timeout))
+	}
+	if len(cfg.ToolTimeouts) > 0 {
+		perTool := make(map[string]time.Duration, len(cfg.ToolTimeouts))
+		for tool, value := range cfg.ToolTimeouts {
+			perTool[tool], _ = config.ParseTimeout(value)
+		}
+		opts = append(opts, server.WithToolTimeouts(perTool))
+	}
+	return opts
+}
+
+// printTools writes the tools the configured server offers, with their parameters, to stdout as JSON
+func printTools(cfg *config.Config, configManager *config.ConfigManager) error {
+	s := server.New(cfg.QuipAPIToken,
+		server.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		server.WithClientOptions(clientOptions(cfg, configManager)...),
+		server.WithToolFilter(cfg.EnabledTools, cfg.DisabledTools),
+		server.WithDefaultLimits(cfg.DefaultSearchLimit, cfg.DefaultRecentLimit),
+		server.WithDeleteConfirmation(cfg.DeleteConfirmation, cfg.DeleteRequireTitle),
+		server.WithContentDir(cfg.ContentDir),
+		server.WithExportDir(cfg.ExportDir),
+		server.WithMarkdownOptions(markdownOptions(cfg)),
+	)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s.Tools())
+}
+
+// markdownOptions returns the markdown output settings from the configuration
+func markdownOptions(cfg *config.Config) server.MarkdownOptions {
+	return server.MarkdownOptions{
+		HeadingStyle: cfg.Markdown.HeadingStyle,
+		BulletMarker: cfg.Markdown.BulletMarker,
+		Links:        cfg.Markdown.Links,
+	}
+}
+
+// clientOptions returns the Quip client options implied by the configuration
+func clientOptions(cfg *config.Config, configManager *config.ConfigManager) []quip.Option {
+	opts := []quip.Option{quip.WithVersion(version, commit)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, quip.WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.APIVersion != 0 {
+		opts = append(opts, quip.WithAPIVersion(cfg.APIVersion))
+	}
+	if cfg.OAuthRefreshToken != "" {
+		opts = append(opts, quip.WithOAuthRefresh(quip.OAuthCredentials{
+			RefreshToken: cfg.OAuthRefreshToken,
+			ClientID:     cfg.OAuthClientID,
+			ClientSecret: cfg.OAuthClientSecret,
+			OnRotate: func(refreshToken string) {
+				if err := configManager.SaveOAuthRefreshToken(refreshToken); err != nil {
+					slog.Warn("Quip issued a new OAuth refresh token that couldn't be saved; the old one no longer works after a restart", "error", err)
+				}
+			},
+		}))
+	}
+	if cfg.MaxContentBytes != 0 {
+		opts = append(opts, quip.WithMaxContentBytes(cfg.MaxContentBytes))
+	}
+	if cfg.DefaultFolderID != "" {
+		opts = append(opts, quip.WithDefaultFolder(quip.ResolveThreadID(cfg.DefaultFolderID)))
+	}
+	if cfg.MaxConcurrency > 0 {
+		opts = append(opts, quip.WithMaxConcurrency(cfg.MaxConcurrency))
+	}
+	// Load rejects an explicit 0 for these, so 0 here means unset and selects the default
+	if threshold := derefInt(cfg.CircuitBreakerThreshold); threshold != -1 {
+		cooldown, _ := time.ParseDuration(cfg.CircuitBreakerCooldown) // already validated by Load
+		opts = append(opts, quip.WithCircuitBreaker(threshold, cooldown))
+	}
+	if cfg.MaxRetries == nil {
+		opts = append(opts, quip.WithRetry(quip.DefaultMaxRetries, 0))
+	} else if *cfg.MaxRetries > 0 {
+		opts = append(opts, quip.WithRetry(*cfg.MaxRetries, 0))
+	}
+	if reserve := derefInt(cfg.RatePacingReserve); reserve != -1 {
+		opts = append(opts, quip.WithRatePacing(reserve))
+	}
+	return opts
+}
+
+// derefInt returns an optional setting's value, or 0 if it is unset
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
 func showUsage() {
 	fmt.Println("Quip MCP Server")
 	fmt.Println()
@@ -110,12 +336,26 @@ func showUsage() {
 	fmt.Println("  -setup         Run interactive configuration setup")
 	fmt.Println("  -config        Show current configuration")
 	fmt.Println("  -config-path   Path to configuration file")
+	fmt.Println("  -no-config     Read configuration only from environment variables (same as QUIP_CONFIG=none)")
+	fmt.Println("  -validate      Check the API token and connectivity against Quip")
+	fmt.Println("  -selftest      Run read-only checks (user, recent threads, search) and report pass/fail with timings")
+	fmt.Println("  -http          Serve MCP over HTTP on this address (e.g. :8080) instead of stdio")
+	fmt.Println("  -readiness-ttl How long /readyz reuses its token check (default: 1m, 0 disables it)")
+	fmt.Println("  -skip-token-check Start without verifying the API token against Quip")
+	fmt.Println("  -list-tools    Print the available tools and their parameters as JSON")
 	fmt.Println()
 	fmt.Println("Configuration:")
 	fmt.Println("  The server looks for your Quip API token in this order:")
 	fmt.Println("  1. QUIP_API_TOKEN environment variable")
-	fmt.Println("  2. Configuration file (~/.config/quip-mcp/config.yaml)")
-	fmt.Println("  3. Interactive setup if no token found")
+	fmt.Println("  2. Token file (QUIP_API_TOKEN_FILE or quip_api_token_file in the config file)")
+	fmt.Printf("  3. Configuration file (%s)\n", config.New().GetConfigPath())
+	fmt.Println("  4. Interactive setup if no token found")
+	fmt.Println()
+	fmt.Println("  Log level (debug, info, warn, error) is read from QUIP_LOG_LEVEL")
+	fmt.Println("  or log_level in the configuration file (default: info).")
+	fmt.Println()
+	fmt.Println("  Set QUIP_DRY_RUN=true or dry_run: true to make mutating tools report")
+	fmt.Println("  the requests they would send without changing anything.")
 	fmt.Println()
 	fmt.Println("Setup:")
 	fmt.Println("  quip-mcp --setup     # Interactive token setup")
@@ -149,16 +389,42 @@ func showCurrentConfig(configManager *config.ConfigManager) {
 		return
 	}
 
-	fmt.Printf("Config file: %s\n", configManager.GetConfigPath())
-	fmt.Println()
+	if configManager.EnvOnly() {
+		fmt.Println("Config file: none (environment variables only)")
+		fmt.Println()
+	} else {
+		fmt.Printf("Config file: %s\n", configManager.GetConfigPath())
+		fmt.Println()
+	}
 
 	// Check if config file exists
-	if _, err := os.Stat(configManager.GetConfigPath()); os.IsNotExist(err) {
+	if configManager.EnvOnly() {
+		fmt.Println("📂 Config file: Disabled")
+	} else if _, err := os.Stat(configManager.GetConfigPath()); os.IsNotExist(err) {
 		fmt.Println("📂 Config file: Not found")
 	} else {
 		fmt.Println("📂 Config file: Found")
 	}
 
+	if cfg.TokenSource == config.TokenSourceKeyring {
+		fmt.Println("🔐 Token source: OS keyring")
+	}
+
+	if cfg.QuipAPITokenFile != "" {
+		fmt.Printf("📄 Token file: %s\n", cfg.QuipAPITokenFile)
+	}
+
+	if cfg.DryRun {
+		fmt.Println("🧪 Dry run: On (mutating tools won't change anything)")
+	}
+
+	if len(cfg.EnabledTools) > 0 {
+		fmt.Printf("🧰 Enabled tools: %s\n", strings.Join(cfg.EnabledTools, ", "))
+	}
+	if len(cfg.DisabledTools) > 0 {
+		fmt.Printf("🚫 Disabled tools: %s\n", strings.Join(cfg.DisabledTools, ", "))
+	}
+
 	// Check environment variable
 	if envToken := os.Getenv("QUIP_API_TOKEN"); envToken != "" {
 		fmt.Println("🌍 Environment variable: Set (will override config file)")
@@ -179,9 +445,60 @@ func showCurrentConfig(configManager *config.ConfigManager) {
 	}
 }
 
+func validateConnection(configManager *config.ConfigManager) bool {
+	fmt.Println("🔌 Validating Quip API connection")
+	fmt.Println("=================================")
+	fmt.Println()
+
+	cfg, err := configManager.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading configuration: %v\n", err)
+		return false
+	}
+
+	if cfg.QuipAPIToken == "" {
+		fmt.Println("❌ No Quip API token configured")
+		fmt.Println()
+		fmt.Println("Run 'quip-mcp --setup' to configure your API token.")
+		return false
+	}
+
+	fmt.Printf("🔑 API Token: %s\n", maskToken(cfg.QuipAPIToken))
+
+	client := quip.NewClient(cfg.QuipAPIToken, clientOptions(cfg, configManager)...)
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		fmt.Printf("❌ API request failed: %v\n", err)
+		return false
+	}
+
+	fmt.Printf("👤 Authenticated as: %s", user.Name)
+	if user.Email != "" {
+		fmt.Printf(" <%s>", user.Email)
+	}
+	fmt.Printf(" (ID: %s)\n", user.ID)
+
+	if rl, ok := client.RateLimit(); ok {
+		fmt.Printf("⏱️  Rate limit: %d of %d requests remaining", rl.Remaining, rl.Limit)
+		if !rl.Reset.IsZero() {
+			fmt.Printf(" (resets %s)", rl.Reset.Format(time.RFC1123))
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("⏱️  Rate limit: not reported")
+	}
+
+	fmt.Println("✅ Status: Connection OK")
+	return true
+}
+
+// maskToken hides all but a few characters of an API token for display
 func maskToken(token string) string {
-	if len(token) <= 8 {
+	// Reveal at most maskRevealChars characters at each end, and only when that's a
+	// small fraction of the token, so short tokens are never mostly shown
+	const maskRevealChars = 2
+	if len(token) < 8*maskRevealChars {
 		return "****"
 	}
-	return token[:4] + "****" + token[len(token)-4:]
+	return token[:maskRevealChars] + "****" + token[len(token)-maskRevealChars:]
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/config"
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestDefaultLimitBound(t *testing.T) {
+	// The configuration checks default limits against its own copy of the API's bound
+	if config.MaxDefaultLimit != quip.MaxLimit {
+		t.Errorf("Expected config.MaxDefaultLimit to match quip.MaxLimit (%d), got %d", quip.MaxLimit, config.MaxDefaultLimit)
+	}
+}
+
+func TestMaskToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		expected string
+	}{
+		{name: "empty", token: "", expected: "****"},
+		{name: "8 chars", token: "abcdefgh", expected: "****"},
+		{name: "9 chars", token: "abcdefghi", expected: "****"},
+		{name: "10 chars", token: "abcdefghij", expected: "****"},
+		{name: "15 chars", token: "abcdefghijklmno", expected: "****"},
+		{name: "16 chars", token: "abcdefghijklmnop", expected: "ab****op"},
+		{name: "very long", token: strings.Repeat("x", 60) + "tail", expected: "xx****il"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskToken(tt.token); got != tt.expected {
+				t.Errorf("maskToken(%q) = %q, expected %q", tt.token, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunSelftest(t *testing.T) {
+	var writes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/current":
+			_, _ = w.Write([]byte(`{"id": "user123", "name": "Ann"}`))
+		case "/threads/recent":
+			_, _ = w.Write([]byte(`[]`))
+		case "/threads/search":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": "Forbidden"}`))
+		default:
+			atomic.AddInt32(&writes, 1)
+		}
+	}))
+	defer server.Close()
+
+	client := quip.NewClient("test-token", quip.WithBaseURL(server.URL))
+	checks := append(append([]selftestCheck{}, selftestChecks...), selftestCheck{name: "Write", run: func(client *quip.Client) (string, error) {
+		_, err := client.CreateDocument("Self-test", "content")
+		return "created", err
+	}})
+
+	var out bytes.Buffer
+	if runSelftest(client, checks, &out) {
+		t.Error("Expected the self-test to fail")
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"✅ Current user (",
+		"): Ann (ID: user123)",
+		"✅ Recent threads (",
+		"❌ Search (",
+		"❌ Write (",
+		"2 of 4 checks passed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got %q", want, output)
+		}
+	}
+	if atomic.LoadInt32(&writes) != 0 {
+		t.Error("Expected the self-test never to send a write request")
+	}
+}
@@ -2,50 +2,216 @@ package config
 
 import (
 	"bufio"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
+	// Timezone names must resolve on systems without a zoneinfo database (Windows, slim containers)
+	_ "time/tzdata"
 
 	"golang.org/x/term"
-	"gopkg.in/yaml.v3"
 )
 
 // Config represents the application configuration
 type Config struct {
-	QuipAPIToken string `json:"quip_api_token" yaml:"quip_api_token"`
+	QuipAPIToken string `json:"quip_api_token" yaml:"quip_api_token" toml:"quip_api_token"`
+	// BaseURL is the API root of a company's Quip instance, e.g.
+	// https://platform.acme.quip.com/1 (default: https://platform.quip.com/1)
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty" toml:"base_url,omitempty"`
+	// APIVersion selects the Quip API version (1 or 2) for requests that exist in both;
+	// everything else always uses v1 (default: 1)
+	APIVersion int `json:"api_version,omitempty" yaml:"api_version,omitempty" toml:"api_version,omitempty"`
+	// QuipAPITokenFile is a path to a file holding the token (e.g. a mounted secret).
+	// When set it takes precedence over QuipAPIToken.
+	QuipAPITokenFile string `json:"quip_api_token_file,omitempty" yaml:"quip_api_token_file,omitempty" toml:"quip_api_token_file,omitempty"`
+	// OAuthRefreshToken, OAuthClientID and OAuthClientSecret enable refreshing for deployments
+	// that issue short-lived OAuth access tokens: when Quip rejects QuipAPIToken as expired, a
+	// new one is obtained with these and the request is retried. Leave them empty for personal tokens.
+	OAuthRefreshToken string `json:"oauth_refresh_token,omitempty" yaml:"oauth_refresh_token,omitempty" toml:"oauth_refresh_token,omitempty"`
+	OAuthClientID     string `json:"oauth_client_id,omitempty" yaml:"oauth_client_id,omitempty" toml:"oauth_client_id,omitempty"`
+	OAuthClientSecret string `json:"oauth_client_secret,omitempty" yaml:"oauth_client_secret,omitempty" toml:"oauth_client_secret,omitempty"`
+	// TokenSource selects where SetupInteractive stores the token and Load looks for it:
+	// "file" (default) or "keyring" for the OS secret store. If the keyring is unavailable
+	// the inline QuipAPIToken is used instead.
+	TokenSource string `json:"token_source,omitempty" yaml:"token_source,omitempty" toml:"token_source,omitempty"`
+	// MinTokenLength is the shortest API token accepted before any request is made
+	// (default: DefaultMinTokenLength). Lower it only if your tokens really are shorter.
+	MinTokenLength int `json:"min_token_length,omitempty" yaml:"min_token_length,omitempty" toml:"min_token_length,omitempty"`
+	// LogLevel is one of debug, info, warn or error (default: info)
+	LogLevel string `json:"log_level,omitempty" yaml:"log_level,omitempty" toml:"log_level,omitempty"`
+	// DryRun makes mutating tools report the requests they would send without sending them
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty" toml:"dry_run,omitempty"`
+	// DefaultFolderID is the folder (ID or URL) new documents are created in when
+	// create_document isn't given one (default: the user's private folder)
+	DefaultFolderID string `json:"default_folder_id,omitempty" yaml:"default_folder_id,omitempty" toml:"default_folder_id,omitempty"`
+	// EnabledTools, if set, limits the server to the named tools
+	EnabledTools []string `json:"enabled_tools,omitempty" yaml:"enabled_tools,omitempty" toml:"enabled_tools,omitempty"`
+	// DisabledTools names tools that are never registered, e.g. delete_document
+	DisabledTools []string `json:"disabled_tools,omitempty" yaml:"disabled_tools,omitempty" toml:"disabled_tools,omitempty"`
+	// MaxContentBytes limits the size of content sent when creating or editing documents
+	// (default: 1 MiB; -1 disables the check)
+	MaxContentBytes int `json:"max_content_bytes,omitempty" yaml:"max_content_bytes,omitempty" toml:"max_content_bytes,omitempty"`
+	// MaxResponseBytes limits the size of tool results sent to the MCP client; longer results
+	// are truncated with a marker (default: 256 KiB; -1 disables the limit)
+	MaxResponseBytes int `json:"max_response_bytes,omitempty" yaml:"max_response_bytes,omitempty" toml:"max_response_bytes,omitempty"`
+	// DefaultSearchLimit and DefaultRecentLimit are the number of results search_documents and
+	// get_recent_threads return when the agent doesn't pass a limit (default: 10, max: 100)
+	DefaultSearchLimit int `json:"default_search_limit,omitempty" yaml:"default_search_limit,omitempty" toml:"default_search_limit,omitempty"`
+	DefaultRecentLimit int `json:"default_recent_limit,omitempty" yaml:"default_recent_limit,omitempty" toml:"default_recent_limit,omitempty"`
+	// DeleteConfirmation is the phrase delete_document's confirm argument must match (default:
+	// DELETE; permanent deletion needs it prefixed with "PERMANENTLY "). With DeleteRequireTitle
+	// the phrase must be followed by the document's exact title.
+	DeleteConfirmation string `json:"delete_confirmation,omitempty" yaml:"delete_confirmation,omitempty" toml:"delete_confirmation,omitempty"`
+	DeleteRequireTitle bool   `json:"delete_require_title,omitempty" yaml:"delete_require_title,omitempty" toml:"delete_require_title,omitempty"`
+	// ContentDir, if set, lets create_document and edit_document read content from files
+	// inside this directory on the server (content_file argument)
+	ContentDir string `json:"content_dir,omitempty" yaml:"content_dir,omitempty" toml:"content_dir,omitempty"`
+	// ExportDir, if set, lets export_folder save zips as new files inside this directory on the
+	// server (output_path argument). Existing files are never overwritten.
+	ExportDir string `json:"export_dir,omitempty" yaml:"export_dir,omitempty" toml:"export_dir,omitempty"`
+	// ToolTimeout bounds each tool call as a duration such as "45s" (default: 2m; "0" disables
+	// it). ToolTimeouts overrides it per tool, e.g. {"search_documents": "10s"}.
+	ToolTimeout  string            `json:"tool_timeout,omitempty" yaml:"tool_timeout,omitempty" toml:"tool_timeout,omitempty"`
+	ToolTimeouts map[string]string `json:"tool_timeouts,omitempty" yaml:"tool_timeouts,omitempty" toml:"tool_timeouts,omitempty"`
+	// Markdown controls how documents are rendered as markdown
+	Markdown MarkdownConfig `json:"markdown,omitempty" yaml:"markdown,omitempty" toml:"markdown,omitempty"`
+	// Timezone is the IANA timezone timestamps in tool results are shown in, e.g. "Asia/Tokyo"
+	// (default: UTC). DateFormat is the Go time layout they are written with, e.g.
+	// "2006-01-02 15:04 MST" (default: "Jan 2, 2006 15:04 MST").
+	Timezone   string `json:"timezone,omitempty" yaml:"timezone,omitempty" toml:"timezone,omitempty"`
+	DateFormat string `json:"date_format,omitempty" yaml:"date_format,omitempty" toml:"date_format,omitempty"`
+	// DiskCacheMB, if positive, keeps converted documents in an on-disk cache of at most this
+	// many megabytes so they survive restarts (default: 0, disabled). DiskCacheDir is where the
+	// cache lives (default: a "cache" directory next to the configuration file).
+	DiskCacheMB  int    `json:"disk_cache_mb,omitempty" yaml:"disk_cache_mb,omitempty" toml:"disk_cache_mb,omitempty"`
+	DiskCacheDir string `json:"disk_cache_dir,omitempty" yaml:"disk_cache_dir,omitempty" toml:"disk_cache_dir,omitempty"`
+	// CircuitBreakerThreshold is how many requests in a row may fail with a server or network
+	// error before requests are paused for CircuitBreakerCooldown, a duration such as "30s"
+	// (defaults: 5 and 30s; a threshold of -1 disables the breaker, 0 is rejected)
+	CircuitBreakerThreshold *int   `json:"circuit_breaker_threshold,omitempty" yaml:"circuit_breaker_threshold,omitempty" toml:"circuit_breaker_threshold,omitempty"`
+	CircuitBreakerCooldown  string `json:"circuit_breaker_cooldown,omitempty" yaml:"circuit_breaker_cooldown,omitempty" toml:"circuit_breaker_cooldown,omitempty"`
+	// MaxConcurrency is how many requests batch operations (get_documents, delete_documents,
+	// export_folder) may have in flight at once, across all tool calls (default: 4)
+	MaxConcurrency int `json:"max_concurrency,omitempty" yaml:"max_concurrency,omitempty" toml:"max_concurrency,omitempty"`
+	// MaxRetries is how many times a request that failed with a 429, 502, 503 or 504 response
+	// or a network error is sent again (default when unset: 2; 0 or -1 disables retries).
+	// Writes that aren't safe to repeat, such as creating or editing a document, are only
+	// retried when the connection failed before anything was sent.
+	MaxRetries *int `json:"max_retries,omitempty" yaml:"max_retries,omitempty" toml:"max_retries,omitempty"`
+	// RatePacingReserve is how many requests may remain in Quip's rate-limit window before the
+	// rest are spread evenly until it resets (default: 10; -1 disables pacing, 0 is rejected)
+	RatePacingReserve *int `json:"rate_pacing_reserve,omitempty" yaml:"rate_pacing_reserve,omitempty" toml:"rate_pacing_reserve,omitempty"`
 }
 
+// MarkdownConfig holds the markdown output settings. Empty values keep the defaults.
+type MarkdownConfig struct {
+	// HeadingStyle is "atx" (# Heading, the default) or "setext" (underlined)
+	HeadingStyle string `json:"heading_style,omitempty" yaml:"heading_style,omitempty" toml:"heading_style,omitempty"`
+	// BulletMarker is "-" (the default), "*" or "+"
+	BulletMarker string `json:"bullet_marker,omitempty" yaml:"bullet_marker,omitempty" toml:"bullet_marker,omitempty"`
+	// Links is "inline" (the default), "referenced" or "text" (drop URLs, keep the link text)
+	Links string `json:"links,omitempty" yaml:"links,omitempty" toml:"links,omitempty"`
+	// Conversion is where markdown sent to create and edit documents is rendered: "client"
+	// (the default) converts it to Quip HTML locally, "server" leaves it to Quip
+	Conversion string `json:"conversion,omitempty" yaml:"conversion,omitempty" toml:"conversion,omitempty"`
+}
+
+// ParseLogLevel converts a configured log level name into a slog.Level.
+// An empty string selects the default level (info).
+func ParseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: must be one of debug, info, warn, error", level)
+	}
+}
+
+// EnvOnlyConfig is the QUIP_CONFIG value that selects environment-only configuration
+const EnvOnlyConfig = "none"
+
+// errEnvOnly is returned when a configuration file is needed in environment-only mode
+var errEnvOnly = fmt.Errorf("no configuration file is used in environment-only mode (QUIP_CONFIG=%s or -no-config)", EnvOnlyConfig)
+
 // ConfigManager handles loading and saving configuration
 type ConfigManager struct {
 	configPath string
+	envOnly    bool
 }
 
-// New creates a new ConfigManager
+// New creates a new ConfigManager for the default configuration file, or an
+// environment-only one (see NewEnvOnly) when QUIP_CONFIG=none
 func New() *ConfigManager {
+	if os.Getenv("QUIP_CONFIG") == EnvOnlyConfig {
+		return NewEnvOnly()
+	}
 	return &ConfigManager{
 		configPath: getConfigPath(),
 	}
 }
 
+// NewEnvOnly creates a ConfigManager that reads configuration only from environment
+// variables and never touches a configuration file, for containers and other read-only
+// deployments. Save and SetupInteractive fail in this mode.
+func NewEnvOnly() *ConfigManager {
+	return &ConfigManager{envOnly: true}
+}
+
+// EnvOnly reports whether the manager ignores configuration files
+func (cm *ConfigManager) EnvOnly() bool {
+	return cm.envOnly
+}
+
+// NewWithPath creates a ConfigManager that reads and writes the given file.
+// The file's format (YAML, JSON or TOML) is chosen from its extension.
+func NewWithPath(path string) *ConfigManager {
+	return &ConfigManager{
+		configPath: path,
+	}
+}
+
 // getConfigPath returns the path to the configuration file
 func getConfigPath() string {
-	// Try XDG_CONFIG_HOME first (Linux/Unix standard)
+	// An explicit XDG_CONFIG_HOME wins on every platform
 	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
 		return filepath.Join(xdgConfig, "quip-mcp", "config.yaml")
 	}
 
-	// Fall back to home directory
-	home, err := os.UserHomeDir()
-	if err != nil {
+	// Earlier versions always used ~/.config; keep using an existing file there so
+	// macOS and Windows users don't lose their configuration
+	home, homeErr := os.UserHomeDir()
+	legacyPath := filepath.Join(home, ".config", "quip-mcp", "config.yaml")
+	if homeErr == nil {
+		if _, err := os.Stat(legacyPath); err == nil {
+			return legacyPath
+		}
+	}
+
+	// Use the platform's config directory: ~/.config on Linux, ~/Library/Application Support
+	// on macOS and %AppData% on Windows
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "quip-mcp", "config.yaml")
+	}
+
+	if homeErr != nil {
 		// If we can't get home directory, use current directory
 		return ".quip-mcp-config.yaml"
 	}
 
-	// Use .config in home directory (follows XDG on Linux, reasonable on other platforms)
-	return filepath.Join(home, ".config", "quip-mcp", "config.yaml")
+	return legacyPath
 }
 
 // Load loads configuration from file and environment
@@ -53,18 +219,355 @@ func (cm *ConfigManager) Load() (*Config, error) {
 	config := &Config{}
 
 	// First, try to load from config file
-	if err := cm.loadFromFile(config); err != nil && !os.IsNotExist(err) {
-		return nil, fmt.Errorf("failed to load config file: %w", err)
+	if !cm.envOnly {
+		if err := cm.loadFromFile(config); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load config file: %w", err)
+		}
+	}
+
+	if baseURL := os.Getenv("QUIP_BASE_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+
+	if folderID := os.Getenv("QUIP_DEFAULT_FOLDER_ID"); folderID != "" {
+		config.DefaultFolderID = folderID
 	}
 
-	// Override with environment variable if set
+	if tokenFile := os.Getenv("QUIP_API_TOKEN_FILE"); tokenFile != "" {
+		config.QuipAPITokenFile = tokenFile
+	}
+
+	// Precedence: QUIP_API_TOKEN > token file > keyring > inline token
 	if token := os.Getenv("QUIP_API_TOKEN"); token != "" {
 		config.QuipAPIToken = token
+	} else if config.QuipAPITokenFile != "" {
+		token, err := readTokenFile(config.QuipAPITokenFile)
+		if err != nil {
+			return nil, err
+		}
+		config.QuipAPIToken = token
+	} else if config.TokenSource == TokenSourceKeyring {
+		// Keyring errors are not fatal: fall back to the inline token, if any
+		if token, err := loadKeyringToken(); err == nil {
+			config.QuipAPIToken = token
+		}
+	}
+
+	if value := os.Getenv("QUIP_OAUTH_REFRESH_TOKEN"); value != "" {
+		config.OAuthRefreshToken = value
+	}
+	if value := os.Getenv("QUIP_OAUTH_CLIENT_ID"); value != "" {
+		config.OAuthClientID = value
+	}
+	if value := os.Getenv("QUIP_OAUTH_CLIENT_SECRET"); value != "" {
+		config.OAuthClientSecret = value
+	}
+	if config.OAuthRefreshToken != "" && (config.OAuthClientID == "" || config.OAuthClientSecret == "") {
+		return nil, fmt.Errorf("oauth_refresh_token requires oauth_client_id and oauth_client_secret")
+	}
+
+	switch config.TokenSource {
+	case "", TokenSourceFile, TokenSourceKeyring:
+	default:
+		return nil, fmt.Errorf("invalid token_source %q: must be %q or %q", config.TokenSource, TokenSourceFile, TokenSourceKeyring)
+	}
+
+	if level := os.Getenv("QUIP_LOG_LEVEL"); level != "" {
+		config.LogLevel = level
+	}
+
+	if _, err := ParseLogLevel(config.LogLevel); err != nil {
+		return nil, err
+	}
+
+	if dryRun := os.Getenv("QUIP_DRY_RUN"); dryRun != "" {
+		enabled, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUIP_DRY_RUN %q: must be true or false", dryRun)
+		}
+		config.DryRun = enabled
+	}
+
+	if phrase := os.Getenv("QUIP_DELETE_CONFIRMATION"); phrase != "" {
+		config.DeleteConfirmation = phrase
+	}
+	if config.DeleteConfirmation != "" && strings.TrimSpace(config.DeleteConfirmation) == "" {
+		return nil, fmt.Errorf("invalid delete_confirmation: must not be blank")
+	}
+
+	if requireTitle := os.Getenv("QUIP_DELETE_REQUIRE_TITLE"); requireTitle != "" {
+		enabled, err := strconv.ParseBool(requireTitle)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUIP_DELETE_REQUIRE_TITLE %q: must be true or false", requireTitle)
+		}
+		config.DeleteRequireTitle = enabled
+	}
+
+	for _, setting := range []struct {
+		env   string
+		value *int
+	}{
+		{"QUIP_MAX_CONTENT_BYTES", &config.MaxContentBytes},
+		{"QUIP_MAX_RESPONSE_BYTES", &config.MaxResponseBytes},
+		{"QUIP_DEFAULT_SEARCH_LIMIT", &config.DefaultSearchLimit},
+		{"QUIP_DEFAULT_RECENT_LIMIT", &config.DefaultRecentLimit},
+		{"QUIP_API_VERSION", &config.APIVersion},
+		{"QUIP_DISK_CACHE_MB", &config.DiskCacheMB},
+		{"QUIP_MIN_TOKEN_LENGTH", &config.MinTokenLength},
+		{"QUIP_MAX_CONCURRENCY", &config.MaxConcurrency},
+	} {
+		if err := envInt(setting.env, setting.value); err != nil {
+			return nil, err
+		}
+	}
+	for _, setting := range []struct {
+		env   string
+		value **int
+	}{
+		{"QUIP_MAX_RETRIES", &config.MaxRetries},
+		{"QUIP_CIRCUIT_BREAKER_THRESHOLD", &config.CircuitBreakerThreshold},
+		{"QUIP_RATE_PACING_RESERVE", &config.RatePacingReserve},
+	} {
+		if err := envOptionalInt(setting.env, setting.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.APIVersion != 0 && config.APIVersion != 1 && config.APIVersion != 2 {
+		return nil, fmt.Errorf("invalid api_version %d: must be 1 or 2", config.APIVersion)
+	}
+
+	if config.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("invalid max_concurrency %d: must be positive", config.MaxConcurrency)
+	}
+
+	if config.MaxRetries != nil && *config.MaxRetries < -1 {
+		return nil, fmt.Errorf("invalid max_retries %d: must be 0 or more; 0 and -1 disable retries", *config.MaxRetries)
+	}
+
+	if reserve := config.RatePacingReserve; reserve != nil && (*reserve == 0 || *reserve < -1) {
+		return nil, fmt.Errorf("invalid rate_pacing_reserve %d: must be 1 or more, or -1 to disable pacing", *reserve)
+	}
+
+	if config.MinTokenLength < 0 {
+		return nil, fmt.Errorf("invalid min_token_length %d: must not be negative", config.MinTokenLength)
+	}
+
+	if threshold := config.CircuitBreakerThreshold; threshold != nil && (*threshold == 0 || *threshold < -1) {
+		return nil, fmt.Errorf("invalid circuit_breaker_threshold %d: must be 1 or more, or -1 to disable the breaker", *threshold)
+	}
+	if cooldown := os.Getenv("QUIP_CIRCUIT_BREAKER_COOLDOWN"); cooldown != "" {
+		config.CircuitBreakerCooldown = cooldown
+	}
+	if config.CircuitBreakerCooldown != "" {
+		if cooldown, err := time.ParseDuration(config.CircuitBreakerCooldown); err != nil || cooldown <= 0 {
+			return nil, fmt.Errorf("invalid circuit_breaker_cooldown %q: must be a positive duration like 30s", config.CircuitBreakerCooldown)
+		}
+	}
+
+	if config.DiskCacheMB < 0 {
+		return nil, fmt.Errorf("invalid disk_cache_mb %d: must not be negative", config.DiskCacheMB)
+	}
+	if dir := os.Getenv("QUIP_DISK_CACHE_DIR"); dir != "" {
+		config.DiskCacheDir = dir
+	}
+	if config.DiskCacheMB > 0 && config.DiskCacheDir == "" {
+		config.DiskCacheDir = cm.defaultCacheDir()
+	}
+
+	if err := validateDefaultLimit("default_search_limit", config.DefaultSearchLimit); err != nil {
+		return nil, err
+	}
+	if err := validateDefaultLimit("default_recent_limit", config.DefaultRecentLimit); err != nil {
+		return nil, err
+	}
+
+	if dir := os.Getenv("QUIP_CONTENT_DIR"); dir != "" {
+		config.ContentDir = dir
+	}
+	if config.ContentDir != "" {
+		if info, err := os.Stat(config.ContentDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("invalid content_dir %q: must be an existing directory", config.ContentDir)
+		}
+	}
+
+	if dir := os.Getenv("QUIP_EXPORT_DIR"); dir != "" {
+		config.ExportDir = dir
+	}
+	if config.ExportDir != "" {
+		if info, err := os.Stat(config.ExportDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("invalid export_dir %q: must be an existing directory", config.ExportDir)
+		}
+	}
+
+	if timeout := os.Getenv("QUIP_TOOL_TIMEOUT"); timeout != "" {
+		config.ToolTimeout = timeout
+	}
+	if config.ToolTimeout != "" {
+		if _, err := ParseTimeout(config.ToolTimeout); err != nil {
+			return nil, fmt.Errorf("invalid tool_timeout: %w", err)
+		}
+	}
+	for tool, timeout := range config.ToolTimeouts {
+		if _, err := ParseTimeout(timeout); err != nil {
+			return nil, fmt.Errorf("invalid tool_timeouts entry for %s: %w", tool, err)
+		}
+	}
+
+	if timezone := os.Getenv("QUIP_TIMEZONE"); timezone != "" {
+		config.Timezone = timezone
+	}
+	if _, err := ParseTimezone(config.Timezone); err != nil {
+		return nil, err
+	}
+	if format := os.Getenv("QUIP_DATE_FORMAT"); format != "" {
+		config.DateFormat = format
+	}
+	if config.DateFormat != "" && !isTimeLayout(config.DateFormat) {
+		return nil, fmt.Errorf("invalid date_format %q: must be a Go time layout such as \"2006-01-02 15:04 MST\"", config.DateFormat)
+	}
+
+	for _, setting := range []struct {
+		env, name string
+		value     *string
+		allowed   []string
+	}{
+		{"QUIP_MARKDOWN_HEADING_STYLE", "markdown.heading_style", &config.Markdown.HeadingStyle, []string{"atx", "setext"}},
+		{"QUIP_MARKDOWN_BULLET_MARKER", "markdown.bullet_marker", &config.Markdown.BulletMarker, []string{"-", "*", "+"}},
+		{"QUIP_MARKDOWN_LINKS", "markdown.links", &config.Markdown.Links, []string{"inline", "referenced", "text"}},
+		{"QUIP_MARKDOWN_CONVERSION", "markdown.conversion", &config.Markdown.Conversion, []string{"client", "server"}},
+	} {
+		if value := os.Getenv(setting.env); value != "" {
+			*setting.value = value
+		}
+		if *setting.value != "" && !slices.Contains(setting.allowed, *setting.value) {
+			return nil, fmt.Errorf("invalid %s %q: must be one of %s", setting.name, *setting.value, strings.Join(setting.allowed, ", "))
+		}
+	}
+
+	if tools := os.Getenv("QUIP_ENABLED_TOOLS"); tools != "" {
+		config.EnabledTools = splitList(tools)
+	}
+	if tools := os.Getenv("QUIP_DISABLED_TOOLS"); tools != "" {
+		config.DisabledTools = splitList(tools)
 	}
 
 	return config, nil
 }
 
+// defaultCacheDir returns where the disk cache lives when disk_cache_dir isn't set: next to
+// the configuration file, or in the user's cache directory in environment-only mode
+func (cm *ConfigManager) defaultCacheDir() string {
+	if cm.configPath != "" {
+		return filepath.Join(filepath.Dir(cm.configPath), "cache")
+	}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cacheDir, "quip-mcp")
+	}
+	return filepath.Join(os.TempDir(), "quip-mcp-cache")
+}
+
+// envInt overrides *value with the integer in environment variable name, if it is set
+func envInt(name string, value *int) error {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: must be a whole number", name, raw)
+	}
+	*value = n
+	return nil
+}
+
+// envOptionalInt overrides an optional integer setting with the named environment variable,
+// if set. Unlike envInt it can tell an explicit 0 from a setting that was left out.
+func envOptionalInt(name string, value **int) error {
+	var n int
+	if os.Getenv(name) == "" {
+		return nil
+	}
+	if err := envInt(name, &n); err != nil {
+		return err
+	}
+	*value = &n
+	return nil
+}
+
+// ParseTimeout parses a configured timeout such as "30s" or "2m". "0" means no timeout;
+// negative durations are rejected.
+func ParseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration like 30s or 2m", value)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("%q must not be negative", value)
+	}
+	return timeout, nil
+}
+
+// ParseTimezone loads the IANA timezone name (e.g. "Europe/Paris"). An empty name is UTC.
+func ParseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: must be an IANA name such as Asia/Tokyo or UTC", name)
+	}
+	return loc, nil
+}
+
+// isTimeLayout reports whether layout contains at least one element of Go's reference time,
+// so that formatting with it doesn't print the same fixed text for every timestamp. The
+// sample time differs from the reference time in every element.
+func isTimeLayout(layout string) bool {
+	sample := time.Date(2001, time.March, 4, 7, 8, 9, 0, time.UTC)
+	return sample.Format(layout) != layout
+}
+
+// MaxDefaultLimit is the largest default_search_limit or default_recent_limit accepted: the
+// most results Quip returns per request (quip.MaxLimit, which this package doesn't import)
+const MaxDefaultLimit = 100
+
+// validateDefaultLimit checks that a configured default result limit is within the API's
+// bounds. Zero means unset.
+func validateDefaultLimit(name string, limit int) error {
+	if limit < 0 || limit > MaxDefaultLimit {
+		return fmt.Errorf("invalid %s %d: must be between 1 and %d, or 0 for the default", name, limit, MaxDefaultLimit)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping surrounding whitespace and empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readTokenFile reads an API token from path, trimming surrounding whitespace
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+
+	return token, nil
+}
+
 // loadFromFile loads configuration from the config file
 func (cm *ConfigManager) loadFromFile(config *Config) error {
 	data, err := os.ReadFile(cm.configPath)
@@ -72,27 +575,26 @@ func (cm *ConfigManager) loadFromFile(config *Config) error {
 		return err
 	}
 
-	// Try to parse as YAML first, then JSON
-	if err := yaml.Unmarshal(data, config); err != nil {
-		// If YAML fails, try JSON
-		if jsonErr := json.Unmarshal(data, config); jsonErr != nil {
-			return fmt.Errorf("failed to parse config as YAML or JSON: %w", err)
-		}
+	if err := decodeConfig(formatForPath(cm.configPath), data, config); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", cm.configPath, err)
 	}
 
 	return nil
 }
 
-// Save saves the configuration to file
+// Save saves the configuration to file, in the format implied by the file's extension
 func (cm *ConfigManager) Save(config *Config) error {
+	if cm.envOnly {
+		return errEnvOnly
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(cm.configPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(config)
+	data, err := encodeConfig(formatForPath(cm.configPath), config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -105,6 +607,26 @@ func (cm *ConfigManager) Save(config *Config) error {
 	return nil
 }
 
+// SaveOAuthRefreshToken replaces oauth_refresh_token in the configuration file, leaving the
+// rest of the file as it is (values from the environment aren't written). Quip invalidates a
+// refresh token once it issues a new one, so the new one must be saved to survive a restart.
+func (cm *ConfigManager) SaveOAuthRefreshToken(token string) error {
+	if cm.envOnly {
+		return errEnvOnly
+	}
+	if os.Getenv("QUIP_OAUTH_REFRESH_TOKEN") != "" {
+		return fmt.Errorf("the refresh token comes from QUIP_OAUTH_REFRESH_TOKEN, which can't be updated; set it to the new token")
+	}
+
+	var config Config
+	if err := cm.loadFromFile(&config); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	config.OAuthRefreshToken = token
+
+	return cm.Save(&config)
+}
+
 // GetConfigPath returns the path to the configuration file
 func (cm *ConfigManager) GetConfigPath() string {
 	return cm.configPath
@@ -112,6 +634,10 @@ func (cm *ConfigManager) GetConfigPath() string {
 
 // SetupInteractive prompts the user to configure the API token
 func (cm *ConfigManager) SetupInteractive() error {
+	if cm.envOnly {
+		return errEnvOnly
+	}
+
 	fmt.Println("🔧 Quip MCP Server Setup")
 	fmt.Println("========================")
 	fmt.Println()
@@ -126,19 +652,28 @@ func (cm *ConfigManager) SetupInteractive() error {
 		return fmt.Errorf("failed to read token: %w", err)
 	}
 
-	token = strings.TrimSpace(token)
-	if token == "" {
-		return fmt.Errorf("token cannot be empty")
+	// Keep any other settings already in the config file
+	config := &Config{}
+	if err := cm.loadFromFile(config); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load existing configuration: %w", err)
 	}
 
-	// Validate token format (basic check)
-	if len(token) < 10 {
-		return fmt.Errorf("token appears to be too short, please check and try again")
+	token = strings.TrimSpace(token)
+	if err := ValidateToken(token, config.MinTokenLength); err != nil {
+		return fmt.Errorf("%w, please check and try again", err)
 	}
+	if err := CheckTokenCharacters(token); err != nil {
+		fmt.Printf("⚠️  %v; saving it anyway\n", err)
+	}
+	config.QuipAPIToken = token
 
-	// Save configuration
-	config := &Config{
-		QuipAPIToken: token,
+	if config.TokenSource == TokenSourceKeyring {
+		if err := saveKeyringToken(token); err != nil {
+			fmt.Printf("⚠️  %v; saving the token to the config file instead\n", err)
+		} else {
+			config.QuipAPIToken = ""
+			fmt.Println("🔐 Token stored in the OS keyring")
+		}
 	}
 
 	if err := cm.Save(config); err != nil {
@@ -176,11 +711,11 @@ func readPassword() (string, error) {
 	return strings.TrimSuffix(password, "\n"), nil
 }
 
-// HasValidToken checks if a valid token is available
+// HasValidToken checks if a token that passes ValidateToken is available
 func (cm *ConfigManager) HasValidToken() bool {
 	config, err := cm.Load()
 	if err != nil {
 		return false
 	}
-	return config.QuipAPIToken != "" && len(config.QuipAPIToken) >= 10
+	return ValidateToken(config.QuipAPIToken, config.MinTokenLength) == nil
 }
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetConfigPath_Platform(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	expected := filepath.Join(home, "Library", "Application Support", "quip-mcp", "config.yaml")
+	if path := getConfigPath(); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+}
+
+func TestGetConfigPath_LegacyLocation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	legacy := filepath.Join(home, ".config", "quip-mcp", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatalf("Failed to create legacy config directory: %v", err)
+	}
+	if err := os.WriteFile(legacy, []byte("quip_api_token: test\n"), 0600); err != nil {
+		t.Fatalf("Failed to write legacy config: %v", err)
+	}
+
+	if path := getConfigPath(); path != legacy {
+		t.Errorf("Expected existing config at %s to be kept, got %s", legacy, path)
+	}
+}
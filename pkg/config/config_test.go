@@ -1,10 +1,13 @@
 package config
 
 import (
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestConfigManager_LoadSave(t *testing.T) {
@@ -95,15 +98,27 @@ func TestConfigManager_NoConfigFile(t *testing.T) {
 
 func TestConfigManager_HasValidToken(t *testing.T) {
 	tests := []struct {
-		name     string
-		token    string
-		expected bool
+		name      string
+		token     string
+		minLength int
+		expected  bool
 	}{
 		{
 			name:     "valid token",
 			token:    "valid-token-123",
 			expected: true,
 		},
+		{
+			name:     "malformed token",
+			token:    "Bearer valid-token-123",
+			expected: false,
+		},
+		{
+			name:      "short token with lower minimum",
+			token:     "short",
+			minLength: 5,
+			expected:  true,
+		},
 		{
 			name:     "empty token",
 			token:    "",
@@ -130,7 +145,7 @@ func TestConfigManager_HasValidToken(t *testing.T) {
 
 			// Save config if token is provided
 			if tt.token != "" {
-				config := &Config{QuipAPIToken: tt.token}
+				config := &Config{QuipAPIToken: tt.token, MinTokenLength: tt.minLength}
 				err := cm.Save(config)
 				if err != nil {
 					t.Fatalf("Failed to save config: %v", err)
@@ -175,12 +190,6 @@ func TestGetConfigPath(t *testing.T) {
 			homeDir:     "/home/user",
 			expectedEnd: "custom/config/quip-mcp/config.yaml",
 		},
-		{
-			name:        "HOME directory fallback",
-			xdgConfig:   "",
-			homeDir:     "/home/user",
-			expectedEnd: ".config/quip-mcp/config.yaml",
-		},
 	}
 
 	for _, tt := range tests {
@@ -206,3 +215,765 @@ func TestGetConfigPath(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigManager_LogLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileLevel string
+		envLevel  string
+		expected  string
+		wantErr   bool
+	}{
+		{name: "default", expected: ""},
+		{name: "from file", fileLevel: "debug", expected: "debug"},
+		{name: "env overrides file", fileLevel: "debug", envLevel: "error", expected: "error"},
+		{name: "invalid level", envLevel: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_LOG_LEVEL", tt.envLevel)
+
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&Config{QuipAPIToken: "test-token-12345", LogLevel: tt.fileLevel}); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error for invalid log level, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+
+			if cfg.LogLevel != tt.expected {
+				t.Errorf("Expected log level %q, got %q", tt.expected, cfg.LogLevel)
+			}
+		})
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		level    string
+		expected slog.Level
+	}{
+		{level: "", expected: slog.LevelInfo},
+		{level: "DEBUG", expected: slog.LevelDebug},
+		{level: "warn", expected: slog.LevelWarn},
+		{level: "error", expected: slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		level, err := ParseLogLevel(tt.level)
+		if err != nil {
+			t.Errorf("ParseLogLevel(%q) returned error %v", tt.level, err)
+		}
+		if level != tt.expected {
+			t.Errorf("ParseLogLevel(%q) = %v, expected %v", tt.level, level, tt.expected)
+		}
+	}
+}
+
+func TestConfigManager_TokenFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	tokenPath := filepath.Join(tmpDir, "token")
+	if err := os.WriteFile(tokenPath, []byte("  file-secret-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		configFile string
+		envFile    string
+		envToken   string
+		expected   string
+	}{
+		{name: "config token file overrides inline token", configFile: tokenPath, expected: "file-secret-token"},
+		{name: "env token file", envFile: tokenPath, expected: "file-secret-token"},
+		{name: "env token overrides token file", configFile: tokenPath, envToken: "env-token", expected: "env-token"},
+		{name: "inline token without token file", expected: "inline-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", tt.envToken)
+			t.Setenv("QUIP_API_TOKEN_FILE", tt.envFile)
+
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&Config{QuipAPIToken: "inline-token", QuipAPITokenFile: tt.configFile}); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+
+			if cfg.QuipAPIToken != tt.expected {
+				t.Errorf("Expected token %s, got %s", tt.expected, cfg.QuipAPIToken)
+			}
+		})
+	}
+}
+
+func TestConfigManager_TokenFileMissing(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_API_TOKEN_FILE", filepath.Join(t.TempDir(), "does-not-exist"))
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+
+	_, err := cm.Load()
+	if err == nil {
+		t.Fatal("Expected error for missing token file, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "failed to read token file") {
+		t.Errorf("Expected token file error, got %v", err)
+	}
+}
+
+func TestConfigManager_DryRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileValue bool
+		envValue  string
+		expected  bool
+		expectErr bool
+	}{
+		{name: "default off", expected: false},
+		{name: "config file", fileValue: true, expected: true},
+		{name: "env enables", envValue: "true", expected: true},
+		{name: "env overrides config", fileValue: true, envValue: "0", expected: false},
+		{name: "invalid env", envValue: "sometimes", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_DRY_RUN", tt.envValue)
+
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&Config{QuipAPIToken: "test-token-12345", DryRun: tt.fileValue}); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error for invalid QUIP_DRY_RUN, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if cfg.DryRun != tt.expected {
+				t.Errorf("Expected DryRun %t, got %t", tt.expected, cfg.DryRun)
+			}
+		})
+	}
+}
+
+func TestConfigManager_ToolFilter(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_ENABLED_TOOLS", "")
+	t.Setenv("QUIP_DISABLED_TOOLS", " delete_document, ,edit_document ")
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "test-token-12345", EnabledTools: []string{"search_documents"}, DisabledTools: []string{"lock_document"}}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if len(cfg.EnabledTools) != 1 || cfg.EnabledTools[0] != "search_documents" {
+		t.Errorf("Expected enabled tools from the config file, got %v", cfg.EnabledTools)
+	}
+	if len(cfg.DisabledTools) != 2 || cfg.DisabledTools[0] != "delete_document" || cfg.DisabledTools[1] != "edit_document" {
+		t.Errorf("Expected disabled tools from QUIP_DISABLED_TOOLS, got %v", cfg.DisabledTools)
+	}
+}
+
+func TestConfigManager_DefaultLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		expectErr bool
+	}{
+		{name: "unset", config: Config{}},
+		{name: "within bounds", config: Config{DefaultSearchLimit: 25, DefaultRecentLimit: 100}},
+		{name: "search limit too high", config: Config{DefaultSearchLimit: 101}, expectErr: true},
+		{name: "negative recent limit", config: Config{DefaultRecentLimit: -5}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error for an out-of-range limit, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if cfg.DefaultSearchLimit != tt.config.DefaultSearchLimit || cfg.DefaultRecentLimit != tt.config.DefaultRecentLimit {
+				t.Errorf("Expected limits %d/%d, got %d/%d", tt.config.DefaultSearchLimit, tt.config.DefaultRecentLimit, cfg.DefaultSearchLimit, cfg.DefaultRecentLimit)
+			}
+		})
+	}
+}
+
+func TestConfigManager_DeleteConfirmation(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_DELETE_CONFIRMATION", "")
+	t.Setenv("QUIP_DELETE_REQUIRE_TITLE", "")
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "test-token-12345", DeleteConfirmation: "YES, DELETE"}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.DeleteConfirmation != "YES, DELETE" || cfg.DeleteRequireTitle {
+		t.Errorf("Expected the configured phrase without a title requirement, got %q/%v", cfg.DeleteConfirmation, cfg.DeleteRequireTitle)
+	}
+
+	t.Setenv("QUIP_DELETE_REQUIRE_TITLE", "true")
+	if cfg, err = cm.Load(); err != nil || !cfg.DeleteRequireTitle {
+		t.Errorf("Expected QUIP_DELETE_REQUIRE_TITLE to require the title, got %v, %v", cfg, err)
+	}
+
+	t.Setenv("QUIP_DELETE_REQUIRE_TITLE", "sometimes")
+	if _, err := cm.Load(); err == nil {
+		t.Error("Expected error for an invalid QUIP_DELETE_REQUIRE_TITLE, got nil")
+	}
+
+	t.Setenv("QUIP_DELETE_REQUIRE_TITLE", "")
+	t.Setenv("QUIP_DELETE_CONFIRMATION", "   ")
+	if _, err := cm.Load(); err == nil {
+		t.Error("Expected error for a blank confirmation phrase, got nil")
+	}
+}
+
+func TestConfigManager_ToolTimeouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		env       string
+		expectErr bool
+	}{
+		{name: "unset", config: Config{}},
+		{name: "valid", config: Config{ToolTimeout: "45s", ToolTimeouts: map[string]string{"search_documents": "10s", "export_document": "0"}}},
+		{name: "from environment", env: "1m30s"},
+		{name: "not a duration", config: Config{ToolTimeout: "soon"}, expectErr: true},
+		{name: "negative override", config: Config{ToolTimeouts: map[string]string{"search_documents": "-1s"}}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_TOOL_TIMEOUT", tt.env)
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error for an invalid timeout, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+
+			expected := tt.config.ToolTimeout
+			if tt.env != "" {
+				expected = tt.env
+			}
+			if cfg.ToolTimeout != expected || len(cfg.ToolTimeouts) != len(tt.config.ToolTimeouts) {
+				t.Errorf("Expected timeout %q with %d overrides, got %q with %v", expected, len(tt.config.ToolTimeouts), cfg.ToolTimeout, cfg.ToolTimeouts)
+			}
+		})
+	}
+}
+
+func TestConfigManager_OAuth(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_OAUTH_CLIENT_ID", "")
+	t.Setenv("QUIP_OAUTH_CLIENT_SECRET", "env-secret")
+	t.Setenv("QUIP_OAUTH_REFRESH_TOKEN", "")
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "test-token-12345", OAuthRefreshToken: "refresh", OAuthClientID: "client"}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.OAuthRefreshToken != "refresh" || cfg.OAuthClientID != "client" || cfg.OAuthClientSecret != "env-secret" {
+		t.Errorf("Expected OAuth settings from the file and QUIP_OAUTH_CLIENT_SECRET, got %+v", cfg)
+	}
+
+	t.Setenv("QUIP_OAUTH_CLIENT_SECRET", "")
+	if _, err := cm.Load(); err == nil {
+		t.Error("Expected error for a refresh token without client credentials, got nil")
+	}
+}
+
+func TestConfigManager_SaveOAuthRefreshToken(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "env-token-12345")
+	t.Setenv("QUIP_OAUTH_CLIENT_ID", "")
+	t.Setenv("QUIP_OAUTH_CLIENT_SECRET", "")
+	t.Setenv("QUIP_OAUTH_REFRESH_TOKEN", "")
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "file-token-12345", OAuthRefreshToken: "refresh-1", OAuthClientID: "client", OAuthClientSecret: "secret"}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	if err := cm.SaveOAuthRefreshToken("refresh-2"); err != nil {
+		t.Fatalf("Failed to save the refresh token: %v", err)
+	}
+
+	var saved Config
+	if err := cm.loadFromFile(&saved); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	// Only the refresh token changes; QUIP_API_TOKEN stays out of the file
+	if saved.OAuthRefreshToken != "refresh-2" || saved.QuipAPIToken != "file-token-12345" || saved.OAuthClientID != "client" {
+		t.Errorf("Expected only the refresh token to be replaced, got %+v", saved)
+	}
+
+	t.Setenv("QUIP_OAUTH_REFRESH_TOKEN", "refresh-env")
+	if err := cm.SaveOAuthRefreshToken("refresh-3"); err == nil || !strings.Contains(err.Error(), "QUIP_OAUTH_REFRESH_TOKEN") {
+		t.Errorf("Expected an error for a refresh token set in the environment, got %v", err)
+	}
+	if err := NewEnvOnly().SaveOAuthRefreshToken("refresh-3"); err == nil {
+		t.Error("Expected an error in environment-only mode, got nil")
+	}
+}
+
+func TestConfigManager_EnvOnly(t *testing.T) {
+	// A config file at the default location must be ignored
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	fileManager := New()
+	if err := fileManager.Save(&Config{QuipAPIToken: "file-token-12345", DryRun: true}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	t.Setenv("QUIP_CONFIG", EnvOnlyConfig)
+	t.Setenv("QUIP_API_TOKEN", "env-token-12345")
+	t.Setenv("QUIP_BASE_URL", "https://platform.acme.quip.com/1")
+	t.Setenv("QUIP_DEFAULT_SEARCH_LIMIT", "30")
+
+	cm := New()
+	if !cm.EnvOnly() || cm.GetConfigPath() != "" {
+		t.Fatalf("Expected an environment-only manager with no config path, got %+v", cm)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.QuipAPIToken != "env-token-12345" || cfg.DryRun {
+		t.Errorf("Expected only environment settings, got %+v", cfg)
+	}
+	if cfg.BaseURL != "https://platform.acme.quip.com/1" || cfg.DefaultSearchLimit != 30 {
+		t.Errorf("Expected base URL and search limit from the environment, got %+v", cfg)
+	}
+
+	if err := cm.Save(cfg); err == nil {
+		t.Error("Expected Save to fail in environment-only mode")
+	}
+	if err := cm.SetupInteractive(); err == nil {
+		t.Error("Expected SetupInteractive to fail in environment-only mode")
+	}
+
+	// Nothing was written besides the file created before switching modes
+	entries, err := os.ReadDir(filepath.Join(configHome, "quip-mcp"))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Expected only the original config file, got %v (%v)", entries, err)
+	}
+
+	t.Setenv("QUIP_DEFAULT_SEARCH_LIMIT", "many")
+	if _, err := cm.Load(); err == nil {
+		t.Error("Expected error for a non-numeric QUIP_DEFAULT_SEARCH_LIMIT, got nil")
+	}
+}
+
+func TestConfigManager_APIVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		env       string
+		expected  int
+		expectErr bool
+	}{
+		{name: "unset", expected: 0},
+		{name: "v2", config: Config{APIVersion: 2}, expected: 2},
+		{name: "from environment", env: "2", expected: 2},
+		{name: "unknown version", config: Config{APIVersion: 3}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_API_VERSION", tt.env)
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error for an unknown API version, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if cfg.APIVersion != tt.expected {
+				t.Errorf("Expected api_version %d, got %d", tt.expected, cfg.APIVersion)
+			}
+		})
+	}
+}
+
+func TestConfigManager_Markdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		env       string
+		expected  MarkdownConfig
+		expectErr bool
+	}{
+		{name: "unset"},
+		{
+			name:     "from file",
+			config:   Config{Markdown: MarkdownConfig{HeadingStyle: "setext", BulletMarker: "*", Links: "text", Conversion: "server"}},
+			expected: MarkdownConfig{HeadingStyle: "setext", BulletMarker: "*", Links: "text", Conversion: "server"},
+		},
+		{name: "unknown conversion", config: Config{Markdown: MarkdownConfig{Conversion: "both"}}, expectErr: true},
+		{name: "from environment", env: "referenced", expected: MarkdownConfig{Links: "referenced"}},
+		{name: "unknown heading style", config: Config{Markdown: MarkdownConfig{HeadingStyle: "bold"}}, expectErr: true},
+		{name: "unknown link style", env: "footnotes", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_MARKDOWN_LINKS", tt.env)
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error for an invalid markdown setting, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if cfg.Markdown != tt.expected {
+				t.Errorf("Expected markdown settings %+v, got %+v", tt.expected, cfg.Markdown)
+			}
+		})
+	}
+}
+
+func TestConfigManager_DiskCache(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_DISK_CACHE_MB", "")
+	t.Setenv("QUIP_DISK_CACHE_DIR", "")
+
+	dir := t.TempDir()
+	cm := &ConfigManager{configPath: filepath.Join(dir, "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "test-token-12345"}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.DiskCacheMB != 0 || cfg.DiskCacheDir != "" {
+		t.Errorf("Expected the disk cache to be off by default, got %d MB in %q", cfg.DiskCacheMB, cfg.DiskCacheDir)
+	}
+
+	t.Setenv("QUIP_DISK_CACHE_MB", "50")
+	cfg, err = cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.DiskCacheMB != 50 || cfg.DiskCacheDir != filepath.Join(dir, "cache") {
+		t.Errorf("Expected a 50 MB cache next to the config file, got %d MB in %q", cfg.DiskCacheMB, cfg.DiskCacheDir)
+	}
+
+	t.Setenv("QUIP_DISK_CACHE_DIR", "/var/cache/quip-mcp")
+	cfg, err = cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.DiskCacheDir != "/var/cache/quip-mcp" {
+		t.Errorf("Expected QUIP_DISK_CACHE_DIR to set the directory, got %q", cfg.DiskCacheDir)
+	}
+
+	t.Setenv("QUIP_DISK_CACHE_MB", "-1")
+	if _, err := cm.Load(); err == nil {
+		t.Error("Expected an error for a negative cache size")
+	}
+}
+
+func TestConfigManager_CircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            Config
+		threshold         string
+		cooldown          string
+		expectedThreshold *int
+		expectedCooldown  string
+		expectErr         bool
+	}{
+		{name: "defaults"},
+		{name: "from file", config: Config{CircuitBreakerThreshold: intPtr(10), CircuitBreakerCooldown: "1m"}, expectedThreshold: intPtr(10), expectedCooldown: "1m"},
+		{name: "from environment", threshold: "-1", cooldown: "45s", expectedThreshold: intPtr(-1), expectedCooldown: "45s"},
+		{name: "invalid threshold", config: Config{CircuitBreakerThreshold: intPtr(-2)}, expectErr: true},
+		{name: "zero threshold", config: Config{CircuitBreakerThreshold: intPtr(0)}, expectErr: true},
+		{name: "zero threshold from environment", threshold: "0", expectErr: true},
+		{name: "invalid cooldown", cooldown: "soon", expectErr: true},
+		{name: "zero cooldown", cooldown: "0s", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_CIRCUIT_BREAKER_THRESHOLD", tt.threshold)
+			t.Setenv("QUIP_CIRCUIT_BREAKER_COOLDOWN", tt.cooldown)
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if optionalInt(cfg.CircuitBreakerThreshold) != optionalInt(tt.expectedThreshold) || cfg.CircuitBreakerCooldown != tt.expectedCooldown {
+				t.Errorf("Expected threshold %s and cooldown %q, got %s and %q",
+					optionalInt(tt.expectedThreshold), tt.expectedCooldown, optionalInt(cfg.CircuitBreakerThreshold), cfg.CircuitBreakerCooldown)
+			}
+		})
+	}
+}
+
+// intPtr returns a pointer to n, for optional settings
+func intPtr(n int) *int {
+	return &n
+}
+
+// optionalInt renders an optional setting for test messages
+func optionalInt(n *int) string {
+	if n == nil {
+		return "unset"
+	}
+	return strconv.Itoa(*n)
+}
+
+func TestConfigManager_MaxRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		env       string
+		expected  *int
+		expectErr bool
+	}{
+		{name: "default"},
+		{name: "from file", config: Config{MaxRetries: intPtr(5)}, expected: intPtr(5)},
+		{name: "zero from file", config: Config{MaxRetries: intPtr(0)}, expected: intPtr(0)},
+		{name: "disabled from environment", config: Config{MaxRetries: intPtr(5)}, env: "-1", expected: intPtr(-1)},
+		{name: "zero from environment", config: Config{MaxRetries: intPtr(5)}, env: "0", expected: intPtr(0)},
+		{name: "invalid", config: Config{MaxRetries: intPtr(-2)}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_MAX_RETRIES", tt.env)
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			// An explicit 0 (no retries) must survive loading, distinct from leaving it unset
+			if optionalInt(cfg.MaxRetries) != optionalInt(tt.expected) {
+				t.Errorf("Expected max_retries %v, got %v", optionalInt(tt.expected), optionalInt(cfg.MaxRetries))
+			}
+		})
+	}
+}
+
+func TestConfigManager_RatePacingReserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		env       string
+		expected  *int
+		expectErr bool
+	}{
+		{name: "default"},
+		{name: "from file", config: Config{RatePacingReserve: intPtr(5)}, expected: intPtr(5)},
+		{name: "disabled from environment", config: Config{RatePacingReserve: intPtr(5)}, env: "-1", expected: intPtr(-1)},
+		{name: "invalid", config: Config{RatePacingReserve: intPtr(-2)}, expectErr: true},
+		{name: "zero", config: Config{RatePacingReserve: intPtr(0)}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_RATE_PACING_RESERVE", tt.env)
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if optionalInt(cfg.RatePacingReserve) != optionalInt(tt.expected) {
+				t.Errorf("Expected rate_pacing_reserve %s, got %s", optionalInt(tt.expected), optionalInt(cfg.RatePacingReserve))
+			}
+		})
+	}
+}
+
+func TestConfigManager_TimeFormat(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           Config
+		timezone         string
+		dateFormat       string
+		expectedTimezone string
+		expectedFormat   string
+		expectErr        bool
+	}{
+		{name: "defaults"},
+		{name: "from file", config: Config{Timezone: "Asia/Tokyo", DateFormat: "2006-01-02 15:04"}, expectedTimezone: "Asia/Tokyo", expectedFormat: "2006-01-02 15:04"},
+		{name: "from environment", config: Config{Timezone: "Asia/Tokyo"}, timezone: "Europe/Paris", dateFormat: "Jan 2 15:04 MST", expectedTimezone: "Europe/Paris", expectedFormat: "Jan 2 15:04 MST"},
+		{name: "unknown timezone", timezone: "Mars/Olympus_Mons", expectErr: true},
+		{name: "format without time elements", dateFormat: "yesterday", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("QUIP_API_TOKEN", "")
+			t.Setenv("QUIP_TIMEZONE", tt.timezone)
+			t.Setenv("QUIP_DATE_FORMAT", tt.dateFormat)
+
+			tt.config.QuipAPIToken = "test-token-12345"
+			cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+			if err := cm.Save(&tt.config); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			cfg, err := cm.Load()
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if cfg.Timezone != tt.expectedTimezone || cfg.DateFormat != tt.expectedFormat {
+				t.Errorf("Expected timezone %q and format %q, got %q and %q",
+					tt.expectedTimezone, tt.expectedFormat, cfg.Timezone, cfg.DateFormat)
+			}
+		})
+	}
+}
+
+func TestParseTimezone(t *testing.T) {
+	loc, err := ParseTimezone("")
+	if err != nil || loc != time.UTC {
+		t.Errorf("Expected an empty timezone to be UTC, got %v, %v", loc, err)
+	}
+
+	loc, err = ParseTimezone("Asia/Tokyo")
+	if err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("Expected Asia/Tokyo, got %v, %v", loc, err)
+	}
+
+	if _, err := ParseTimezone("JST+9"); err == nil {
+		t.Error("Expected an error for a non-IANA timezone")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows && !darwin
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetConfigPath_Platform(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	expected := filepath.Join(home, ".config", "quip-mcp", "config.yaml")
+	if path := getConfigPath(); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+}
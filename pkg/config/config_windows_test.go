@@ -0,0 +1,18 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetConfigPath_Platform(t *testing.T) {
+	appData := t.TempDir()
+	t.Setenv("AppData", appData)
+	t.Setenv("USERPROFILE", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	expected := filepath.Join(appData, "quip-mcp", "config.yaml")
+	if path := getConfigPath(); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats, selected by file extension
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// formatForPath returns the config format implied by path's extension.
+// Paths without a recognized extension are treated as YAML, the default format.
+func formatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatYAML
+	}
+}
+
+// decodeConfig parses data in the given format into config
+func decodeConfig(format string, data []byte, config *Config) error {
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, config)
+		if err != nil {
+			err = withJSONLine(data, err)
+		}
+	case FormatTOML:
+		_, err = toml.Decode(string(data), config)
+	default:
+		err = yaml.Unmarshal(data, config)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", strings.ToUpper(format), err)
+	}
+	return nil
+}
+
+// encodeConfig serializes config in the given format
+func encodeConfig(format string, config *Config) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return yaml.Marshal(config)
+	}
+}
+
+// withJSONLine adds the line number to encoding/json errors, which only report a byte offset.
+// yaml.v3 and toml errors already name the line.
+func withJSONLine(data []byte, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	return fmt.Errorf("line %d: %w", line, err)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigManager_Formats(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_API_TOKEN_FILE", "")
+	t.Setenv("QUIP_LOG_LEVEL", "")
+
+	tests := []struct {
+		file     string
+		contains string
+	}{
+		{file: "config.yaml", contains: "quip_api_token: test-token-12345"},
+		{file: "config.yml", contains: "quip_api_token: test-token-12345"},
+		{file: "config.json", contains: `"quip_api_token": "test-token-12345"`},
+		{file: "config.toml", contains: `quip_api_token = "test-token-12345"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			cm := NewWithPath(path)
+
+			if err := cm.Save(&Config{QuipAPIToken: "test-token-12345", LogLevel: "debug"}); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read saved config: %v", err)
+			}
+			if !strings.Contains(string(data), tt.contains) {
+				t.Errorf("Expected saved config to contain %q, got:\n%s", tt.contains, data)
+			}
+
+			loaded, err := cm.Load()
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if loaded.QuipAPIToken != "test-token-12345" || loaded.LogLevel != "debug" {
+				t.Errorf("Expected saved values to round-trip, got %+v", loaded)
+			}
+		})
+	}
+}
+
+func TestConfigManager_ParseErrors(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+
+	tests := []struct {
+		file     string
+		content  string
+		expected []string
+	}{
+		{
+			file:     "config.json",
+			content:  "{\n  \"quip_api_token\": \"abc\",\n  \"log_level\": 3\n}\n",
+			expected: []string{"invalid JSON", "line 3"},
+		},
+		{
+			file:     "config.json",
+			content:  "{\n  \"quip_api_token\": \"abc\"\n  \"log_level\": \"info\"\n}\n",
+			expected: []string{"invalid JSON", "line 3"},
+		},
+		{
+			file:     "config.toml",
+			content:  "quip_api_token = \"abc\"\nlog_level = \n",
+			expected: []string{"invalid TOML", "line 2"},
+		},
+		{
+			file:     "config.yaml",
+			content:  "quip_api_token: abc\nlog_level: [debug\n",
+			expected: []string{"invalid YAML", "line"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			_, err := NewWithPath(path).Load()
+			if err == nil {
+				t.Fatal("Expected parse error, got nil")
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
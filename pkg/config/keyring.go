@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Token sources selectable with the token_source config field
+const (
+	TokenSourceFile    = "file"
+	TokenSourceKeyring = "keyring"
+)
+
+// Keyring entry the API token is stored under
+const (
+	keyringService = "quip-mcp"
+	keyringUser    = "quip_api_token"
+)
+
+// loadKeyringToken reads the API token from the OS secret store
+func loadKeyringToken() (string, error) {
+	token, err := keyring.Get(keyringService, keyringUser)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token from keyring: %w", err)
+	}
+	return token, nil
+}
+
+// saveKeyringToken stores the API token in the OS secret store
+func saveKeyringToken(token string) error {
+	if err := keyring.Set(keyringService, keyringUser, token); err != nil {
+		return fmt.Errorf("failed to store token in keyring: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestConfigManager_KeyringTokenSource(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_API_TOKEN_FILE", "")
+	keyring.MockInit()
+
+	if err := saveKeyringToken("keyring-token-12345"); err != nil {
+		t.Fatalf("Failed to store token in keyring: %v", err)
+	}
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "inline-token", TokenSource: TokenSourceKeyring}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.QuipAPIToken != "keyring-token-12345" {
+		t.Errorf("Expected keyring token, got %s", cfg.QuipAPIToken)
+	}
+}
+
+func TestConfigManager_KeyringUnavailable(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_API_TOKEN_FILE", "")
+	keyring.MockInitWithError(errors.New("no secret service available"))
+	defer keyring.MockInit()
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "inline-token", TokenSource: TokenSourceKeyring}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.QuipAPIToken != "inline-token" {
+		t.Errorf("Expected fallback to inline token, got %s", cfg.QuipAPIToken)
+	}
+}
+
+func TestConfigManager_InvalidTokenSource(t *testing.T) {
+	t.Setenv("QUIP_API_TOKEN", "")
+	t.Setenv("QUIP_API_TOKEN_FILE", "")
+
+	cm := &ConfigManager{configPath: filepath.Join(t.TempDir(), "config.yaml")}
+	if err := cm.Save(&Config{QuipAPIToken: "inline-token", TokenSource: "vault"}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	if _, err := cm.Load(); err == nil {
+		t.Fatal("Expected error for invalid token_source, got nil")
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultMinTokenLength is the shortest token ValidateToken accepts when no minimum is configured
+const DefaultMinTokenLength = 10
+
+// tokenChars are the characters that appear in Quip API tokens (base64 segments joined by |)
+const tokenChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+/=|-_."
+
+// ErrTokenTooShort is wrapped by ValidateToken's error when the token is shorter than the
+// minimum length, the one failure min_token_length can resolve
+var ErrTokenTooShort = errors.New("token is too short")
+
+// ValidateToken checks that token has the shape of a Quip API token: at least minLength
+// characters (DefaultMinTokenLength if minLength isn't positive), with no whitespace, quotes or
+// "Bearer " prefix. It catches paste mistakes such as those or a token cut short; whether Quip
+// accepts the token is only known once it is used. Unusual characters are left to
+// CheckTokenCharacters, as a warning.
+func ValidateToken(token string, minLength int) error {
+	if minLength <= 0 {
+		minLength = DefaultMinTokenLength
+	}
+
+	switch {
+	case token == "":
+		return fmt.Errorf("token cannot be empty")
+	case strings.HasPrefix(strings.ToLower(token), "bearer "):
+		return fmt.Errorf("token should not include the \"Bearer \" prefix; use only the token itself")
+	case strings.ContainsAny(token[:1], `"'`) || strings.ContainsAny(token[len(token)-1:], `"'`):
+		return fmt.Errorf("token should not be wrapped in quotes")
+	case strings.IndexFunc(token, unicode.IsSpace) >= 0:
+		return fmt.Errorf("token contains whitespace; check that it was copied as a single line")
+	}
+
+	if len(token) < minLength {
+		return fmt.Errorf("%w: only %d characters, shorter than the minimum of %d; check that it was copied in full", ErrTokenTooShort, len(token), minLength)
+	}
+
+	return nil
+}
+
+// CheckTokenCharacters reports a character that doesn't appear in the Quip tokens seen so far
+// (letters, digits and + / = | - _ .). Token formats can change, so callers should treat the
+// result as a warning rather than reject the token.
+func CheckTokenCharacters(token string) error {
+	if i := strings.IndexFunc(token, func(r rune) bool { return !strings.ContainsRune(tokenChars, r) }); i >= 0 {
+		r := []rune(token[i:])[0]
+		return fmt.Errorf("token contains an unexpected character %q; Quip tokens usually only use letters, digits and + / = | - _ .", r)
+	}
+	return nil
+}
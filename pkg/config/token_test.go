@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		minLength int
+		wantErr   string
+	}{
+		{name: "quip token", token: "T0JBOU1BWmNhSWs=|1735689600|abcDEF123+/xyz="},
+		{name: "simple token", token: "valid-token-123"},
+		{name: "empty", token: "", wantErr: "cannot be empty"},
+		{name: "too short", token: "short", wantErr: "token is too short: only 5 characters, shorter than the minimum of 10"},
+		{name: "configured minimum", token: "abcdef", minLength: 6},
+		{name: "below configured minimum", token: "valid-token-123", minLength: 20, wantErr: "minimum of 20"},
+		{name: "bearer prefix", token: "Bearer valid-token-123", wantErr: "\"Bearer \" prefix"},
+		{name: "double quotes", token: `"valid-token-123"`, wantErr: "wrapped in quotes"},
+		{name: "single quote", token: "valid-token-123'", wantErr: "wrapped in quotes"},
+		{name: "line break", token: "valid-token\n-123", wantErr: "whitespace"},
+		{name: "unexpected characters are not an error", token: "valid-token#123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateToken(tt.token, tt.minLength)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected %q to be valid, got %v", tt.token, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateToken_TooShort(t *testing.T) {
+	if err := ValidateToken("short", 0); !errors.Is(err, ErrTokenTooShort) {
+		t.Errorf("Expected ErrTokenTooShort, got %v", err)
+	}
+	if err := ValidateToken(`"valid-token-123"`, 0); errors.Is(err, ErrTokenTooShort) {
+		t.Errorf("Expected a quoting mistake not to be reported as too short, got %v", err)
+	}
+}
+
+func TestCheckTokenCharacters(t *testing.T) {
+	tests := []struct {
+		token   string
+		wantErr string
+	}{
+		{token: "T0JBOU1BWmNhSWs=|1735689600|abcDEF123+/xyz="},
+		{token: "valid-token#123", wantErr: "unexpected character '#'"},
+		{token: "valid-tokén-123", wantErr: "unexpected character 'é'"},
+	}
+
+	for _, tt := range tests {
+		err := CheckTokenCharacters(tt.token)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Expected %q to pass, got %v", tt.token, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Expected error containing %q for %q, got %v", tt.wantErr, tt.token, err)
+		}
+	}
+}
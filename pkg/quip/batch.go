@@ -0,0 +1,178 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DefaultFetchConcurrency is the number of documents GetDocuments fetches at once when no limit is given
+const DefaultFetchConcurrency = 4
+
+// DefaultMaxConcurrency is how many batch requests a client has in flight at once, across all
+// of its batch operations, unless WithMaxConcurrency says otherwise
+const DefaultMaxConcurrency = 4
+
+// maxThreadsPerRequest is how many IDs GetThreads puts in one /threads/?ids= request
+const maxThreadsPerRequest = 100
+
+// WithMaxConcurrency limits how many requests batch operations (GetDocuments, DeleteDocuments)
+// have in flight at once. The limit is shared by every batch running on the client and its
+// WithContext copies, so parallel tool calls together stay within it; a batch asking for more
+// concurrency than this gets no more. Non-positive values keep DefaultMaxConcurrency.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.batchSlots = make(chan struct{}, n)
+		}
+	}
+}
+
+// DocumentResult is the outcome of fetching one document in a batch
+type DocumentResult struct {
+	ID       string
+	Document *Document
+	Err      error
+}
+
+// DeleteResult is the outcome of deleting one document in a batch
+type DeleteResult struct {
+	ID  string
+	Err error
+}
+
+// GetDocuments fetches several documents concurrently, running at most concurrency requests
+// at a time. Results are returned in the order of ids; a failure on one document is recorded
+// in its result and doesn't stop the others. Once the client's context is done, documents that
+// haven't started yet are reported with the context's error.
+func (c *Client) GetDocuments(ids []string, concurrency int) []DocumentResult {
+	results := make([]DocumentResult, len(ids))
+	c.forEachConcurrently(len(ids), concurrency, func(i int, ctxErr error) {
+		results[i].ID = ids[i]
+		if ctxErr != nil {
+			results[i].Err = ctxErr
+			return
+		}
+		results[i].Document, results[i].Err = c.GetDocument(ids[i])
+	})
+	return results
+}
+
+// GetThreads fetches the metadata of several threads in as few requests as possible, keyed by
+// thread ID. Content is dropped, so this is much lighter than GetDocuments for listings.
+// Threads the user can't access are omitted from the result.
+func (c *Client) GetThreads(ids []string) (map[string]Document, error) {
+	threads := make(map[string]Document, len(ids))
+
+	var pending []string
+	for _, id := range ids {
+		if id != "" {
+			pending = append(pending, id)
+		}
+	}
+
+	for len(pending) > 0 {
+		batch := pending[:min(len(pending), maxThreadsPerRequest)]
+		pending = pending[len(batch):]
+
+		endpoint := fmt.Sprintf("/threads/?ids=%s", url.QueryEscape(strings.Join(batch, ",")))
+		resp, err := c.makeRequest("GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var response RecentThreadsResponse
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for id, data := range response {
+			thread := data.Thread
+			thread.HTML = ""
+			threads[id] = thread
+		}
+	}
+
+	return threads, nil
+}
+
+// DeleteDocuments deletes several documents concurrently with the same options, running at
+// most concurrency requests at a time. Like GetDocuments it returns results in the order of
+// ids and carries on past individual failures.
+func (c *Client) DeleteDocuments(ids []string, opts DeleteOptions, concurrency int) []DeleteResult {
+	results := make([]DeleteResult, len(ids))
+	c.forEachConcurrently(len(ids), concurrency, func(i int, ctxErr error) {
+		results[i].ID = ids[i]
+		if ctxErr != nil {
+			results[i].Err = ctxErr
+			return
+		}
+		results[i].Err = c.DeleteDocumentWithOptions(ids[i], opts)
+	})
+	return results
+}
+
+// forEachConcurrently calls fn for each index below n, at most concurrency (default
+// DefaultFetchConcurrency) at a time and within the client's shared batch limit. Once the
+// client's context is done, indexes that haven't started yet are passed to fn with the
+// context's error instead of nil.
+func (c *Client) forEachConcurrently(n, concurrency int, fn func(i int, ctxErr error)) {
+	if concurrency <= 0 {
+		concurrency = DefaultFetchConcurrency
+	}
+
+	ctx := c.context()
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		if err := acquireSlot(ctx, sem); err != nil {
+			fn(i, err)
+			continue
+		}
+		if err := acquireSlot(ctx, c.batchSlots); err != nil {
+			<-sem
+			fn(i, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				releaseSlot(c.batchSlots)
+				<-sem
+			}()
+
+			fn(i, ctx.Err())
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// acquireSlot takes a slot from slots, waiting until one is free or ctx is done.
+// A nil slots channel means there is no limit.
+func acquireSlot(ctx context.Context, slots chan struct{}) error {
+	if slots == nil {
+		return nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseSlot returns a slot taken by acquireSlot
+func releaseSlot(slots chan struct{}) {
+	if slots != nil {
+		<-slots
+	}
+}
@@ -0,0 +1,233 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_GetDocuments(t *testing.T) {
+	var running, maxRunning int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		id := strings.TrimPrefix(r.URL.Path, "/threads/")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+
+		response := RecentThreadData{Thread: Document{ID: id, Title: "Doc " + id}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	ids := []string{"doc1", "missing", "doc3", "doc4", "doc5"}
+	results := client.GetDocuments(ids, 2)
+
+	if len(results) != len(ids) {
+		t.Fatalf("Expected %d results, got %d", len(ids), len(results))
+	}
+
+	for i, result := range results {
+		if result.ID != ids[i] {
+			t.Errorf("Expected result %d to be for %s, got %s", i, ids[i], result.ID)
+		}
+	}
+
+	if results[1].Err == nil {
+		t.Error("Expected error for missing document, got nil")
+	}
+
+	for _, i := range []int{0, 2, 3, 4} {
+		if results[i].Err != nil || results[i].Document == nil || results[i].Document.ID != ids[i] {
+			t.Errorf("Expected %s to be fetched, got %+v", ids[i], results[i])
+		}
+	}
+
+	if maxRunning > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", maxRunning)
+	}
+}
+
+func TestClient_GetDocuments_CancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request after cancellation, got %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient("test-token").WithContext(ctx)
+	client.baseURL = server.URL
+
+	for _, result := range client.GetDocuments([]string{"doc1", "doc2"}, 1) {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected context.Canceled for %s, got %v", result.ID, result.Err)
+		}
+	}
+}
+
+func TestClient_DeleteDocuments(t *testing.T) {
+	var deleted sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A thread_id Quip doesn't know is looked up once before giving up
+		if r.Method == http.MethodGet && r.URL.Path == "/threads/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path != "/threads/delete" {
+			t.Errorf("Expected path /threads/delete, got %s", r.URL.Path)
+		}
+		if r.FormValue("wipeout") != "true" {
+			t.Errorf("Expected wipeout=true, got %q", r.FormValue("wipeout"))
+		}
+
+		id := r.FormValue("thread_id")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+		deleted.Store(id, true)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	ids := []string{"doc1", "missing", "doc3"}
+	results := client.DeleteDocuments(ids, DeleteOptions{Wipeout: true}, 2)
+
+	if len(results) != len(ids) {
+		t.Fatalf("Expected %d results, got %d", len(ids), len(results))
+	}
+	for i, result := range results {
+		if result.ID != ids[i] {
+			t.Errorf("Expected result %d to be for %s, got %s", i, ids[i], result.ID)
+		}
+	}
+
+	if StatusCode(results[1].Err) != http.StatusNotFound {
+		t.Errorf("Expected a 404 for the missing document, got %v", results[1].Err)
+	}
+	for _, i := range []int{0, 2} {
+		if _, ok := deleted.Load(ids[i]); !ok || results[i].Err != nil {
+			t.Errorf("Expected %s to be deleted, got %v", ids[i], results[i].Err)
+		}
+	}
+}
+
+func TestClient_GetThreads(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/threads/" {
+			t.Errorf("Expected path /threads/, got %s", r.URL.Path)
+		}
+
+		response := RecentThreadsResponse{}
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			if id != "private" {
+				response[id] = RecentThreadData{Thread: Document{ID: id, Title: "Doc " + id, HTML: "<p>body</p>"}}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	ids := []string{"private"}
+	for i := 0; i < maxThreadsPerRequest+5; i++ {
+		ids = append(ids, "doc"+strconv.Itoa(i))
+	}
+
+	threads, err := client.GetThreads(ids)
+	if err != nil {
+		t.Fatalf("GetThreads failed: %v", err)
+	}
+	if len(threads) != len(ids)-1 {
+		t.Errorf("Expected %d threads, got %d", len(ids)-1, len(threads))
+	}
+	if _, ok := threads["private"]; ok {
+		t.Error("Expected the inaccessible thread to be omitted")
+	}
+	if doc := threads["doc3"]; doc.Title != "Doc doc3" || doc.HTML != "" {
+		t.Errorf("Expected doc3's metadata without content, got %+v", doc)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected the IDs to be split over 2 requests, got %d", got)
+	}
+}
+
+func TestClient_MaxConcurrency(t *testing.T) {
+	var running, maxRunning int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		id := strings.TrimPrefix(r.URL.Path, "/threads/")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: id}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL), WithMaxConcurrency(3))
+
+	// Two batches that each ask for more than the limit share it
+	var wg sync.WaitGroup
+	for b := 0; b < 2; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			var ids []string
+			for i := 0; i < 6; i++ {
+				ids = append(ids, "doc"+strconv.Itoa(b)+"-"+strconv.Itoa(i))
+			}
+			for _, result := range client.WithContext(context.Background()).GetDocuments(ids, 10) {
+				if result.Err != nil {
+					t.Errorf("Expected %s to be fetched, got %v", result.ID, result.Err)
+				}
+			}
+		}(b)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxRunning); got > 3 {
+		t.Errorf("Expected at most 3 requests in flight, got %d", got)
+	}
+	if got := atomic.LoadInt32(&maxRunning); got < 2 {
+		t.Errorf("Expected requests to run in parallel, got at most %d at once", got)
+	}
+}
@@ -0,0 +1,99 @@
+package quip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting Quip while the circuit breaker is open
+var ErrCircuitOpen = errors.New("quip is temporarily unavailable after repeated server errors")
+
+// Circuit breaker defaults used by WithCircuitBreaker for non-positive settings
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// circuitBreaker stops requests after threshold consecutive failures (5xx responses or
+// network errors) until cooldown has passed. Then a single trial request is let through:
+// success closes the circuit, failure opens it for another cooldown. It is shared by copies
+// of a Client made with WithContext.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+	now       func() time.Time
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen once threshold requests
+// in a row have failed with a server or network error, for cooldown, so a struggling Quip
+// isn't hammered during an outage. Non-positive values select DefaultBreakerThreshold and
+// DefaultBreakerCooldown. 4xx responses count as Quip being up.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if threshold <= 0 {
+			threshold = DefaultBreakerThreshold
+		}
+		if cooldown <= 0 {
+			cooldown = DefaultBreakerCooldown
+		}
+		c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+	}
+}
+
+// allow reports whether a request may be sent, returning an error wrapping ErrCircuitOpen if not.
+// A nil breaker allows everything.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if now := b.now(); now.Before(b.openUntil) || b.trial {
+		retryIn := max(b.openUntil.Sub(now).Round(time.Second), time.Second)
+		return fmt.Errorf("%w; retry in %s", ErrCircuitOpen, retryIn)
+	}
+
+	// Cooldown is over: let one request through to see whether Quip has recovered
+	b.trial = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a request that was sent: a status of 500 or
+// above, or no response at all, is a failure
+func (b *circuitBreaker) record(status int, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	// Cancelled requests say nothing about Quip's health
+	if status == 0 && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return
+	}
+	if status < 500 && err == nil {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold || !b.openUntil.IsZero() {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,77 @@
+package quip
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var requests, healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "user123"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL), WithCircuitBreaker(3, time.Minute))
+	now := time.Unix(1640995200, 0)
+	client.breaker.now = func() time.Time { return now }
+
+	// Three failures in a row trip the breaker
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetCurrentUser(); StatusCode(err) != http.StatusServiceUnavailable {
+			t.Fatalf("Expected a 503 on attempt %d, got %v", i+1, err)
+		}
+	}
+
+	// While it is open requests fail fast without reaching Quip
+	_, err := client.GetCurrentUser()
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("Expected no request while the circuit is open, got %d requests", got)
+	}
+
+	// After the cooldown a trial request goes through; failing reopens the circuit
+	now = now.Add(time.Minute + time.Second)
+	if _, err := client.GetCurrentUser(); StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the trial request to reach Quip, got %v", err)
+	}
+	if _, err := client.GetCurrentUser(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected a failed trial to reopen the circuit, got %v", err)
+	}
+
+	// Once Quip recovers, a successful trial closes the circuit again
+	atomic.StoreInt32(&healthy, 1)
+	now = now.Add(time.Minute + time.Second)
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCurrentUser(); err != nil {
+			t.Fatalf("Expected requests to succeed after recovery, got %v", err)
+		}
+	}
+}
+
+func TestClient_CircuitBreaker_ClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL), WithCircuitBreaker(2, time.Minute))
+
+	// 4xx responses mean Quip is up and answering, so they never trip the breaker
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetCurrentUser(); StatusCode(err) != http.StatusNotFound {
+			t.Fatalf("Expected a 404 on attempt %d, got %v", i+1, err)
+		}
+	}
+}
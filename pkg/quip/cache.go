@@ -0,0 +1,158 @@
+package quip
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw API payloads by key. Implementations must be safe for concurrent use;
+// MemoryCache is the built-in implementation, and shared stores such as Redis can be
+// plugged in by implementing this interface.
+type Cache interface {
+	// Get returns the cached value and whether it was found and still fresh
+	Get(key string) ([]byte, bool)
+	// Set stores a value that expires after ttl (a zero ttl never expires)
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete removes a value if present
+	Delete(key string)
+}
+
+// maxMemoryCacheEntries bounds how many entries a MemoryCache holds, so a long-running
+// server that reads many documents does not keep every payload forever
+const maxMemoryCacheEntries = 1000
+
+// MemoryCache is an in-process Cache with per-entry expiry and a bounded size
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+	max     int
+}
+
+type cacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+		max:     maxMemoryCacheEntries,
+	}
+}
+
+// Get returns the cached value if it exists and has not expired
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !entry.expiresAt.IsZero() && m.now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+// Set stores a value that expires after ttl. When the cache is full, expired entries are
+// swept first and an arbitrary entry is evicted if that frees nothing.
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[key]; !ok && len(m.entries) >= m.max {
+		m.evict()
+	}
+
+	entry := cacheEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.entries[key] = entry
+}
+
+// evict makes room for one entry; the caller must hold m.mu
+func (m *MemoryCache) evict() {
+	now := m.now()
+	for key, entry := range m.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	if len(m.entries) < m.max {
+		return
+	}
+	// The cost of evicting a live entry is only a refetch
+	for key := range m.entries {
+		delete(m.entries, key)
+		break
+	}
+}
+
+// Delete removes a value from the cache
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+}
+
+// CacheScope identifies the account and Quip instance cached entries belong to, as a short
+// hash of the token and base URL, so the token itself is never written to the store
+func CacheScope(token, baseURL string) string {
+	sum := sha256.Sum256([]byte(token + "\x00" + strings.TrimSuffix(baseURL, "/")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewScopedCache returns a view of cache whose keys are prefixed with scope (see CacheScope).
+// Use it when a persistent store may be shared, so one account's entries are never served
+// to another.
+func NewScopedCache(cache Cache, scope string) Cache {
+	return &scopedCache{cache: cache, prefix: scope + ":"}
+}
+
+// scopedCache prefixes every key with its scope
+type scopedCache struct {
+	cache  Cache
+	prefix string
+}
+
+func (s *scopedCache) Get(key string) ([]byte, bool) {
+	return s.cache.Get(s.prefix + key)
+}
+
+func (s *scopedCache) Set(key string, value []byte, ttl time.Duration) {
+	s.cache.Set(s.prefix+key, value, ttl)
+}
+
+func (s *scopedCache) Delete(key string) {
+	s.cache.Delete(s.prefix + key)
+}
+
+// Cache key helpers
+func userCacheKey(userID string) string {
+	return "user:" + userID
+}
+
+func documentCacheKey(threadID string) string {
+	return "document:" + threadID
+}
+
+func validatorCacheKey(threadID string) string {
+	return "etag:" + threadID
+}
+
+func threadHTMLCacheKey(threadID string, updated int64) string {
+	return fmt.Sprintf("html:%s:%d", threadID, updated)
+}
@@ -0,0 +1,220 @@
+package quip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	cache := NewMemoryCache()
+	now := time.Unix(1640995200, 0)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("short", []byte("a"), time.Minute)
+	cache.Set("forever", []byte("b"), 0)
+
+	if value, ok := cache.Get("short"); !ok || string(value) != "a" {
+		t.Errorf("Expected fresh entry 'a', got %q (found %v)", value, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, ok := cache.Get("short"); ok {
+		t.Error("Expected expired entry to be evicted")
+	}
+
+	if _, ok := cache.Get("forever"); !ok {
+		t.Error("Expected entry without TTL to persist")
+	}
+
+	cache.Delete("forever")
+	if _, ok := cache.Get("forever"); ok {
+		t.Error("Expected deleted entry to be gone")
+	}
+}
+
+func TestMemoryCache_Bounded(t *testing.T) {
+	cache := NewMemoryCache()
+	now := time.Unix(1640995200, 0)
+	cache.now = func() time.Time { return now }
+	cache.max = 3
+
+	cache.Set("expiring", []byte("a"), time.Minute)
+	cache.Set("b", []byte("b"), 0)
+	cache.Set("c", []byte("c"), 0)
+
+	now = now.Add(2 * time.Minute)
+	cache.Set("d", []byte("d"), 0)
+
+	if len(cache.entries) != 3 {
+		t.Fatalf("Expected 3 entries after sweeping, got %d", len(cache.entries))
+	}
+	if _, ok := cache.entries["expiring"]; ok {
+		t.Error("Expected the expired entry to be swept on Set")
+	}
+	for _, key := range []string{"b", "c", "d"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected live entry %q to be kept while an expired one could be swept", key)
+		}
+	}
+
+	cache.Set("e", []byte("e"), 0)
+	if len(cache.entries) != 3 {
+		t.Errorf("Expected the cache to stay at 3 entries, got %d", len(cache.entries))
+	}
+	if _, ok := cache.Get("e"); !ok {
+		t.Error("Expected the newest entry to be stored")
+	}
+
+	cache.Set("e", []byte("e2"), 0)
+	if value, _ := cache.Get("e"); string(value) != "e2" || len(cache.entries) != 3 {
+		t.Errorf("Expected overwriting a key not to evict, got %q with %d entries", value, len(cache.entries))
+	}
+}
+
+func TestClient_GetUser_Cached(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(User{ID: "user123", Name: "John Doe"})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCache(NewMemoryCache(), time.Minute))
+	client.baseURL = server.URL
+
+	for i := 0; i < 3; i++ {
+		user, err := client.GetUser("user123")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if user.Name != "John Doe" {
+			t.Errorf("Expected user name 'John Doe', got %s", user.Name)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected 1 API request, got %d", got)
+	}
+}
+
+func TestClient_GetUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/" {
+			t.Errorf("Expected path /users/, got %s", r.URL.Path)
+		}
+
+		// user1 is already cached, so only user2 and user3 should be requested
+		if ids := r.URL.Query().Get("ids"); ids != "user2,user3" {
+			t.Errorf("Expected ids 'user2,user3', got %s", ids)
+		}
+
+		// user3 is deleted and not returned
+		users := map[string]User{
+			"user2": {ID: "user2", Name: "Jane"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(users)
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	client := NewClient("test-token", WithCache(cache, time.Minute))
+	client.baseURL = server.URL
+	client.cacheSet(userCacheKey("user1"), &User{ID: "user1", Name: "John"})
+
+	users, err := client.GetUsers([]string{"user1", "user2", "user3"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Fatalf("Expected 2 users, got %d", len(users))
+	}
+
+	if users["user1"].Name != "John" || users["user2"].Name != "Jane" {
+		t.Errorf("Unexpected users: %+v", users)
+	}
+
+	if _, ok := cache.Get(userCacheKey("user2")); !ok {
+		t.Error("Expected fetched user to be cached")
+	}
+}
+
+func TestClient_DocumentCache_InvalidatedOnEdit(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/threads/doc123":
+			n := atomic.AddInt32(&fetches, 1)
+			title := "Original"
+			if n > 1 {
+				title = "Edited"
+			}
+			_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: title}})
+		case "/threads/edit-document":
+			_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Edited"}})
+		default:
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithCache(NewMemoryCache(), time.Minute), WithDocumentCache())
+	client.baseURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		doc, err := client.GetDocument("doc123")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if doc.Title != "Original" {
+			t.Errorf("Expected cached title 'Original', got %s", doc.Title)
+		}
+	}
+
+	if _, err := client.EditDocument("doc123", "more", "APPEND", "markdown"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	doc, err := client.GetDocument("doc123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if doc.Title != "Edited" {
+		t.Errorf("Expected refetched title 'Edited', got %s", doc.Title)
+	}
+
+	if got := atomic.LoadInt32(&fetches); got != 2 {
+		t.Errorf("Expected 2 document fetches, got %d", got)
+	}
+}
+
+func TestScopedCache(t *testing.T) {
+	store := NewMemoryCache()
+	alice := NewScopedCache(store, CacheScope("alice-token", BaseURL))
+	bob := NewScopedCache(store, CacheScope("bob-token", BaseURL))
+
+	alice.Set("document:doc123", []byte("secret"), 0)
+	if _, ok := bob.Get("document:doc123"); ok {
+		t.Error("Expected another token's scope to miss")
+	}
+	if got, ok := alice.Get("document:doc123"); !ok || string(got) != "secret" {
+		t.Errorf("Expected the entry in its own scope, got %q, %v", got, ok)
+	}
+
+	if CacheScope("alice-token", BaseURL) == CacheScope("alice-token", "https://platform.example.quip.com/1") {
+		t.Error("Expected the scope to depend on the base URL")
+	}
+	if CacheScope("alice-token", BaseURL) != CacheScope("alice-token", BaseURL+"/") {
+		t.Error("Expected a trailing slash not to change the scope")
+	}
+}
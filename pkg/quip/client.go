@@ -2,11 +2,15 @@ package quip
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,11 +20,125 @@ const (
 	Timeout = 30 * time.Second
 )
 
+// Result limits for search and recent-thread listings. Quip rejects counts above MaxLimit.
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// NormalizeLimit validates a result limit, substituting DefaultLimit for 0 and clamping
+// values above MaxLimit. Negative limits are rejected.
+func NormalizeLimit(limit int) (int, error) {
+	switch {
+	case limit < 0:
+		return 0, fmt.Errorf("invalid limit %d: must be between 1 and %d", limit, MaxLimit)
+	case limit == 0:
+		return DefaultLimit, nil
+	case limit > MaxLimit:
+		return MaxLimit, nil
+	default:
+		return limit, nil
+	}
+}
+
+// DefaultMaxContentBytes is the largest document content the client sends by default
+const DefaultMaxContentBytes = 1 << 20
+
+// DefaultContentFormat is the format CreateDocumentWithOptions and EditDocument send content
+// in when none is given
+const DefaultContentFormat = "markdown"
+
+// Edit locations accepted by /threads/edit-document
+const (
+	locationAppend         = 0
+	locationPrepend        = 1
+	locationAfterSection   = 2
+	locationBeforeSection  = 3
+	locationReplaceSection = 4
+	locationDeleteSection  = 5
+)
+
 // Client represents a Quip API client
 type Client struct {
 	token      string
 	baseURL    string
+	apiVersion int
 	httpClient *http.Client
+
+	cache          Cache
+	cacheTTL       time.Duration
+	cacheDocuments bool
+
+	maxContentBytes int
+	defaultFolderID string
+
+	version         string
+	commit          string
+	userAgentSuffix string
+	userAgent       string
+
+	validators *validatorStore
+	contacts   *contactsCache
+	threadIDs  *threadIDCache
+
+	oauth *oauthTokenSource
+
+	rateLimit *rateLimitTracker
+	pacer     *requestPacer
+	breaker   *circuitBreaker
+	retry     *retryPolicy
+	hooks     Hooks
+
+	// batchSlots bounds the requests in flight across all batch operations
+	batchSlots chan struct{}
+
+	ctx context.Context
+}
+
+// Option configures optional Client behavior
+type Option func(*Client)
+
+// WithBaseURL points the client at a different API root, e.g. a company's
+// https://platform.<company>.quip.com/1 instance
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithCache caches user lookups in the given cache for ttl, along with the HTML of v2
+// document fetches (keyed by updated_usec, so edits are never served stale) and, with
+// WithConditionalRequests, the documents to revalidate by ETag. Entries aren't scoped to the
+// token; wrap a store shared between accounts with NewScopedCache.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// WithDocumentCache additionally caches GetDocument responses. Requires WithCache;
+// cached documents are invalidated whenever the client edits or deletes them.
+func WithDocumentCache() Option {
+	return func(c *Client) {
+		c.cacheDocuments = true
+	}
+}
+
+// WithMaxContentBytes changes the size limit for content sent by CreateDocument and
+// EditDocument (default DefaultMaxContentBytes). Zero or a negative value disables the check.
+func WithMaxContentBytes(n int) Option {
+	return func(c *Client) {
+		c.maxContentBytes = n
+	}
+}
+
+// WithDefaultFolder makes CreateDocumentWithOptions create documents in folderID when the
+// options don't name a folder, so agent-created documents land in one shared place
+func WithDefaultFolder(folderID string) Option {
+	return func(c *Client) {
+		c.defaultFolderID = folderID
+	}
 }
 
 // Document represents a Quip document
@@ -38,8 +156,39 @@ type Document struct {
 	SharedFolderID  string                 `json:"shared_folder_id,omitempty"`
 	ThreadID        string                 `json:"thread_id"`
 	UserIsFollowing bool                   `json:"user_is_following"`
+	EditsDisabled   bool                   `json:"edits_disabled,omitempty"`
+	IsDeleted       bool                   `json:"is_deleted,omitempty"`
 	ExpandedUserIds []string               `json:"expanded_user_ids,omitempty"`
 	AccessLevels    map[string]interface{} `json:"access_levels,omitempty"`
+
+	// Users and Folders are the user and folder objects embedded in an expanded response,
+	// keyed by ID. Only GetDocumentExpanded fills them in.
+	Users   map[string]User   `json:"users,omitempty"`
+	Folders map[string]Folder `json:"folders,omitempty"`
+}
+
+// AccessLevelsByID returns the access level (e.g. OWN, EDIT, COMMENT, VIEW) granted to each
+// user or group ID in AccessLevels. Quip nests each level as {"access_level": "EDIT"}; plain
+// string values are accepted as well. Entries without a level are skipped.
+func (d *Document) AccessLevelsByID() map[string]string {
+	levels := make(map[string]string, len(d.AccessLevels))
+	for id, value := range d.AccessLevels {
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				levels[id] = v
+			}
+		case map[string]interface{}:
+			if level, ok := v["access_level"].(string); ok && level != "" {
+				levels[id] = level
+			}
+		case map[string]string:
+			if level := v["access_level"]; level != "" {
+				levels[id] = level
+			}
+		}
+	}
+	return levels
 }
 
 // User represents a Quip user
@@ -55,6 +204,11 @@ type User struct {
 	ProfilePic string   `json:"profile_picture_url"`
 	Emails     []string `json:"emails,omitempty"`
 	ChatOnly   bool     `json:"chat_only"`
+
+	// Folder IDs; only populated for the current user
+	PrivateFolderID string   `json:"private_folder_id,omitempty"`
+	SharedFolderIDs []string `json:"shared_folder_ids,omitempty"`
+	GroupFolderIDs  []string `json:"group_folder_ids,omitempty"`
 }
 
 // SearchResult represents search results from Quip
@@ -95,13 +249,83 @@ type Comment struct {
 }
 
 // NewClient creates a new Quip API client
-func NewClient(token string) *Client {
-	return &Client{
+func NewClient(token string, opts ...Option) *Client {
+	c := &Client{
 		token:   token,
 		baseURL: BaseURL,
 		httpClient: &http.Client{
 			Timeout: Timeout,
 		},
+		rateLimit:       &rateLimitTracker{},
+		contacts:        &contactsCache{},
+		threadIDs:       &threadIDCache{},
+		maxContentBytes: DefaultMaxContentBytes,
+		batchSlots:      make(chan struct{}, DefaultMaxConcurrency),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.userAgent = c.buildUserAgent()
+	if c.oauth != nil {
+		c.oauth.accessToken = token
+	}
+
+	return c
+}
+
+// cacheGet decodes a cached payload into v, reporting false on a miss or when caching is disabled
+func (c *Client) cacheGet(key string, v interface{}) bool {
+	if c.cache == nil {
+		return false
+	}
+
+	data, ok := c.cache.Get(key)
+	if !ok {
+		return false
+	}
+
+	return json.Unmarshal(data, v) == nil
+}
+
+// cacheSet stores v in the cache if caching is enabled
+func (c *Client) cacheSet(key string, v interface{}) {
+	if c.cache == nil {
+		return
+	}
+
+	if data, err := json.Marshal(v); err == nil {
+		c.cache.Set(key, data, c.cacheTTL)
+	}
+}
+
+// WithContext returns a shallow copy of the client whose API requests are bound to ctx,
+// so they are abandoned when ctx is cancelled or its deadline passes
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// context returns the context requests should be bound to
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// invalidateDocument drops a thread from the cache and remembered ETags after it has been modified
+func (c *Client) invalidateDocument(threadID string) {
+	if threadID == "" {
+		return
+	}
+	if c.cache != nil {
+		c.cache.Delete(documentCacheKey(threadID))
+		c.cache.Delete(validatorCacheKey(threadID))
+	}
+	if c.validators != nil {
+		c.validators.delete(threadID)
 	}
 }
 
@@ -117,31 +341,28 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.R
 	}
 
 	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(c.context(), method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "MCP-Quip-Server/1.0")
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
+	return c.do(req, endpoint)
+}
 
-	if resp.StatusCode >= 400 {
-		defer resp.Body.Close()
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
+// makeFormRequest performs an HTTP request to the Quip API with form-urlencoded body.
+// A thread_id Quip doesn't recognize is retried once as the thread's internal ID.
+func (c *Client) makeFormRequest(method, endpoint string, formData map[string]string) (*http.Response, error) {
+	resp, err := c.sendForm(method, endpoint, formData)
+	if internalID, ok := c.retryWithInternalThreadID(formData["thread_id"], err); ok {
+		return c.sendForm(method, endpoint, withThreadID(formData, internalID))
 	}
-
-	return resp, nil
+	return resp, err
 }
 
-// makeFormRequest performs an HTTP request to the Quip API with form-urlencoded body
-func (c *Client) makeFormRequest(method, endpoint string, formData map[string]string) (*http.Response, error) {
+// sendForm performs a single form-urlencoded request
+func (c *Client) sendForm(method, endpoint string, formData map[string]string) (*http.Response, error) {
 	var reqBody io.Reader
 	if formData != nil {
 		values := url.Values{}
@@ -152,27 +373,104 @@ func (c *Client) makeFormRequest(method, endpoint string, formData map[string]st
 	}
 
 	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(c.context(), method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", "MCP-Quip-Server/1.0")
 
+	return c.do(req, endpoint)
+}
+
+// do authenticates and sends req, recording rate limits and invoking hooks.
+// Responses with status >= 400 are turned into errors. With WithRetry, failures that are safe
+// to repeat are retried. With WithOAuthRefresh, a 401 refreshes the access token and the
+// request is sent once more.
+func (c *Client) do(req *http.Request, endpoint string) (*http.Response, error) {
+	token := c.accessToken()
+	resp, status, err := c.sendWithRetries(req, endpoint, token)
+	if c.oauth == nil || status != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	token, refreshErr := c.refreshAccessToken(req.Context(), token)
+	if refreshErr != nil {
+		return nil, fmt.Errorf("%w (refreshing the access token failed: %v)", err, refreshErr)
+	}
+
+	retry, rewindErr := rewindRequest(req)
+	if rewindErr != nil {
+		return nil, err
+	}
+
+	resp, _, err = c.sendWithRetries(retry, endpoint, token)
+	return resp, err
+}
+
+// send makes a single attempt at req with the given access token, returning the
+// response's status code alongside the result (0 if no response was received)
+func (c *Client) send(req *http.Request, endpoint, token string) (*http.Response, int, error) {
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	if recordDryRun(req, endpoint) {
+		return nil, 0, ErrDryRun
+	}
+
+	endpoint = hookEndpoint(endpoint)
+	if err := c.pace(req.Context()); err != nil {
+		return nil, 0, fmt.Errorf("%s %s: %w", req.Method, endpoint, err)
+	}
+	if err := c.breaker.allow(); err != nil {
+		return nil, 0, fmt.Errorf("%s %s: %w", req.Method, endpoint, err)
+	}
+
+	ctx, endSpan := startRequestSpan(req.Context(), req.Method, endpoint)
+	req = req.WithContext(ctx)
+	if c.hooks.OnRequest != nil {
+		c.hooks.OnRequest(ctx, RequestEvent{Method: req.Method, Endpoint: endpoint})
+	}
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		c.breaker.record(0, err)
+		err = fmt.Errorf("%s %s: failed to make request: %w", req.Method, endpoint, redactURLError(err))
+		c.onResponse(ctx, ResponseEvent{Method: req.Method, Endpoint: endpoint, Duration: time.Since(start), Err: err})
+		endSpan(0, err)
+		return nil, 0, err
+	}
+	c.rateLimit.update(resp)
+	c.breaker.record(resp.StatusCode, nil)
+
+	if err := decompressResponse(resp); err != nil {
+		err = fmt.Errorf("%s %s: %w", req.Method, endpoint, err)
+		c.onResponse(ctx, ResponseEvent{Method: req.Method, Endpoint: endpoint, StatusCode: resp.StatusCode, Duration: time.Since(start), Err: err})
+		endSpan(resp.StatusCode, err)
+		return nil, resp.StatusCode, err
 	}
 
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
+		err = &APIError{Method: req.Method, Endpoint: endpoint, StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+	c.onResponse(ctx, ResponseEvent{Method: req.Method, Endpoint: endpoint, StatusCode: resp.StatusCode, Duration: time.Since(start), Err: err})
+	endSpan(resp.StatusCode, err)
+
+	if err != nil {
+		return nil, resp.StatusCode, err
 	}
+	return resp, resp.StatusCode, nil
+}
 
-	return resp, nil
+// onResponse invokes the OnResponse hook if one is set
+func (c *Client) onResponse(ctx context.Context, event ResponseEvent) {
+	if c.hooks.OnResponse != nil {
+		c.hooks.OnResponse(ctx, event)
+	}
 }
 
 // GetCurrentUser returns information about the current user
@@ -191,14 +489,117 @@ func (c *Client) GetCurrentUser() (*User, error) {
 	return &user, nil
 }
 
+// SearchOptions narrows a document search. Filters are applied client-side to the
+// threads Quip returns, since the search API has no author or date operators.
+type SearchOptions struct {
+	Limit int
+
+	// AuthorID keeps only threads created by this user
+	AuthorID string
+
+	// UpdatedAfter/UpdatedBefore and CreatedAfter/CreatedBefore bound the thread's
+	// update and creation times; zero values are ignored
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// Rank reorders results by a simple relevance heuristic: threads whose title contains
+	// every word of the query come first, then more recently updated threads
+	Rank bool
+
+	// FolderID keeps only threads directly inside this folder (not its subfolders). Quip's
+	// search can't be scoped to a folder, so the folder's thread list is fetched and the top
+	// MaxLimit search results are filtered against it: documents that match but rank below
+	// them in Quip's results are missed, however small the folder.
+	FolderID string
+
+	// IncludeTrashed keeps threads that are in the trash. They are left out by default, so
+	// cleaned-up documents don't resurface in normal searches.
+	IncludeTrashed bool
+}
+
+// searchFilterFetchCount is how many results are requested when filters are set,
+// so that enough threads survive client-side filtering to fill Limit
+const searchFilterFetchCount = 50
+
+// hasFilters reports whether any client-side filter is set
+func (o SearchOptions) hasFilters() bool {
+	return o.AuthorID != "" || o.FolderID != "" ||
+		!o.UpdatedAfter.IsZero() || !o.UpdatedBefore.IsZero() ||
+		!o.CreatedAfter.IsZero() || !o.CreatedBefore.IsZero()
+}
+
+// matches reports whether doc passes every filter in o
+func (o SearchOptions) matches(doc Document) bool {
+	if doc.IsDeleted && !o.IncludeTrashed {
+		return false
+	}
+	if o.AuthorID != "" && doc.AuthorID != o.AuthorID {
+		return false
+	}
+	return inRange(doc.Updated, o.UpdatedAfter, o.UpdatedBefore) &&
+		inRange(doc.Created, o.CreatedAfter, o.CreatedBefore)
+}
+
+// inRange reports whether the microsecond timestamp usec lies within [after, before]
+func inRange(usec int64, after, before time.Time) bool {
+	if !after.IsZero() && usec < after.UnixMicro() {
+		return false
+	}
+	if !before.IsZero() && usec > before.UnixMicro() {
+		return false
+	}
+	return true
+}
+
+// searchEndpoint builds the /threads/search request path for query
+func searchEndpoint(query string, opts SearchOptions) string {
+	params := url.Values{}
+	params.Set("query", query)
+
+	count := opts.Limit
+	if opts.hasFilters() && count < searchFilterFetchCount {
+		count = searchFilterFetchCount
+	}
+	if opts.FolderID != "" {
+		count = MaxLimit
+	}
+	if count > 0 {
+		params.Set("count", strconv.Itoa(count))
+	}
+
+	return "/threads/search?" + params.Encode()
+}
+
 // SearchDocuments searches for documents
 func (c *Client) SearchDocuments(query string, limit int) (*SearchResult, error) {
-	endpoint := fmt.Sprintf("/threads/search?query=%s", url.QueryEscape(query))
-	if limit > 0 {
-		endpoint += fmt.Sprintf("&count=%d", limit)
+	return c.SearchDocumentsWithOptions(query, SearchOptions{Limit: limit})
+}
+
+// SearchDocumentsWithOptions searches for documents, keeping only those that match opts.
+// Quip can return the same thread more than once (e.g. matched in both title and body);
+// duplicates are dropped, keeping the first occurrence.
+func (c *Client) SearchDocumentsWithOptions(query string, opts SearchOptions) (*SearchResult, error) {
+	limit, err := NormalizeLimit(opts.Limit)
+	if err != nil {
+		return nil, err
 	}
+	opts.Limit = limit
 
-	resp, err := c.makeRequest("GET", endpoint, nil)
+	var inFolder map[string]bool
+	if opts.FolderID != "" {
+		folder, err := c.GetFolder(opts.FolderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get folder %s: %w", opts.FolderID, err)
+		}
+		inFolder = make(map[string]bool)
+		for _, id := range folder.ThreadIDs() {
+			inFolder[id] = true
+		}
+	}
+
+	resp, err := c.makeRequest("GET", searchEndpoint(query, opts), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -212,19 +613,98 @@ func (c *Client) SearchDocuments(query string, limit int) (*SearchResult, error)
 
 	// Transform the response to our expected format
 	result := &SearchResult{
-		Documents: make([]Document, len(apiResponse)),
+		Documents: make([]Document, 0, len(apiResponse)),
 		Users:     []User{}, // Search API doesn't return users
 	}
 
-	for i, item := range apiResponse {
-		result.Documents[i] = item.Thread
+	seen := make(map[string]bool, len(apiResponse))
+	for _, item := range apiResponse {
+		if !opts.matches(item.Thread) {
+			continue
+		}
+		if inFolder != nil && !inFolder[item.Thread.ID] {
+			continue
+		}
+		if id := item.Thread.ID; id != "" {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+		result.Documents = append(result.Documents, item.Thread)
+	}
+
+	if opts.Rank {
+		rankSearchResults(query, result.Documents)
+	}
+	if opts.Limit > 0 && len(result.Documents) > opts.Limit {
+		result.Documents = result.Documents[:opts.Limit]
 	}
 
 	return result, nil
 }
 
+// rankSearchResults sorts docs so title matches for query come first, then by most recent update.
+// The sort is stable, so Quip's order breaks ties.
+func rankSearchResults(query string, docs []Document) {
+	words := strings.Fields(strings.ToLower(query))
+	titleMatch := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		titleMatch[doc.ID] = titleContainsAll(doc.Title, words)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		if mi, mj := titleMatch[docs[i].ID], titleMatch[docs[j].ID]; mi != mj {
+			return mi
+		}
+		return docs[i].Updated > docs[j].Updated
+	})
+}
+
+// titleContainsAll reports whether title contains every one of the lowercase words
+func titleContainsAll(title string, words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	title = strings.ToLower(title)
+	for _, word := range words {
+		if !strings.Contains(title, word) {
+			return false
+		}
+	}
+	return true
+}
+
 // GetDocument retrieves a document by ID using v1 API and includes HTML content
 func (c *Client) GetDocument(id string) (*Document, error) {
+	if c.cacheDocuments {
+		var cached Document
+		if c.cacheGet(documentCacheKey(id), &cached) {
+			return &cached, nil
+		}
+	}
+
+	doc, err := c.fetchDocument(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cacheDocuments {
+		c.cacheSet(documentCacheKey(id), doc)
+	}
+
+	return doc, nil
+}
+
+// fetchDocument retrieves a document from the API, bypassing the cache
+func (c *Client) fetchDocument(id string) (*Document, error) {
+	if c.APIVersion() == APIVersion2 {
+		return c.fetchDocumentV2(id)
+	}
+	if c.validators != nil {
+		return c.fetchDocumentConditional(id)
+	}
+
 	// Use v1 API to get document with HTML content
 	endpoint := fmt.Sprintf("/threads/%s", id)
 
@@ -234,37 +714,73 @@ func (c *Client) GetDocument(id string) (*Document, error) {
 	}
 	defer resp.Body.Close()
 
-	// Read the response body to check the structure
-	respBody, err := io.ReadAll(resp.Body)
+	data, err := readBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
+	return decodeDocument(data)
+}
 
-	// Try to decode as the complex structure first (like CreateDocument and GetRecentThreads)
-	var response RecentThreadData
-	if err := json.Unmarshal(respBody, &response); err == nil && response.Thread.ID != "" {
-		// The HTML content is in the response.HTML field, not response.Thread.HTML
-		if response.HTML != "" {
-			response.Thread.HTML = response.HTML
+// readBody reads a response body, allocating the buffer once when the length is known
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.ContentLength <= 0 {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
 		}
-		return &response.Thread, nil
+		return data, nil
 	}
 
-	// Fallback to direct document structure
-	var doc Document
-	if err := json.Unmarshal(respBody, &doc); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	data := make([]byte, resp.ContentLength)
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	return data, nil
+}
 
-	return &doc, nil
+// decodeDocument decodes a thread response body, which may be either nested thread data or a bare document.
+// Thread data, the usual shape, is decoded in a single pass.
+func decodeDocument(data []byte) (*Document, error) {
+	return decodeShapes(data, threadDataShape, bareDocumentShape)
 }
 
 // CreateDocument creates a new document
 func (c *Client) CreateDocument(title, content string) (*Document, error) {
+	return c.CreateDocumentWithOptions(title, content, CreateOptions{})
+}
+
+// CreateOptions controls how CreateDocumentWithOptions creates a document
+type CreateOptions struct {
+	// Format of the content: "markdown" or "html" (default: DefaultContentFormat)
+	Format string
+	// Type of thread to create: "document" (default) or "spreadsheet"
+	Type string
+	// FolderID is the folder to create the document in (default: the WithDefaultFolder
+	// folder, or else the user's private folder)
+	FolderID string
+}
+
+// CreateDocumentWithOptions creates a new document from content in the given format
+func (c *Client) CreateDocumentWithOptions(title, content string, opts CreateOptions) (*Document, error) {
+	if err := c.checkContentSize(content); err != nil {
+		return nil, err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = DefaultContentFormat
+	}
+
 	formData := map[string]string{
 		"title":   title,
 		"content": content,
-		"format":  "markdown",
+		"format":  format,
+	}
+	if opts.Type != "" {
+		formData["type"] = opts.Type
+	}
+	if folderID := cmp.Or(opts.FolderID, c.defaultFolderID); folderID != "" {
+		formData["member_ids"] = folderID
 	}
 
 	resp, err := c.makeFormRequest("POST", "/threads/new-document", formData)
@@ -288,11 +804,120 @@ func (c *Client) CreateDocument(title, content string) (*Document, error) {
 	return &response.Thread, nil
 }
 
+// CreateSpreadsheet creates a new spreadsheet. content is an HTML <table> whose rows become
+// the first sheet's rows; it may be empty for a blank spreadsheet.
+func (c *Client) CreateSpreadsheet(title, content string) (*Document, error) {
+	return c.CreateDocumentWithOptions(title, content, CreateOptions{Format: "html", Type: "spreadsheet"})
+}
+
+// CommentOptions controls paging through a thread's comments
+type CommentOptions struct {
+	// Count is the maximum number of comments to return (Quip's default page size when 0)
+	Count int
+	// MaxCreatedUsec only returns comments created before this timestamp; use it as the paging cursor
+	MaxCreatedUsec int64
+	// LastUpdatedSinceUsec only returns comments updated after this timestamp
+	LastUpdatedSinceUsec int64
+	// MinCreatedUsec only returns comments created after this timestamp; it is the paging cursor
+	// for ascending pages. Quip has no such filter, so it is sent as last_updated_since_usec (a
+	// comment is never updated before it was created) and older comments edited since are dropped.
+	MinCreatedUsec int64
+	// SortedBy orders the results by creation time: "ASC" or "DESC" (Quip's default)
+	SortedBy string
+
+	// editedBefore is how many older, since edited comments an ascending page is expected to
+	// get back and drop; that many extra are requested so they can't crowd out newer ones
+	editedBefore int
+}
+
+// ascending reports whether opts asks for the oldest comments first
+func (opts CommentOptions) ascending() bool {
+	return strings.EqualFold(opts.SortedBy, "ASC")
+}
+
+// ContinueFrom sets opts up to read the page after the one that returned cursor as its
+// NextCursor: newer comments when sorted ascending, older ones otherwise. Ascending cursors
+// have the form "<created_usec>:<edited comments>"; descending ones are a timestamp.
+func (opts *CommentOptions) ContinueFrom(cursor string) error {
+	if !opts.ascending() {
+		usec, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || usec < 0 {
+			return fmt.Errorf("%q is not a cursor for newest-first pages", cursor)
+		}
+		opts.MaxCreatedUsec = usec
+		return nil
+	}
+
+	created, edited, _ := strings.Cut(cursor, ":")
+	usec, err := strconv.ParseInt(created, 10, 64)
+	if err != nil || usec < 0 {
+		return fmt.Errorf("%q is not a cursor for oldest-first pages", cursor)
+	}
+	skip := 0
+	if edited != "" {
+		if skip, err = strconv.Atoi(edited); err != nil || skip < 0 {
+			return fmt.Errorf("%q is not a cursor for oldest-first pages", cursor)
+		}
+	}
+	opts.MinCreatedUsec = usec
+	opts.editedBefore = skip
+	return nil
+}
+
+// CommentPage is a single page of comments along with the cursor for the next page
+type CommentPage struct {
+	Comments []Comment
+	// NextCursor continues in the same sort order (see CommentOptions.ContinueFrom), or is empty
+	// if there are no more comments
+	NextCursor string
+}
+
 // GetDocumentComments retrieves comments for a document
 func (c *Client) GetDocumentComments(documentID string) ([]Comment, error) {
-	endpoint := fmt.Sprintf("/threads/%s/messages", documentID)
+	page, err := c.GetDocumentCommentsPage(documentID, CommentOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Comments, nil
+}
+
+// GetDocumentCommentsPage retrieves a single page of comments for a document
+func (c *Client) GetDocumentCommentsPage(documentID string, opts CommentOptions) (*CommentPage, error) {
+	return c.GetThreadMessages(documentID, opts)
+}
+
+// GetThreadMessages retrieves a single page of a thread's messages: the conversation of a
+// chat thread, or the comments on a document or spreadsheet
+func (c *Client) GetThreadMessages(threadID string, opts CommentOptions) (*CommentPage, error) {
+	endpoint := fmt.Sprintf("/threads/%s/messages", threadID)
+
+	// Ascending pages ask for enough extra comments to cover the edited ones they drop
+	count := opts.Count
+	if count > 0 && opts.MinCreatedUsec > 0 {
+		count += opts.editedBefore
+	}
+
+	params := url.Values{}
+	if count > 0 {
+		params.Set("count", strconv.Itoa(count))
+	}
+	if opts.MaxCreatedUsec > 0 {
+		params.Set("max_created_usec", strconv.FormatInt(opts.MaxCreatedUsec, 10))
+	}
+	if updatedSince := max(opts.LastUpdatedSinceUsec, opts.MinCreatedUsec); updatedSince > 0 {
+		params.Set("last_updated_since_usec", strconv.FormatInt(updatedSince, 10))
+	}
+	if opts.SortedBy != "" {
+		params.Set("sorted_by", strings.ToUpper(opts.SortedBy))
+	}
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
 
 	resp, err := c.makeRequest("GET", endpoint, nil)
+	if internalID, ok := c.retryWithInternalThreadID(threadID, err); ok {
+		return c.GetThreadMessages(internalID, opts)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -303,68 +928,199 @@ func (c *Client) GetDocumentComments(documentID string) ([]Comment, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return comments, nil
+	page := &CommentPage{Comments: comments}
+	if opts.MinCreatedUsec > 0 {
+		// Comments on earlier pages that were edited since come back too; leave them out
+		page.Comments = make([]Comment, 0, len(comments))
+		for _, comment := range comments {
+			if comment.Created > opts.MinCreatedUsec {
+				page.Comments = append(page.Comments, comment)
+			}
+		}
+	}
+	trimmed := opts.Count > 0 && len(page.Comments) > opts.Count
+	if trimmed {
+		page.Comments = page.Comments[:opts.Count]
+	}
+
+	// A full page means there may be more comments; continue past the last one we returned
+	if trimmed || (count > 0 && len(comments) >= count) {
+		page.NextCursor = nextCommentCursor(comments, page.Comments, opts)
+	}
+
+	return page, nil
 }
 
-// EditDocument edits an existing document
+// nextCommentCursor returns the cursor that continues after a full page: just before the
+// oldest comment for descending pages. Ascending pages continue after the newest comment
+// returned and count the comments Quip will send again because they were updated after it.
+func nextCommentCursor(received, returned []Comment, opts CommentOptions) string {
+	if !opts.ascending() {
+		oldest := received[0].Created
+		for _, comment := range received[1:] {
+			oldest = min(oldest, comment.Created)
+		}
+		return strconv.FormatInt(max(oldest-1, 0), 10)
+	}
+
+	// The creation time only advances past comments that were returned. A page of nothing but
+	// edited older comments keeps it and asks for more next time, so the newer comments
+	// behind them aren't skipped.
+	created := opts.MinCreatedUsec
+	for _, comment := range returned {
+		created = max(created, comment.Created)
+	}
+	updatedSince := max(opts.LastUpdatedSinceUsec, created)
+	edited := 0
+	for _, comment := range received {
+		if comment.Created <= created && comment.Updated > updatedSince {
+			edited++
+		}
+	}
+	return fmt.Sprintf("%d:%d", created, edited)
+}
+
+// EditDocument edits an existing document with operation REPLACE, APPEND or PREPEND (empty
+// means REPLACE); any other operation is an error. An empty format sends DefaultContentFormat.
 func (c *Client) EditDocument(documentID, content, operation, format string) (*Document, error) {
+	if err := c.checkContentSize(content); err != nil {
+		return nil, err
+	}
+
 	formData := map[string]string{
 		"thread_id": documentID,
 		"content":   content,
 	}
 
-	// Convert operation to location parameter as per Quip API v1
-	// For now, map "REPLACE" to location=0 (APPEND)
-	// In a full implementation, we'd need section_id for true replacement
-	if operation == "REPLACE" || operation == "" {
-		formData["location"] = "0" // APPEND - adds to end of document
-	} else if operation == "APPEND" {
-		formData["location"] = "0" // APPEND
-	} else if operation == "PREPEND" {
-		formData["location"] = "1" // PREPEND
-	} else {
-		formData["location"] = "0" // Default to APPEND
+	// Quip has no whole-document replace, so REPLACE appends like APPEND; replacing a
+	// document's content needs section IDs (see ReplaceSectionContent)
+	switch strings.ToUpper(operation) {
+	case "", "REPLACE", "APPEND":
+		formData["location"] = strconv.Itoa(locationAppend)
+	case "PREPEND":
+		formData["location"] = strconv.Itoa(locationPrepend)
+	default:
+		return nil, fmt.Errorf("invalid operation %q: must be REPLACE, APPEND or PREPEND", operation)
 	}
 
-	if format != "" {
-		formData["format"] = format
-	} else {
-		formData["format"] = "markdown"
+	if format == "" {
+		format = DefaultContentFormat
 	}
+	formData["format"] = format
+
+	return c.postThreadForm("/threads/edit-document", formData)
+}
+
+// BaseURL returns the API root the client sends requests to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
 
-	endpoint := "/threads/edit-document"
+// DefaultFolderID returns the folder documents are created in when none is given, or "" if
+// WithDefaultFolder wasn't used
+func (c *Client) DefaultFolderID() string {
+	return c.defaultFolderID
+}
+
+// MaxContentBytes returns the content size limit enforced by CreateDocument and EditDocument,
+// or 0 if there is none
+func (c *Client) MaxContentBytes() int {
+	return max(c.maxContentBytes, 0)
+}
+
+// checkContentSize rejects content larger than the configured limit before it is sent,
+// since Quip's own error for oversized content doesn't say what went wrong
+func (c *Client) checkContentSize(content string) error {
+	if c.maxContentBytes > 0 && len(content) > c.maxContentBytes {
+		return fmt.Errorf("content is %d bytes, over the %d-byte limit; split it into several smaller edits", len(content), c.maxContentBytes)
+	}
+	return nil
+}
+
+// AppendToDocument adds content to the end of a document, leaving existing content untouched
+func (c *Client) AppendToDocument(documentID, content, format string) (*Document, error) {
+	return c.EditDocument(documentID, content, "APPEND", format)
+}
+
+// PrependToDocument adds content to the start of a document, leaving existing content untouched
+func (c *Client) PrependToDocument(documentID, content, format string) (*Document, error) {
+	return c.EditDocument(documentID, content, "PREPEND", format)
+}
+
+// postThreadForm posts a form to a thread-modifying endpoint and decodes the updated thread
+func (c *Client) postThreadForm(endpoint string, formData map[string]string) (*Document, error) {
+	doc, _, err := c.postThreadFormEcho(endpoint, formData, "")
+	return doc, err
+}
+
+// postThreadFormEcho is postThreadForm for changes whose result must be confirmed: it also
+// reports whether the response's thread carried field. A flag that is missing decodes as
+// false, so only an echoed field says what Quip did.
+func (c *Client) postThreadFormEcho(endpoint string, formData map[string]string, field string) (*Document, bool, error) {
 	resp, err := c.makeFormRequest("POST", endpoint, formData)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 
-	// The edit-document API returns the same complex structure as other APIs
-	respBody, err := io.ReadAll(resp.Body)
+	c.invalidateDocument(formData["thread_id"])
+
+	// Thread-modifying APIs return the same complex structure as other APIs
+	respBody, err := readBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, false, err
 	}
 
-	// Try to decode as the complex structure first
-	var response RecentThreadData
-	if err := json.Unmarshal(respBody, &response); err == nil && response.Thread.ID != "" {
-		return &response.Thread, nil
+	// Some endpoints acknowledge with an empty body; callers fill in what they know
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return &Document{}, false, nil
 	}
 
-	// Fallback to direct document structure
-	var doc Document
-	if err := json.Unmarshal(respBody, &doc); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	doc, err := decodeDocument(respBody)
+	if err != nil {
+		return nil, false, err
 	}
+	return doc, doc.ID != "" && threadFieldEchoed(respBody, field), nil
+}
 
-	return &doc, nil
+// threadFieldEchoed reports whether a thread response carries field, on the thread nested
+// under "thread" or on the document itself
+func threadFieldEchoed(body []byte, field string) bool {
+	if field == "" {
+		return false
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return false
+	}
+	if thread, ok := envelope["thread"]; ok {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(thread, &fields); err == nil {
+			if _, ok := fields[field]; ok {
+				return true
+			}
+		}
+	}
+	_, ok := envelope[field]
+	return ok
 }
 
-// DeleteDocument deletes a document
+// DeleteDocument moves a document to the trash, from where it can still be restored
 func (c *Client) DeleteDocument(documentID string) error {
+	return c.DeleteDocumentWithOptions(documentID, DeleteOptions{})
+}
+
+// DeleteOptions controls how DeleteDocumentWithOptions removes a document
+type DeleteOptions struct {
+	// Wipeout deletes the document permanently instead of moving it to the trash
+	Wipeout bool
+}
+
+// DeleteDocumentWithOptions deletes a document, permanently if opts.Wipeout is set
+func (c *Client) DeleteDocumentWithOptions(documentID string, opts DeleteOptions) error {
 	formData := map[string]string{
 		"thread_id": documentID,
-		"wipeout":   "false", // Set to true for permanent deletion
+		"wipeout":   strconv.FormatBool(opts.Wipeout),
 	}
 	endpoint := "/threads/delete"
 	resp, err := c.makeFormRequest("POST", endpoint, formData)
@@ -373,15 +1129,65 @@ func (c *Client) DeleteDocument(documentID string) error {
 	}
 	defer resp.Body.Close()
 
+	c.invalidateDocument(documentID)
+
 	return nil
 }
 
-// GetRecentThreads retrieves recent threads for the current user
+// GetRecentThreads retrieves the current user's most recently updated threads, newest first
 func (c *Client) GetRecentThreads(limit int) ([]Document, error) {
-	endpoint := "/threads/recent"
-	if limit > 0 {
-		endpoint += fmt.Sprintf("?count=%d", limit)
+	page, err := c.GetRecentThreadsWithOptions(RecentOptions{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Threads, nil
+}
+
+// RecentOptions controls which recent threads GetRecentThreadsWithOptions returns
+type RecentOptions struct {
+	// Limit is the maximum number of threads to return (DefaultLimit when 0)
+	Limit int
+	// Type only returns threads of this type, e.g. "document", "spreadsheet" or "chat".
+	// Empty or "all" returns every type.
+	Type string
+	// MaxUpdatedUsec only returns threads updated before this timestamp; use it as the paging cursor
+	MaxUpdatedUsec int64
+	// IncludeTrashed keeps threads that are in the trash (default: they are left out)
+	IncludeTrashed bool
+}
+
+// filtersType reports whether threads are filtered by type
+func (o RecentOptions) filtersType() bool {
+	return o.Type != "" && !strings.EqualFold(o.Type, "all")
+}
+
+// RecentPage is a page of recent threads along with the cursor for the next page
+type RecentPage struct {
+	Threads []Document
+	// NextCursor is the MaxUpdatedUsec to request for the next (older) page, or 0 if there are no more
+	NextCursor int64
+}
+
+// GetRecentThreadsWithOptions retrieves a page of recent threads, newest first. Quip can't
+// filter by type or trash status, so when Type is set more threads are requested and
+// filtered client-side; trashed threads are dropped the same way unless IncludeTrashed is set.
+func (c *Client) GetRecentThreadsWithOptions(opts RecentOptions) (*RecentPage, error) {
+	limit, err := NormalizeLimit(opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	count := limit
+	if opts.filtersType() && count < searchFilterFetchCount {
+		count = searchFilterFetchCount
+	}
+
+	params := url.Values{}
+	params.Set("count", strconv.Itoa(count))
+	if opts.MaxUpdatedUsec > 0 {
+		params.Set("max_updated_usec", strconv.FormatInt(opts.MaxUpdatedUsec, 10))
 	}
+	endpoint := "/threads/recent?" + params.Encode()
 
 	resp, err := c.makeRequest("GET", endpoint, nil)
 	if err != nil {
@@ -390,43 +1196,57 @@ func (c *Client) GetRecentThreads(limit int) ([]Document, error) {
 	defer resp.Body.Close()
 
 	// Read the response body to determine the structure
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := readBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	// Try to decode as the complex map response structure
-	var response RecentThreadsResponse
-	if err := json.Unmarshal(respBody, &response); err == nil && len(response) > 0 {
-		// Convert the map to an array of documents
-		threads := make([]Document, 0, len(response))
-		for _, threadData := range response {
-			threads = append(threads, threadData.Thread)
-		}
-		return threads, nil
+	threads, err := decodeRecentThreads(respBody)
+	if err != nil {
+		return nil, err
 	}
 
-	// If that fails, try to decode as direct array of documents (fallback)
-	var threads []Document
-	if err := json.Unmarshal(respBody, &threads); err == nil {
-		return threads, nil
-	}
+	// The map-shaped response has no inherent order
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].Updated > threads[j].Updated
+	})
 
-	// If both fail, try to decode as array of SearchResponse objects (similar to search API)
-	var searchResponses []SearchResponse
-	if err := json.Unmarshal(respBody, &searchResponses); err == nil {
-		threads := make([]Document, len(searchResponses))
-		for i, item := range searchResponses {
-			threads[i] = item.Thread
+	page := &RecentPage{}
+	for _, thread := range threads {
+		if opts.filtersType() && !strings.EqualFold(thread.Type, opts.Type) {
+			continue
+		}
+		if thread.IsDeleted && !opts.IncludeTrashed {
+			continue
+		}
+		if len(page.Threads) == limit {
+			// Threads were left over: continue right after the last one returned
+			page.NextCursor = page.Threads[limit-1].Updated - 1
+			return page, nil
 		}
-		return threads, nil
+		page.Threads = append(page.Threads, thread)
 	}
 
-	return nil, fmt.Errorf("failed to decode response: unrecognized response format. Response body: %s", string(respBody))
+	// A full page means there may be older threads; continue from the oldest one we saw
+	if len(threads) >= count && threads[len(threads)-1].Updated > 0 {
+		page.NextCursor = threads[len(threads)-1].Updated - 1
+	}
+
+	return page, nil
+}
+
+// decodeRecentThreads decodes a /threads/recent response, which Quip has returned in several shapes
+func decodeRecentThreads(respBody []byte) ([]Document, error) {
+	return decodeShapes(respBody, threadMapShape, documentListShape, searchResponseListShape)
 }
 
 // GetUser retrieves user information by ID
 func (c *Client) GetUser(userID string) (*User, error) {
+	var cached User
+	if c.cacheGet(userCacheKey(userID), &cached) {
+		return &cached, nil
+	}
+
 	endpoint := fmt.Sprintf("/users/%s", userID)
 
 	resp, err := c.makeRequest("GET", endpoint, nil)
@@ -440,5 +1260,147 @@ func (c *Client) GetUser(userID string) (*User, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	c.cacheSet(userCacheKey(userID), &user)
+
 	return &user, nil
 }
+
+// GetUsers retrieves several users in a single request, keyed by user ID.
+// IDs that Quip doesn't return (e.g. deleted users) are absent from the result.
+func (c *Client) GetUsers(userIDs []string) (map[string]User, error) {
+	users := make(map[string]User, len(userIDs))
+
+	var missing []string
+	for _, id := range userIDs {
+		if id == "" {
+			continue
+		}
+		var cached User
+		if c.cacheGet(userCacheKey(id), &cached) {
+			users[id] = cached
+			continue
+		}
+		missing = append(missing, id)
+	}
+
+	if len(missing) == 0 {
+		return users, nil
+	}
+
+	endpoint := fmt.Sprintf("/users/?ids=%s", url.QueryEscape(strings.Join(missing, ",")))
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var fetched map[string]User
+	if err := json.NewDecoder(resp.Body).Decode(&fetched); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for id, user := range fetched {
+		users[id] = user
+		c.cacheSet(userCacheKey(id), &user)
+	}
+
+	return users, nil
+}
+
+// FollowThread starts following a thread so the current user gets notified of changes
+func (c *Client) FollowThread(threadID string) (*Document, error) {
+	return c.setFollowing(threadID, true)
+}
+
+// UnfollowThread stops following a thread
+func (c *Client) UnfollowThread(threadID string) (*Document, error) {
+	return c.setFollowing(threadID, false)
+}
+
+// setFollowing updates the current user's following state for a thread and returns the thread
+// with its resulting state as reported by Quip. Callers should check UserIsFollowing: Quip may
+// accept the request without changing it.
+func (c *Client) setFollowing(threadID string, follow bool) (*Document, error) {
+	endpoint := "/threads/unfollow"
+	if follow {
+		endpoint = "/threads/follow"
+	}
+
+	formData := map[string]string{
+		"thread_id": threadID,
+	}
+
+	doc, echoed, err := c.postThreadFormEcho(endpoint, formData, "user_is_following")
+	if err != nil {
+		return nil, err
+	}
+
+	// Some responses omit the thread or the flag, so read the state back unless the response
+	// confirms the one asked for
+	if !echoed || doc.UserIsFollowing != follow {
+		if doc, err = c.fetchDocument(threadID); err != nil {
+			return nil, fmt.Errorf("follow request sent, but reading the following state back failed: %w", err)
+		}
+	}
+	if doc.ID == "" {
+		doc.ID = threadID
+	}
+
+	return doc, nil
+}
+
+// LockEdits locks (or unlocks) a thread so that nobody else can edit it, and returns the
+// thread with its resulting lock state as reported by Quip. Callers should check
+// EditsDisabled: Quip may accept the request without changing the lock.
+func (c *Client) LockEdits(threadID string, locked bool) (*Document, error) {
+	formData := map[string]string{
+		"thread_id":      threadID,
+		"edits_disabled": strconv.FormatBool(locked),
+	}
+
+	doc, echoed, err := c.postThreadFormEcho("/threads/lock-edits", formData, "edits_disabled")
+	if err != nil {
+		return nil, err
+	}
+
+	// The response doesn't reliably echo the lock state, so read it back unless it confirms the
+	// one asked for
+	if !echoed || doc.EditsDisabled != locked {
+		if doc, err = c.fetchDocument(threadID); err != nil {
+			return nil, fmt.Errorf("lock request sent, but reading the lock state back failed: %w", err)
+		}
+	}
+	if doc.ID == "" {
+		doc.ID = threadID
+	}
+
+	return doc, nil
+}
+
+// RenameDocument changes a document's title without touching its content, and returns the
+// renamed thread
+func (c *Client) RenameDocument(threadID, newTitle string) (*Document, error) {
+	newTitle = strings.TrimSpace(newTitle)
+	if newTitle == "" {
+		return nil, fmt.Errorf("a new title is required")
+	}
+
+	formData := map[string]string{
+		"thread_id": threadID,
+		"title":     newTitle,
+	}
+
+	doc, err := c.postThreadForm("/threads/edit-document", formData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in what we asked for when the response doesn't echo the thread
+	if doc.ID == "" {
+		doc.ID = threadID
+		doc.Title = newTitle
+	}
+
+	return doc, nil
+}
@@ -1,10 +1,16 @@
 package quip
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -95,7 +101,7 @@ func TestClient_GetCurrentUser_Error(t *testing.T) {
 		t.Fatal("Expected error, got nil")
 	}
 
-	expectedError := "API error 401: {\"error\": \"Invalid token\"}"
+	expectedError := "GET /users/current: API error 401: {\"error\": \"Invalid token\"}"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error %s, got %s", expectedError, err.Error())
 	}
@@ -456,6 +462,11 @@ func TestClient_EditDocument(t *testing.T) {
 	if doc.Title != "Updated Document" {
 		t.Errorf("Expected document title 'Updated Document', got %s", doc.Title)
 	}
+
+	// An unknown operation is an error rather than a silent append
+	if _, err := client.EditDocument("doc123", "<p>Updated content</p>", "REMOVE", "markdown"); err == nil || !strings.Contains(err.Error(), `invalid operation "REMOVE"`) {
+		t.Errorf("Expected an invalid operation error, got %v", err)
+	}
 }
 
 func TestClient_DeleteDocument(t *testing.T) {
@@ -572,3 +583,922 @@ func TestClient_GetRecentThreads(t *testing.T) {
 		t.Errorf("Expected second thread type 'chat', got %s", threads[1].Type)
 	}
 }
+
+func TestClient_FollowThread(t *testing.T) {
+	tests := []struct {
+		name         string
+		follow       bool
+		expectedPath string
+	}{
+		{name: "follow", follow: true, expectedPath: "/threads/follow"},
+		{name: "unfollow", follow: false, expectedPath: "/threads/unfollow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.expectedPath {
+					t.Errorf("Expected path %s, got %s", tt.expectedPath, r.URL.Path)
+				}
+
+				if r.Method != "POST" {
+					t.Errorf("Expected POST method, got %s", r.Method)
+				}
+
+				if err := r.ParseForm(); err != nil {
+					t.Fatalf("Failed to parse form data: %v", err)
+				}
+
+				if r.FormValue("thread_id") != "doc123" {
+					t.Errorf("Expected thread_id 'doc123', got %s", r.FormValue("thread_id"))
+				}
+
+				response := RecentThreadData{
+					Thread: Document{ID: "doc123", Title: "Followed Doc", UserIsFollowing: tt.follow},
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token")
+			client.baseURL = server.URL
+
+			var doc *Document
+			var err error
+			if tt.follow {
+				doc, err = client.FollowThread("doc123")
+			} else {
+				doc, err = client.UnfollowThread("doc123")
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if doc.UserIsFollowing != tt.follow {
+				t.Errorf("Expected UserIsFollowing %v, got %v", tt.follow, doc.UserIsFollowing)
+			}
+		})
+	}
+}
+
+func TestClient_FollowThread_EmptyResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		following bool
+	}{
+		{name: "state changed", following: true},
+		{name: "state unchanged", following: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The follow response carries no thread; the state is read back from the thread
+				if r.URL.Path == "/threads/doc123" {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Plan", UserIsFollowing: tt.following}})
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token")
+			client.baseURL = server.URL
+
+			doc, err := client.FollowThread("doc123")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if doc.ID != "doc123" || doc.UserIsFollowing != tt.following {
+				t.Errorf("Expected doc123 with following %t as read back, got %+v", tt.following, doc)
+			}
+		})
+	}
+}
+
+func TestClient_UnfollowThread_EmptyResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Quip acknowledges with an empty body, but the thread is still followed
+		if r.URL.Path == "/threads/doc123" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Plan", UserIsFollowing: true}})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	doc, err := client.UnfollowThread("doc123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !doc.UserIsFollowing {
+		t.Errorf("Expected the following state read back from Quip, got %+v", doc)
+	}
+}
+
+func TestClient_GetDocumentCommentsPage(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request
+		expectedPath := "/threads/doc123/messages"
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("count") != "2" {
+			t.Errorf("Expected count '2', got %s", query.Get("count"))
+		}
+
+		if query.Get("max_created_usec") != "1640995300000000" {
+			t.Errorf("Expected max_created_usec '1640995300000000', got %s", query.Get("max_created_usec"))
+		}
+
+		if query.Get("last_updated_since_usec") != "1640995000000000" {
+			t.Errorf("Expected last_updated_since_usec '1640995000000000', got %s", query.Get("last_updated_since_usec"))
+		}
+
+		if query.Get("sorted_by") != "ASC" {
+			t.Errorf("Expected sorted_by 'ASC', got %s", query.Get("sorted_by"))
+		}
+
+		// Return a full page of comments
+		comments := []Comment{
+			{ID: "comment1", Text: "First", Created: 1640995100000000},
+			{ID: "comment2", Text: "Second", Created: 1640995200000000},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(comments)
+	}))
+	defer server.Close()
+
+	// Create client with test server URL
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	// Test the method
+	page, err := client.GetDocumentCommentsPage("doc123", CommentOptions{
+		Count:                2,
+		MaxCreatedUsec:       1640995300000000,
+		LastUpdatedSinceUsec: 1640995000000000,
+		SortedBy:             "asc",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(page.Comments) != 2 {
+		t.Errorf("Expected 2 comments, got %d", len(page.Comments))
+	}
+
+	// Ascending pages continue after the newest comment
+	if page.NextCursor != "1640995200000000:0" {
+		t.Errorf("Expected next cursor 1640995200000000:0, got %s", page.NextCursor)
+	}
+}
+
+func TestClient_GetThreadMessages_AscendingPastEditedComments(t *testing.T) {
+	// Two comments from before the cursor were edited after a newer one was posted
+	messages := []Comment{
+		{ID: "old1", Text: "Old", Created: 10, Updated: 500},
+		{ID: "old2", Text: "Older edit", Created: 20, Updated: 600},
+		{ID: "new", Text: "New", Created: 200, Updated: 200},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		since, _ := strconv.ParseInt(query.Get("last_updated_since_usec"), 10, 64)
+		count, _ := strconv.Atoi(query.Get("count"))
+
+		// Quip filters by update time and orders by creation time
+		var page []Comment
+		for _, message := range messages {
+			if message.Updated > since && len(page) < count {
+				page = append(page, message)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	var read []string
+	cursor := "100"
+	for pages := 0; cursor != ""; pages++ {
+		if pages == 5 {
+			t.Fatalf("Expected paging to finish, still at cursor %s", cursor)
+		}
+		opts := CommentOptions{Count: 2, SortedBy: "ASC"}
+		if err := opts.ContinueFrom(cursor); err != nil {
+			t.Fatalf("ContinueFrom(%q) failed: %v", cursor, err)
+		}
+		page, err := client.GetThreadMessages("chat1", opts)
+		if err != nil {
+			t.Fatalf("GetThreadMessages failed: %v", err)
+		}
+		for _, comment := range page.Comments {
+			read = append(read, comment.ID)
+		}
+		cursor = page.NextCursor
+	}
+
+	if strings.Join(read, ",") != "new" {
+		t.Errorf("Expected the newer comment once and no edited older ones, got %v", read)
+	}
+}
+
+func TestSearchEndpoint(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    string
+		opts     SearchOptions
+		expected string
+	}{
+		{name: "query only", query: "roadmap", expected: "/threads/search?query=roadmap"},
+		{name: "with limit", query: "q3 plan", opts: SearchOptions{Limit: 5}, expected: "/threads/search?count=5&query=q3+plan"},
+		{name: "escapes operators", query: "a&b=c", opts: SearchOptions{Limit: 5}, expected: "/threads/search?count=5&query=a%26b%3Dc"},
+		{name: "filters widen count", query: "notes", opts: SearchOptions{Limit: 5, AuthorID: "user123"}, expected: "/threads/search?count=50&query=notes"},
+		{name: "date filter widens count", query: "notes", opts: SearchOptions{UpdatedAfter: since}, expected: "/threads/search?count=50&query=notes"},
+		{name: "large limit kept", query: "notes", opts: SearchOptions{Limit: 80, AuthorID: "user123"}, expected: "/threads/search?count=80&query=notes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchEndpoint(tt.query, tt.opts); got != tt.expected {
+				t.Errorf("searchEndpoint(%q) = %s, expected %s", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClient_SearchDocumentsWithOptions(t *testing.T) {
+	day := func(d int) int64 {
+		return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC).UnixMicro()
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiResponse := []SearchResponse{
+			{Thread: Document{ID: "old", AuthorID: "user123", Created: day(1), Updated: day(2)}},
+			{Thread: Document{ID: "mine", AuthorID: "user123", Created: day(3), Updated: day(10)}},
+			{Thread: Document{ID: "theirs", AuthorID: "user456", Created: day(3), Updated: day(10)}},
+			{Thread: Document{ID: "mine-too", AuthorID: "user123", Created: day(5), Updated: day(12)}},
+			{Thread: Document{ID: "future", AuthorID: "user123", Created: day(20), Updated: day(25)}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(apiResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	result, err := client.SearchDocumentsWithOptions("notes", SearchOptions{
+		AuthorID:      "user123",
+		UpdatedAfter:  time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC),
+		UpdatedBefore: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Documents) != 2 || result.Documents[0].ID != "mine" || result.Documents[1].ID != "mine-too" {
+		t.Errorf("Expected [mine mine-too], got %+v", result.Documents)
+	}
+
+	result, err = client.SearchDocumentsWithOptions("notes", SearchOptions{Limit: 1, AuthorID: "user123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Documents) != 1 || result.Documents[0].ID != "old" {
+		t.Errorf("Expected limit to keep only [old], got %+v", result.Documents)
+	}
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	tests := []struct {
+		limit    int
+		expected int
+		wantErr  bool
+	}{
+		{limit: -1, wantErr: true},
+		{limit: 0, expected: DefaultLimit},
+		{limit: 1, expected: 1},
+		{limit: 50, expected: 50},
+		{limit: MaxLimit, expected: MaxLimit},
+		{limit: MaxLimit + 1, expected: MaxLimit},
+		{limit: 10000, expected: MaxLimit},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeLimit(tt.limit)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NormalizeLimit(%d) expected error, got nil", tt.limit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NormalizeLimit(%d) returned error %v", tt.limit, err)
+		}
+		if got != tt.expected {
+			t.Errorf("NormalizeLimit(%d) = %d, expected %d", tt.limit, got, tt.expected)
+		}
+	}
+}
+
+func TestClient_GetRecentThreads_LimitBounds(t *testing.T) {
+	var gotCount string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCount = r.URL.Query().Get("count")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	if _, err := client.GetRecentThreads(0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotCount != "10" {
+		t.Errorf("Expected default count '10', got %s", gotCount)
+	}
+
+	if _, err := client.GetRecentThreads(500); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotCount != "100" {
+		t.Errorf("Expected clamped count '100', got %s", gotCount)
+	}
+
+	gotCount = ""
+	if _, err := client.GetRecentThreads(-5); err == nil {
+		t.Error("Expected error for negative limit, got nil")
+	}
+	if gotCount != "" {
+		t.Errorf("Expected no request for negative limit, got count %s", gotCount)
+	}
+
+	if _, err := client.SearchDocuments("notes", -1); err == nil {
+		t.Error("Expected error for negative search limit, got nil")
+	}
+}
+
+func TestClient_AppendPrependToDocument(t *testing.T) {
+	var location, format string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/edit-document" {
+			t.Errorf("Expected path /threads/edit-document, got %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+		location = r.FormValue("location")
+		format = r.FormValue("format")
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	if _, err := client.AppendToDocument("doc123", "note", ""); err != nil {
+		t.Fatalf("AppendToDocument failed: %v", err)
+	}
+	if location != "0" || format != "markdown" {
+		t.Errorf("Expected append with location 0 and format markdown, got location %s format %s", location, format)
+	}
+
+	if _, err := client.PrependToDocument("doc123", "<p>note</p>", "html"); err != nil {
+		t.Fatalf("PrependToDocument failed: %v", err)
+	}
+	if location != "1" || format != "html" {
+		t.Errorf("Expected prepend with location 1 and format html, got location %s format %s", location, format)
+	}
+}
+
+func TestClient_LockEdits(t *testing.T) {
+	for _, locked := range []bool{true, false} {
+		t.Run(strconv.FormatBool(locked), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				// The lock-edits response carries no flag; the state is read back from the thread
+				if r.URL.Path == "/threads/doc123" {
+					_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Plan", EditsDisabled: locked}})
+					return
+				}
+				if r.URL.Path != "/threads/lock-edits" {
+					t.Errorf("Expected path /threads/lock-edits, got %s", r.URL.Path)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Fatalf("Failed to parse form data: %v", err)
+				}
+				if r.FormValue("thread_id") != "doc123" {
+					t.Errorf("Expected thread_id 'doc123', got %s", r.FormValue("thread_id"))
+				}
+				if r.FormValue("edits_disabled") != strconv.FormatBool(locked) {
+					t.Errorf("Expected edits_disabled %t, got %s", locked, r.FormValue("edits_disabled"))
+				}
+
+				_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Plan"}})
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token")
+			client.baseURL = server.URL
+
+			doc, err := client.LockEdits("doc123", locked)
+			if err != nil {
+				t.Fatalf("LockEdits failed: %v", err)
+			}
+			if doc.ID != "doc123" || doc.EditsDisabled != locked {
+				t.Errorf("Expected doc123 with edits disabled %t, got %+v", locked, doc)
+			}
+		})
+	}
+}
+
+func TestClient_LockEdits_UnlockUnconfirmed(t *testing.T) {
+	responses := map[string]string{
+		"empty":   "",
+		"partial": `{"thread": {"id": "doc123", "title": "Plan"}}`,
+	}
+
+	for name, body := range responses {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				// Quip ignored the unlock: the thread is still locked
+				if r.URL.Path == "/threads/doc123" {
+					_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Plan", EditsDisabled: true}})
+					return
+				}
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token", WithBaseURL(server.URL))
+
+			doc, err := client.LockEdits("doc123", false)
+			if err != nil {
+				t.Fatalf("LockEdits failed: %v", err)
+			}
+			if !doc.EditsDisabled {
+				t.Errorf("Expected the lock state read back from Quip, got %+v", doc)
+			}
+		})
+	}
+}
+
+func TestClient_RenameDocument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/edit-document" {
+			t.Errorf("Expected path /threads/edit-document, got %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+		if r.FormValue("thread_id") != "doc123" || r.FormValue("title") != "Q3 Plan" {
+			t.Errorf("Expected thread_id doc123 and title 'Q3 Plan', got %v", r.Form)
+		}
+		if _, ok := r.Form["content"]; ok {
+			t.Errorf("Expected no content in a rename, got %q", r.FormValue("content"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Q3 Plan"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	doc, err := client.RenameDocument("doc123", "  Q3 Plan ")
+	if err != nil {
+		t.Fatalf("RenameDocument failed: %v", err)
+	}
+	if doc.ID != "doc123" || doc.Title != "Q3 Plan" {
+		t.Errorf("Expected doc123 titled 'Q3 Plan', got %+v", doc)
+	}
+
+	if _, err := client.RenameDocument("doc123", " "); err == nil {
+		t.Error("Expected an error for an empty title, got nil")
+	}
+}
+
+func TestClient_CreateDocumentWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+		if r.FormValue("format") != "html" {
+			t.Errorf("Expected format 'html', got %s", r.FormValue("format"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	if _, err := client.CreateDocumentWithOptions("Tasks", "<p>hi</p>", CreateOptions{Format: "html"}); err != nil {
+		t.Fatalf("CreateDocumentWithOptions failed: %v", err)
+	}
+}
+
+func TestClient_CreateDocument_DefaultFolder(t *testing.T) {
+	var memberIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+		memberIDs = r.Form["member_ids"]
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123"}})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		defaultFolder string
+		folderID      string
+		expected      []string
+	}{
+		{name: "no folder", expected: nil},
+		{name: "default folder", defaultFolder: "team", expected: []string{"team"}},
+		{name: "explicit folder overrides the default", defaultFolder: "team", folderID: "mine", expected: []string{"mine"}},
+		{name: "explicit folder without a default", folderID: "mine", expected: []string{"mine"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test-token", WithBaseURL(server.URL), WithDefaultFolder(tt.defaultFolder))
+			if _, err := client.CreateDocumentWithOptions("Notes", "hi", CreateOptions{FolderID: tt.folderID}); err != nil {
+				t.Fatalf("CreateDocumentWithOptions failed: %v", err)
+			}
+			if !reflect.DeepEqual(memberIDs, tt.expected) {
+				t.Errorf("Expected member_ids %v, got %v", tt.expected, memberIDs)
+			}
+		})
+	}
+}
+
+func TestClient_GetRecentThreadsWithOptions(t *testing.T) {
+	// Returned in map form, so the order on the wire is arbitrary
+	response := RecentThreadsResponse{
+		"doc1":   {Thread: Document{ID: "doc1", Type: "document", Updated: 500}},
+		"sheet1": {Thread: Document{ID: "sheet1", Type: "spreadsheet", Updated: 400}},
+		"doc2":   {Thread: Document{ID: "doc2", Type: "document", Updated: 300}},
+		"chat1":  {Thread: Document{ID: "chat1", Type: "chat", Updated: 200}},
+		"doc3":   {Thread: Document{ID: "doc3", Type: "document", Updated: 100}},
+	}
+
+	tests := []struct {
+		name           string
+		opts           RecentOptions
+		expectedCount  string
+		expectedMax    string
+		expectedIDs    []string
+		expectedCursor int64
+	}{
+		{
+			name:          "all types newest first",
+			opts:          RecentOptions{Limit: 10},
+			expectedCount: "10",
+			expectedIDs:   []string{"doc1", "sheet1", "doc2", "chat1", "doc3"},
+		},
+		{
+			name:           "documents only, trimmed to limit",
+			opts:           RecentOptions{Limit: 2, Type: "document"},
+			expectedCount:  "50",
+			expectedIDs:    []string{"doc1", "doc2"},
+			expectedCursor: 299,
+		},
+		{
+			name:          "all is no filter",
+			opts:          RecentOptions{Limit: 10, Type: "all"},
+			expectedCount: "10",
+			expectedIDs:   []string{"doc1", "sheet1", "doc2", "chat1", "doc3"},
+		},
+		{
+			name:           "full page continues from oldest",
+			opts:           RecentOptions{Limit: 5, MaxUpdatedUsec: 1000},
+			expectedCount:  "5",
+			expectedMax:    "1000",
+			expectedIDs:    []string{"doc1", "sheet1", "doc2", "chat1", "doc3"},
+			expectedCursor: 99,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("count"); got != tt.expectedCount {
+					t.Errorf("Expected count %s, got %s", tt.expectedCount, got)
+				}
+				if got := r.URL.Query().Get("max_updated_usec"); got != tt.expectedMax {
+					t.Errorf("Expected max_updated_usec %q, got %q", tt.expectedMax, got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token")
+			client.baseURL = server.URL
+
+			page, err := client.GetRecentThreadsWithOptions(tt.opts)
+			if err != nil {
+				t.Fatalf("GetRecentThreadsWithOptions failed: %v", err)
+			}
+
+			var ids []string
+			for _, thread := range page.Threads {
+				ids = append(ids, thread.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expectedIDs, ",") {
+				t.Errorf("Expected threads %v, got %v", tt.expectedIDs, ids)
+			}
+			if page.NextCursor != tt.expectedCursor {
+				t.Errorf("Expected next cursor %d, got %d", tt.expectedCursor, page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestClient_MaxContentBytes(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithMaxContentBytes(10))
+	client.baseURL = server.URL
+
+	if client.MaxContentBytes() != 10 {
+		t.Errorf("Expected limit 10, got %d", client.MaxContentBytes())
+	}
+
+	if _, err := client.CreateDocument("Big", strings.Repeat("x", 11)); err == nil || !strings.Contains(err.Error(), "over the 10-byte limit") {
+		t.Errorf("Expected size error from CreateDocument, got %v", err)
+	}
+	if _, err := client.EditDocument("doc123", strings.Repeat("x", 11), "APPEND", ""); err == nil || !strings.Contains(err.Error(), "over the 10-byte limit") {
+		t.Errorf("Expected size error from EditDocument, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected oversized content not to be sent, got %d requests", requests)
+	}
+
+	if _, err := client.EditDocument("doc123", strings.Repeat("x", 10), "APPEND", ""); err != nil {
+		t.Errorf("Expected content at the limit to be sent, got %v", err)
+	}
+
+	if NewClient("test-token", WithMaxContentBytes(0)).MaxContentBytes() != 0 {
+		t.Error("Expected WithMaxContentBytes(0) to disable the limit")
+	}
+}
+
+func TestClient_SearchDocuments_DedupeAndRank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiResponse := []SearchResponse{
+			{Thread: Document{ID: "body-old", Title: "Weekly notes", Updated: 100}},
+			{Thread: Document{ID: "title", Title: "Roadmap Plan 2025", Updated: 50}},
+			{Thread: Document{ID: "body-new", Title: "Standup", Updated: 300}},
+			{Thread: Document{ID: "title", Title: "Roadmap Plan 2025", Updated: 50}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(apiResponse)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	ids := func(docs []Document) []string {
+		var ids []string
+		for _, doc := range docs {
+			ids = append(ids, doc.ID)
+		}
+		return ids
+	}
+
+	result, err := client.SearchDocuments("roadmap plan", 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := strings.Join(ids(result.Documents), ","); got != "body-old,title,body-new" {
+		t.Errorf("Expected duplicates removed in Quip's order, got %s", got)
+	}
+
+	result, err = client.SearchDocumentsWithOptions("roadmap plan", SearchOptions{Limit: 2, Rank: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := strings.Join(ids(result.Documents), ","); got != "title,body-new" {
+		t.Errorf("Expected title match first, then most recent, got %s", got)
+	}
+}
+
+func TestClient_SearchDocuments_Folder(t *testing.T) {
+	var searchCount string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/folders/folder1":
+			_ = json.NewEncoder(w).Encode(FolderData{
+				Folder:   Folder{ID: "folder1"},
+				Children: []FolderChild{{ThreadID: "doc1"}, {FolderID: "sub1"}, {ThreadID: "doc3"}},
+			})
+		case "/threads/search":
+			searchCount = r.URL.Query().Get("count")
+			_ = json.NewEncoder(w).Encode([]SearchResponse{
+				{Thread: Document{ID: "doc1", Title: "Plan"}},
+				{Thread: Document{ID: "doc2", Title: "Plan elsewhere"}},
+				{Thread: Document{ID: "doc3", Title: "Plan notes"}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	result, err := client.SearchDocumentsWithOptions("plan", SearchOptions{Limit: 5, FolderID: "folder1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Documents) != 2 || result.Documents[0].ID != "doc1" || result.Documents[1].ID != "doc3" {
+		t.Errorf("Expected only doc1 and doc3 from the folder, got %+v", result.Documents)
+	}
+	if searchCount != strconv.Itoa(MaxLimit) {
+		t.Errorf("Expected a folder search to request %d results, got %s", MaxLimit, searchCount)
+	}
+
+	if _, err := client.SearchDocumentsWithOptions("plan", SearchOptions{FolderID: "missing"}); err == nil {
+		t.Error("Expected error for a missing folder, got nil")
+	}
+}
+
+func TestClient_DeleteDocumentWithOptions(t *testing.T) {
+	var wipeout string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wipeout = r.FormValue("wipeout")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	if err := client.DeleteDocumentWithOptions("doc123", DeleteOptions{Wipeout: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if wipeout != "true" {
+		t.Errorf("Expected wipeout 'true', got %q", wipeout)
+	}
+}
+
+func TestDecodeDocument_AccessLevels(t *testing.T) {
+	body := `{
+		"thread": {"id": "doc123", "title": "Plan"},
+		"access_levels": {"user1": {"access_level": "OWN"}, "user2": {"access_level": "VIEW"}}
+	}`
+
+	doc, err := decodeDocument([]byte(body))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	levels := doc.AccessLevelsByID()
+	if len(levels) != 2 || levels["user1"] != "OWN" || levels["user2"] != "VIEW" {
+		t.Errorf("Expected OWN and VIEW, got %v", levels)
+	}
+
+	// A bare document decoded from JSON holds the nested levels as generic maps
+	bare, err := decodeDocument([]byte(`{"id": "doc123", "access_levels": {"user3": {"access_level": "EDIT"}, "user4": {}}}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if levels := bare.AccessLevelsByID(); len(levels) != 1 || levels["user3"] != "EDIT" {
+		t.Errorf("Expected only user3 with EDIT, got %v", levels)
+	}
+}
+
+// BenchmarkDecodeDocument measures reading and decoding a thread response with about 4 MB of HTML
+func BenchmarkDecodeDocument(b *testing.B) {
+	html := strings.Repeat("<p>Lorem ipsum dolor sit amet, consectetur adipiscing elit.</p>\n", 64<<10)
+	body, err := json.Marshal(RecentThreadData{
+		Thread: Document{ID: "doc123", Title: "Large Document", Type: "document"},
+		HTML:   html,
+	})
+	if err != nil {
+		b.Fatalf("Failed to encode response: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		resp := &http.Response{Body: io.NopCloser(bytes.NewReader(body)), ContentLength: int64(len(body))}
+		data, err := readBody(resp)
+		if err != nil {
+			b.Fatalf("Failed to read body: %v", err)
+		}
+		doc, err := decodeDocument(data)
+		if err != nil || len(doc.HTML) != len(html) {
+			b.Fatalf("Expected the full document, got %v", err)
+		}
+	}
+}
+
+func TestClient_IncludeTrashed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/search":
+			_ = json.NewEncoder(w).Encode([]SearchResponse{
+				{Thread: Document{ID: "live", Updated: 200}},
+				{Thread: Document{ID: "trashed", Updated: 100, IsDeleted: true}},
+			})
+		case "/threads/recent":
+			_ = json.NewEncoder(w).Encode(RecentThreadsResponse{
+				"live":    {Thread: Document{ID: "live", Updated: 200}},
+				"trashed": {Thread: Document{ID: "trashed", Updated: 100, IsDeleted: true}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	ids := func(docs []Document) string {
+		var ids []string
+		for _, doc := range docs {
+			ids = append(ids, doc.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	for _, includeTrashed := range []bool{false, true} {
+		expected := "live"
+		if includeTrashed {
+			expected = "live,trashed"
+		}
+
+		result, err := client.SearchDocumentsWithOptions("notes", SearchOptions{Rank: true, IncludeTrashed: includeTrashed})
+		if err != nil {
+			t.Fatalf("SearchDocumentsWithOptions failed: %v", err)
+		}
+		if got := ids(result.Documents); got != expected {
+			t.Errorf("Search with IncludeTrashed=%v returned %s, expected %s", includeTrashed, got, expected)
+		}
+
+		page, err := client.GetRecentThreadsWithOptions(RecentOptions{IncludeTrashed: includeTrashed})
+		if err != nil {
+			t.Fatalf("GetRecentThreadsWithOptions failed: %v", err)
+		}
+		if got := ids(page.Threads); got != expected {
+			t.Errorf("Recent threads with IncludeTrashed=%v returned %s, expected %s", includeTrashed, got, expected)
+		}
+	}
+}
+
+func TestClient_LockEdits_Ignored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Plan"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	doc, err := client.LockEdits("doc123", true)
+	if err != nil {
+		t.Fatalf("LockEdits failed: %v", err)
+	}
+	if doc.EditsDisabled {
+		t.Errorf("Expected the lock state Quip reports, not the one requested, got %+v", doc)
+	}
+}
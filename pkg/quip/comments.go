@@ -0,0 +1,80 @@
+package quip
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// AddComment posts a new comment (message) to a thread
+func (c *Client) AddComment(threadID, text string) (*Comment, error) {
+	formData := map[string]string{
+		"thread_id": threadID,
+		"content":   text,
+	}
+
+	return c.postComment("/messages/new", formData)
+}
+
+// EditComment replaces the text of an existing comment
+func (c *Client) EditComment(messageID, newText string) (*Comment, error) {
+	formData := map[string]string{
+		"message_id": messageID,
+		"content":    newText,
+	}
+
+	comment, err := c.postComment("/messages/edit", formData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quip may acknowledge the edit without echoing the message back
+	if comment.ID == "" {
+		comment.ID = messageID
+		comment.Text = newText
+	}
+
+	return comment, nil
+}
+
+// DeleteComment deletes a comment. Quip acknowledges deletes with an empty body,
+// so any non-error status is treated as success.
+func (c *Client) DeleteComment(messageID string) error {
+	formData := map[string]string{
+		"message_id": messageID,
+	}
+
+	resp, err := c.makeFormRequest("POST", "/messages/delete", formData)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment %s: %w", messageID, err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// postComment posts a form to a message endpoint and decodes the returned comment, if any
+func (c *Client) postComment(endpoint string, formData map[string]string) (*Comment, error) {
+	resp, err := c.makeFormRequest("POST", endpoint, formData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var comment Comment
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return &comment, nil
+	}
+
+	if err := json.Unmarshal(respBody, &comment); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &comment, nil
+}
@@ -0,0 +1,114 @@
+package quip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_AddComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages/new" {
+			t.Errorf("Expected path /messages/new, got %s", r.URL.Path)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+
+		if r.FormValue("thread_id") != "doc123" {
+			t.Errorf("Expected thread_id 'doc123', got %s", r.FormValue("thread_id"))
+		}
+
+		if r.FormValue("content") != "Looks good" {
+			t.Errorf("Expected content 'Looks good', got %s", r.FormValue("content"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Comment{ID: "msg123", Text: "Looks good", AuthorID: "user123"})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	comment, err := client.AddComment("doc123", "Looks good")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if comment.ID != "msg123" {
+		t.Errorf("Expected comment ID 'msg123', got %s", comment.ID)
+	}
+}
+
+func TestClient_EditComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages/edit" {
+			t.Errorf("Expected path /messages/edit, got %s", r.URL.Path)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+
+		if r.FormValue("message_id") != "msg123" {
+			t.Errorf("Expected message_id 'msg123', got %s", r.FormValue("message_id"))
+		}
+
+		// Acknowledge without echoing the message
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	comment, err := client.EditComment("msg123", "Fixed typo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if comment.ID != "msg123" || comment.Text != "Fixed typo" {
+		t.Errorf("Expected edited comment msg123 with new text, got %+v", comment)
+	}
+}
+
+func TestClient_DeleteComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "empty success response", status: http.StatusOK},
+		{name: "not found", status: http.StatusNotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/messages/delete" {
+					t.Errorf("Expected path /messages/delete, got %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token")
+			client.baseURL = server.URL
+
+			err := client.DeleteComment("msg123")
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "msg123") {
+					t.Errorf("Expected error mentioning msg123, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
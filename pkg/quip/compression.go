@@ -0,0 +1,47 @@
+package quip
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipBody decompresses a gzip-encoded body and closes the underlying response body with it
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes both the gzip reader and the response body
+func (g *gzipBody) Close() error {
+	gzErr := g.Reader.Close()
+	if err := g.body.Close(); err != nil {
+		return err
+	}
+	return gzErr
+}
+
+// decompressResponse replaces a gzip-encoded response body with a decompressing reader.
+// Setting Accept-Encoding ourselves turns off net/http's transparent decompression, so
+// this does the same job: the encoding header and Content-Length (which describe the
+// compressed bytes) are cleared and resp.Uncompressed is set. Other encodings are left as-is.
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return fmt.Errorf("failed to decompress response: %w", err)
+	}
+
+	resp.Body = &gzipBody{Reader: reader, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
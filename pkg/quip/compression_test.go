@@ -0,0 +1,90 @@
+package quip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestClient_GzipResponse(t *testing.T) {
+	html := strings.Repeat("<p>Large document content</p>", 1000)
+	payload, err := json.Marshal(RecentThreadData{Thread: Document{ID: "doc123", Title: "Big Doc"}, HTML: html})
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+	compressed := gzipBytes(t, payload)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(len(compressed)))
+		_, _ = w.Write(compressed)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	doc, err := client.GetDocument("doc123")
+	if err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+	if doc.Title != "Big Doc" || doc.HTML != html {
+		t.Errorf("Expected decompressed document, got title %q and %d bytes of HTML", doc.Title, len(doc.HTML))
+	}
+}
+
+func TestClient_GzipErrorResponse(t *testing.T) {
+	compressed := gzipBytes(t, []byte(`{"error": "Not found"}`))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write(compressed)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	_, err := client.GetDocument("missing")
+	if err == nil || !strings.Contains(err.Error(), `"error": "Not found"`) {
+		t.Errorf("Expected decompressed API error, got %v", err)
+	}
+}
+
+func TestClient_GzipCorruptResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	_, err := client.GetDocument("doc123")
+	if err == nil || !strings.Contains(err.Error(), "failed to decompress response") {
+		t.Errorf("Expected decompression error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package quip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// contactsTTL is how long GetContacts reuses a fetched contact list
+const contactsTTL = 15 * time.Minute
+
+// contactsCache holds the current user's contacts; it is shared by copies made with WithContext
+type contactsCache struct {
+	mu        sync.Mutex
+	users     []User
+	fetchedAt time.Time
+}
+
+// GetContacts returns the current user's contacts. The list changes rarely, so it is
+// fetched once and reused for a while regardless of WithCache.
+func (c *Client) GetContacts() ([]User, error) {
+	c.contacts.mu.Lock()
+	defer c.contacts.mu.Unlock()
+
+	if !c.contacts.fetchedAt.IsZero() && time.Since(c.contacts.fetchedAt) < contactsTTL {
+		return c.contacts.users, nil
+	}
+
+	resp, err := c.makeRequest("GET", "/users/contacts", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var users []User
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	c.contacts.users = users
+	c.contacts.fetchedAt = time.Now()
+	return users, nil
+}
+
+// PrimaryEmail returns the user's email address, falling back to the first of Emails
+func (u *User) PrimaryEmail() string {
+	if u.Email != "" || len(u.Emails) == 0 {
+		return u.Email
+	}
+	return u.Emails[0]
+}
+
+// ResolveUserByEmail finds the user with the given email address. The current user's
+// contacts are checked first; otherwise Quip's user lookup, which accepts an email in
+// place of a user ID, is tried. Only users visible to the current user can be found; a 404 or
+// an empty answer is reported as not found, while other failures (authentication, rate limits,
+// server errors) are returned as they are.
+func (c *Client) ResolveUserByEmail(email string) (*User, error) {
+	email = strings.TrimSpace(email)
+
+	contacts, err := c.GetContacts()
+	if err == nil {
+		for _, contact := range contacts {
+			if contact.hasEmail(email) {
+				return &contact, nil
+			}
+		}
+	}
+
+	user, err := c.GetUser(email)
+	if err != nil && StatusCode(err) != http.StatusNotFound {
+		return nil, fmt.Errorf("failed to look up user %s: %w", email, err)
+	}
+	if err != nil || user.ID == "" {
+		if err == nil {
+			err = fmt.Errorf("empty response")
+		}
+		return nil, fmt.Errorf("no Quip user with email %s found among your contacts or accessible users: %w", email, err)
+	}
+
+	return user, nil
+}
+
+// hasEmail reports whether any of the user's email addresses matches email, ignoring case
+func (u *User) hasEmail(email string) bool {
+	if strings.EqualFold(u.Email, email) {
+		return true
+	}
+	for _, e := range u.Emails {
+		if strings.EqualFold(e, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_GetContacts(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/users/contacts" {
+			t.Errorf("Expected path /users/contacts, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]User{
+			{ID: "user1", Name: "Ada Lovelace", Emails: []string{"ada@example.com"}},
+			{ID: "user2", Name: "Alan Turing", Emails: []string{"alan@example.com"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	contacts, err := client.GetContacts()
+	if err != nil {
+		t.Fatalf("GetContacts failed: %v", err)
+	}
+	if len(contacts) != 2 || contacts[0].Name != "Ada Lovelace" {
+		t.Errorf("Expected 2 contacts starting with Ada Lovelace, got %+v", contacts)
+	}
+
+	// A second call, including from a context-bound copy, reuses the fetched list
+	if _, err := client.WithContext(context.Background()).GetContacts(); err != nil {
+		t.Fatalf("GetContacts failed: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected contacts to be fetched once, got %d requests", requests)
+	}
+}
+
+func TestClient_ResolveUserByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/contacts":
+			_ = json.NewEncoder(w).Encode([]User{
+				{ID: "user1", Name: "Ada Lovelace", Emails: []string{"ada@example.com"}},
+			})
+		case "/users/grace@example.com":
+			_ = json.NewEncoder(w).Encode(User{ID: "user2", Name: "Grace Hopper", Email: "grace@example.com"})
+		case "/users/busy@example.com":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error": "Service unavailable"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	tests := []struct {
+		name     string
+		email    string
+		expected string
+	}{
+		{name: "contact", email: "Ada@Example.com", expected: "user1"},
+		{name: "user lookup", email: "grace@example.com", expected: "user2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := client.ResolveUserByEmail(tt.email)
+			if err != nil {
+				t.Fatalf("ResolveUserByEmail failed: %v", err)
+			}
+			if user.ID != tt.expected {
+				t.Errorf("Expected user %s, got %s", tt.expected, user.ID)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.ResolveUserByEmail("nobody@example.com")
+		if err == nil || !strings.Contains(err.Error(), "no Quip user with email nobody@example.com") {
+			t.Errorf("Expected not-found error, got %v", err)
+		}
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		_, err := client.ResolveUserByEmail("busy@example.com")
+		if err == nil || strings.Contains(err.Error(), "no Quip user") || StatusCode(err) != http.StatusServiceUnavailable {
+			t.Errorf("Expected the 503 to be returned rather than not found, got %v", err)
+		}
+	})
+}
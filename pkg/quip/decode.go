@@ -0,0 +1,150 @@
+package quip
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// maxSnippetBytes bounds how much of an unrecognized response body a DecodeError quotes
+const maxSnippetBytes = 256
+
+// DecodeError is returned when a response body matches none of the shapes the client
+// knows for that endpoint. It quotes only the start of the body, so a large unexpected
+// response doesn't end up in logs or tool results in full.
+type DecodeError struct {
+	Snippet string // the start of the body, at most maxSnippetBytes
+	Size    int    // length of the whole body in bytes
+	Err     error  // why the last shape didn't match
+}
+
+func (e *DecodeError) Error() string {
+	snippet := e.Snippet
+	if len(snippet) < e.Size {
+		snippet += "..."
+	}
+	return fmt.Sprintf("failed to decode response: %v (%d-byte body: %s)", e.Err, e.Size, snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError builds a DecodeError for data, cutting the snippet on a UTF-8 boundary
+func newDecodeError(data []byte, err error) *DecodeError {
+	snippet := data
+	if len(snippet) > maxSnippetBytes {
+		snippet = snippet[:maxSnippetBytes]
+		for len(snippet) > 0 && !utf8.Valid(snippet) {
+			snippet = snippet[:len(snippet)-1]
+		}
+	}
+	return &DecodeError{Snippet: string(snippet), Size: len(data), Err: err}
+}
+
+// responseShape decodes one known layout of a response body. It reports ok=false, with
+// the reason in err, when the body isn't in this layout so the next shape can be tried.
+type responseShape[T any] func(data []byte) (result T, ok bool, err error)
+
+// decodeShapes tries each shape in order and returns the first match. When none match,
+// the error is a *DecodeError carrying the last shape's reason.
+func decodeShapes[T any](data []byte, shapes ...responseShape[T]) (T, error) {
+	var zero T
+	err := fmt.Errorf("unrecognized response format")
+	for _, shape := range shapes {
+		result, ok, shapeErr := shape(data)
+		if ok {
+			return result, nil
+		}
+		if shapeErr != nil {
+			err = shapeErr
+		}
+	}
+	return zero, newDecodeError(data, err)
+}
+
+// threadEnvelope decodes thread data: the document nested under "thread", with the HTML,
+// access levels and expanded users and folders beside it landing in the embedded Document
+type threadEnvelope struct {
+	Document
+	Thread *Document `json:"thread"`
+}
+
+// threadDataShape matches thread data with the document nested under "thread". The HTML,
+// per-user access levels and expanded data are returned beside the thread and are merged into it.
+func threadDataShape(data []byte) (*Document, bool, error) {
+	var envelope threadEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, false, err
+	}
+	if envelope.Thread == nil || envelope.Thread.ID == "" {
+		return nil, false, nil
+	}
+
+	doc := envelope.Thread
+	if envelope.HTML != "" {
+		doc.HTML = envelope.HTML
+	}
+	if len(doc.AccessLevels) == 0 {
+		doc.AccessLevels = envelope.AccessLevels
+	}
+	if len(doc.ExpandedUserIds) == 0 {
+		doc.ExpandedUserIds = envelope.ExpandedUserIds
+	}
+	if len(doc.Users) == 0 {
+		doc.Users = envelope.Users
+	}
+	if len(doc.Folders) == 0 {
+		doc.Folders = envelope.Folders
+	}
+	return doc, true, nil
+}
+
+// bareDocumentShape matches a document returned on its own
+func bareDocumentShape(data []byte) (*Document, bool, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, err
+	}
+	return &doc, true, nil
+}
+
+// threadMapShape matches the /threads/recent map of thread ID to thread data
+func threadMapShape(data []byte) ([]Document, bool, error) {
+	var response RecentThreadsResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, false, err
+	}
+	if len(response) == 0 {
+		return nil, false, nil
+	}
+
+	threads := make([]Document, 0, len(response))
+	for _, threadData := range response {
+		threads = append(threads, threadData.Thread)
+	}
+	return threads, true, nil
+}
+
+// documentListShape matches a plain array of documents
+func documentListShape(data []byte) ([]Document, bool, error) {
+	var threads []Document
+	if err := json.Unmarshal(data, &threads); err != nil {
+		return nil, false, err
+	}
+	return threads, true, nil
+}
+
+// searchResponseListShape matches an array of thread data, as returned by search
+func searchResponseListShape(data []byte) ([]Document, bool, error) {
+	var searchResponses []SearchResponse
+	if err := json.Unmarshal(data, &searchResponses); err != nil {
+		return nil, false, err
+	}
+
+	threads := make([]Document, len(searchResponses))
+	for i, item := range searchResponses {
+		threads[i] = item.Thread
+	}
+	return threads, true, nil
+}
@@ -0,0 +1,98 @@
+package quip
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeDocument_Shapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantID   string
+		wantHTML string
+	}{
+		{
+			name:     "thread data",
+			body:     `{"thread": {"id": "doc1", "title": "Plan"}, "html": "<p>Hi</p>", "user_ids": ["u1"]}`,
+			wantID:   "doc1",
+			wantHTML: "<p>Hi</p>",
+		},
+		{
+			name:     "bare document",
+			body:     `{"id": "doc2", "title": "Plan", "html": "<p>Bare</p>"}`,
+			wantID:   "doc2",
+			wantHTML: "<p>Bare</p>",
+		},
+		{
+			name:   "thread data without an ID falls back to the bare shape",
+			body:   `{"thread": {}, "id": "doc3"}`,
+			wantID: "doc3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeDocument([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if doc.ID != tt.wantID || doc.HTML != tt.wantHTML {
+				t.Errorf("Expected ID %q and HTML %q, got %q and %q", tt.wantID, tt.wantHTML, doc.ID, doc.HTML)
+			}
+		})
+	}
+}
+
+func TestDecodeRecentThreads_Shapes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "map of thread data", body: `{"doc1": {"thread": {"id": "doc1", "title": "Plan"}}}`},
+		{name: "array of documents", body: `[{"id": "doc1", "title": "Plan"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threads, err := decodeRecentThreads([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(threads) != 1 || threads[0].ID != "doc1" || threads[0].Title != "Plan" {
+				t.Errorf("Expected doc1, got %+v", threads)
+			}
+		})
+	}
+}
+
+func TestDecodeShapes_SearchResponseList(t *testing.T) {
+	threads, err := decodeShapes([]byte(`[{"thread": {"id": "doc1"}}]`), searchResponseListShape)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(threads) != 1 || threads[0].ID != "doc1" {
+		t.Errorf("Expected doc1, got %+v", threads)
+	}
+}
+
+func TestDecodeShapes_Unrecognized(t *testing.T) {
+	body := `"` + strings.Repeat("é", 1000) + `"`
+
+	_, err := decodeRecentThreads([]byte(body))
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected a *DecodeError, got %v", err)
+	}
+	if decodeErr.Size != len(body) {
+		t.Errorf("Expected size %d, got %d", len(body), decodeErr.Size)
+	}
+	if len(decodeErr.Snippet) > maxSnippetBytes || !strings.HasPrefix(body, decodeErr.Snippet) {
+		t.Errorf("Expected a snippet of at most %d bytes from the start of the body, got %d bytes", maxSnippetBytes, len(decodeErr.Snippet))
+	}
+	if len(err.Error()) > maxSnippetBytes+200 || !strings.Contains(err.Error(), "failed to decode response") {
+		t.Errorf("Expected a bounded decode error, got %q", err.Error())
+	}
+}
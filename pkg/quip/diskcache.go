@@ -0,0 +1,147 @@
+package quip
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheSuffix marks the files a DiskCache owns, so eviction never touches anything else
+const diskCacheSuffix = ".cache"
+
+// DiskCache is a Cache that keeps entries as files in a directory, so they survive restarts.
+// The directory is kept under maxBytes by removing the least recently used entries.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewDiskCache creates a cache in dir, creating the directory if needed. maxBytes bounds the
+// total size of the cached entries and must be positive.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("invalid cache size %d: must be positive", maxBytes)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir, maxBytes: maxBytes, now: time.Now}, nil
+}
+
+// path returns the file holding key. Keys are hashed since they may contain any characters.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskCacheSuffix)
+}
+
+// Get returns the cached value if it exists and has not expired. Reading an entry marks it
+// as recently used.
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) < 8 {
+		return nil, false
+	}
+
+	// Each file starts with the expiry time in Unix nanoseconds (0 for none)
+	if expires := int64(binary.BigEndian.Uint64(data[:8])); expires != 0 && d.now().UnixNano() > expires {
+		os.Remove(path)
+		return nil, false
+	}
+
+	now := d.now()
+	_ = os.Chtimes(path, now, now)
+	return data[8:], true
+}
+
+// Set stores a value that expires after ttl, then evicts old entries if the cache is over size
+func (d *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data := make([]byte, 8, 8+len(value))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(data, uint64(d.now().Add(ttl).UnixNano()))
+	}
+	data = append(data, value...)
+	if int64(len(data)) > d.maxBytes {
+		return
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	path := d.path(key)
+	tmp, err := os.CreateTemp(d.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	now := d.now()
+	_ = os.Chtimes(path, now, now)
+
+	d.evict()
+}
+
+// Delete removes a value from the cache
+func (d *DiskCache) Delete(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	os.Remove(d.path(key))
+}
+
+// evict removes the least recently used entries until the cache fits in maxBytes
+func (d *DiskCache) evict() {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), diskCacheSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{filepath.Join(d.dir, entry.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= d.maxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+}
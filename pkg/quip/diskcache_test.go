@@ -0,0 +1,106 @@
+package quip
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiskCache_Persistence(t *testing.T) {
+	dir := t.TempDir()
+
+	cache, err := NewDiskCache(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	cache.Set("markdown:doc123:1", []byte("# Roadmap"), 0)
+
+	// A new cache over the same directory, as after a restart, sees the entry
+	reopened, err := NewDiskCache(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	if value, ok := reopened.Get("markdown:doc123:1"); !ok || string(value) != "# Roadmap" {
+		t.Errorf("Expected the entry to survive a restart, got %q (found %v)", value, ok)
+	}
+
+	reopened.Delete("markdown:doc123:1")
+	if _, ok := cache.Get("markdown:doc123:1"); ok {
+		t.Error("Expected deleted entry to be gone")
+	}
+}
+
+func TestDiskCache_Expiry(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	now := time.Unix(1640995200, 0)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("short", []byte("a"), time.Minute)
+	cache.Set("forever", []byte("b"), 0)
+
+	now = now.Add(2 * time.Minute)
+
+	if _, ok := cache.Get("short"); ok {
+		t.Error("Expected expired entry to be evicted")
+	}
+	if value, ok := cache.Get("forever"); !ok || string(value) != "b" {
+		t.Errorf("Expected entry without TTL to persist, got %q (found %v)", value, ok)
+	}
+}
+
+func TestDiskCache_SizeBound(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 250)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	now := time.Unix(1640995200, 0)
+	cache.now = func() time.Time { return now }
+
+	value := []byte(strings.Repeat("x", 92)) // 100 bytes on disk with the expiry header
+	cache.Set("first", value, 0)
+	now = now.Add(time.Second)
+	cache.Set("second", value, 0)
+	now = now.Add(time.Second)
+
+	// Reading first makes second the least recently used entry
+	cache.Get("first")
+	now = now.Add(time.Second)
+	cache.Set("third", value, 0)
+
+	if _, ok := cache.Get("second"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"first", "third"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %s to be kept", key)
+		}
+	}
+
+	cache.Set("huge", make([]byte, 300), 0)
+	if _, ok := cache.Get("huge"); ok {
+		t.Error("Expected an entry larger than the cache not to be stored")
+	}
+
+	entries, _ := filepath.Glob(filepath.Join(dir, "*"))
+	var total int64
+	for _, entry := range entries {
+		if info, err := os.Stat(entry); err == nil {
+			total += info.Size()
+		}
+	}
+	if total > 250 {
+		t.Errorf("Expected the cache to stay within 250 bytes, got %d", total)
+	}
+}
+
+func TestNewDiskCache_InvalidSize(t *testing.T) {
+	if _, err := NewDiskCache(t.TempDir(), 0); err == nil {
+		t.Error("Expected an error for a zero size")
+	}
+}
@@ -0,0 +1,73 @@
+package quip
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrDryRun is returned for write requests made while a DryRunRecorder is attached to the
+// client's context. The request was recorded but not sent.
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// DryRunRequest is a write request that was recorded instead of sent
+type DryRunRequest struct {
+	Method      string
+	Endpoint    string
+	ContentType string
+	Body        string
+}
+
+// DryRunRecorder collects the write requests made by clients bound to a context from
+// WithDryRunRecorder. Reads are still sent so callers can build their writes as usual.
+type DryRunRecorder struct {
+	mu       sync.Mutex
+	requests []DryRunRequest
+}
+
+// Requests returns the write requests recorded so far
+func (r *DryRunRecorder) Requests() []DryRunRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]DryRunRequest(nil), r.requests...)
+}
+
+func (r *DryRunRecorder) record(req DryRunRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, req)
+}
+
+type dryRunKey struct{}
+
+// WithDryRunRecorder returns a context under which a client (see Client.WithContext) records
+// write requests in recorder and fails them with ErrDryRun instead of sending them
+func WithDryRunRecorder(ctx context.Context, recorder *DryRunRecorder) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, recorder)
+}
+
+// recordDryRun records req if it is a write made under a dry-run context, reporting whether it did
+func recordDryRun(req *http.Request, endpoint string) bool {
+	recorder, ok := req.Context().Value(dryRunKey{}).(*DryRunRecorder)
+	if !ok || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return false
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+	}
+
+	recorder.record(DryRunRequest{
+		Method:      req.Method,
+		Endpoint:    endpoint,
+		ContentType: req.Header.Get("Content-Type"),
+		Body:        string(body),
+	})
+	return true
+}
@@ -0,0 +1,41 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_DryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected only reads to reach the server in a dry run, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123"}})
+	}))
+	defer server.Close()
+
+	recorder := &DryRunRecorder{}
+	client := NewClient("test-token").WithContext(WithDryRunRecorder(context.Background(), recorder))
+	client.baseURL = server.URL
+
+	if _, err := client.GetDocument("doc123"); err != nil {
+		t.Fatalf("Expected reads to be sent in a dry run, got %v", err)
+	}
+
+	if err := client.DeleteDocument("doc123"); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("Expected ErrDryRun, got %v", err)
+	}
+
+	requests := recorder.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 recorded request, got %d", len(requests))
+	}
+	if requests[0].Method != "POST" || requests[0].Endpoint != "/threads/delete" || requests[0].Body != "thread_id=doc123&wipeout=false" {
+		t.Errorf("Unexpected recorded request %+v", requests[0])
+	}
+}
@@ -0,0 +1,54 @@
+package quip
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIError is returned when Quip answers a request with an error status (400 or above)
+type APIError struct {
+	// Method and Endpoint identify the failed request, e.g. "GET" and "/threads/abc".
+	// The endpoint's query string is left out since it may hold search terms.
+	Method   string
+	Endpoint string
+
+	StatusCode int
+	// Body is the response body, usually a JSON object with an error description
+	Body string
+}
+
+func (e *APIError) Error() string {
+	if e.Endpoint == "" {
+		return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("%s %s: API error %d: %s", e.Method, e.Endpoint, e.StatusCode, e.Body)
+}
+
+// StatusCode returns the HTTP status of the APIError in err's chain, or 0 if there is none
+// (e.g. a network error or a request that was never sent)
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsUnauthorized reports whether err is Quip rejecting the request's token (HTTP 401)
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// redactURLError drops the query string from the URL in a transport error, since it may hold
+// search terms. The error stays a *url.Error so that retries still recognize it.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	redacted, _, _ := strings.Cut(urlErr.URL, "?")
+	return &url.Error{Op: urlErr.Op, URL: redacted, Err: urlErr.Err}
+}
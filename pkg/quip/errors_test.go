@@ -0,0 +1,73 @@
+package quip
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error": "Forbidden"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	_, err := client.GetDocument("doc123")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusForbidden || apiErr.Body != `{"error": "Forbidden"}` {
+		t.Errorf("Expected status 403 with the response body, got %d %q", apiErr.StatusCode, apiErr.Body)
+	}
+	if StatusCode(err) != http.StatusForbidden || IsUnauthorized(err) {
+		t.Errorf("Expected StatusCode 403 and not unauthorized, got %d", StatusCode(err))
+	}
+	if StatusCode(errors.New("network down")) != 0 {
+		t.Error("Expected StatusCode 0 for an error without a response")
+	}
+	if apiErr.Method != "GET" || apiErr.Endpoint != "/threads/doc123" {
+		t.Errorf("Expected the request to be recorded, got %s %s", apiErr.Method, apiErr.Endpoint)
+	}
+}
+
+func TestClient_ErrorEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Not found"}`))
+	}))
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	_, err := client.GetDocument("abc")
+	if err == nil || err.Error() != `GET /threads/abc: API error 404: {"error": "Not found"}` {
+		t.Errorf("Expected the method and endpoint in the error, got %v", err)
+	}
+
+	_, err = client.SearchDocuments("secret plans", 5)
+	if err == nil || !strings.HasPrefix(err.Error(), "GET /threads/search: API error 404") || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected the endpoint without its query string, got %v", err)
+	}
+
+	// Network failures name the request too
+	server.Close()
+	_, err = client.RenameDocument("abc", "New title")
+	if err == nil || !strings.HasPrefix(err.Error(), "POST /threads/edit-document: failed to make request") {
+		t.Errorf("Expected the method and endpoint in the network error, got %v", err)
+	}
+	_, err = client.SearchDocuments("secret plans", 5)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected the network error without the query string, got %v", err)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || !strings.HasSuffix(urlErr.URL, "/threads/search") {
+		t.Errorf("Expected a *url.Error with the redacted URL, got %#v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package quip
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// maxConditionalEntries bounds how many documents WithConditionalRequests remembers
+const maxConditionalEntries = 100
+
+// WithConditionalRequests makes GetDocument remember each document's ETag and send it as
+// If-None-Match on the next fetch. When Quip answers 304 Not Modified the remembered copy is
+// returned without transferring the document again. Responses without an ETag are simply
+// not remembered. Unlike WithDocumentCache, documents are always revalidated with Quip.
+// With WithCache the remembered copies are also kept in the cache, so a persistent cache
+// spares unchanged documents from being downloaded again after a restart.
+func WithConditionalRequests() Option {
+	return func(c *Client) {
+		c.validators = &validatorStore{entries: make(map[string]validatedDocument)}
+	}
+}
+
+// validatedDocument is a document together with the ETag it was served with
+type validatedDocument struct {
+	etag string
+	doc  Document
+}
+
+// cachedValidator is a validatedDocument as kept in the client's cache
+type cachedValidator struct {
+	ETag     string   `json:"etag"`
+	Document Document `json:"document"`
+}
+
+// validatorStore holds the last ETag-bearing response for each document
+type validatorStore struct {
+	mu      sync.Mutex
+	entries map[string]validatedDocument
+}
+
+func (v *validatorStore) get(id string) (validatedDocument, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.entries[id]
+	return entry, ok
+}
+
+func (v *validatorStore) set(id, etag string, doc Document) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.entries[id]; !ok && len(v.entries) >= maxConditionalEntries {
+		// Evict an arbitrary entry; the cost of a miss is only a full fetch
+		for key := range v.entries {
+			delete(v.entries, key)
+			break
+		}
+	}
+	v.entries[id] = validatedDocument{etag: etag, doc: doc}
+}
+
+func (v *validatorStore) delete(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.entries, id)
+}
+
+// fetchDocumentConditional fetches a document, revalidating any remembered copy with If-None-Match
+func (c *Client) fetchDocumentConditional(id string) (*Document, error) {
+	endpoint := fmt.Sprintf("/threads/%s", id)
+	req, err := http.NewRequestWithContext(c.context(), "GET", c.baseURL+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	cached, haveCached := c.validators.get(id)
+	if !haveCached {
+		var stored cachedValidator
+		if c.cacheGet(validatorCacheKey(id), &stored) && stored.ETag != "" {
+			cached, haveCached = validatedDocument{etag: stored.ETag, doc: stored.Document}, true
+		}
+	}
+	if haveCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := c.do(req, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		if !haveCached {
+			return nil, fmt.Errorf("unexpected 304 Not Modified for document %s", id)
+		}
+		doc := cached.doc
+		return &doc, nil
+	}
+
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.validators.set(id, etag, *doc)
+		c.cacheSet(validatorCacheKey(id), cachedValidator{ETag: etag, Document: *doc})
+	} else if haveCached {
+		c.validators.delete(id)
+		if c.cache != nil {
+			c.cache.Delete(validatorCacheKey(id))
+		}
+	}
+
+	return doc, nil
+}
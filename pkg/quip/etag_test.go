@@ -0,0 +1,141 @@
+package quip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetDocument_NotModified(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			if got := r.Header.Get("If-None-Match"); got != "" {
+				t.Errorf("Expected no If-None-Match on first fetch, got %q", got)
+			}
+		case 2:
+			if got := r.Header.Get("If-None-Match"); got != `"v1"` {
+				t.Errorf("Expected If-None-Match \"v1\", got %q", got)
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{
+			Thread: Document{ID: "doc123", Title: "Cached Doc"},
+			HTML:   "<p>content</p>",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithConditionalRequests())
+	client.baseURL = server.URL
+
+	first, err := client.GetDocument("doc123")
+	if err != nil {
+		t.Fatalf("First GetDocument failed: %v", err)
+	}
+
+	second, err := client.GetDocument("doc123")
+	if err != nil {
+		t.Fatalf("Second GetDocument failed: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if second.Title != first.Title || second.HTML != "<p>content</p>" {
+		t.Errorf("Expected cached document on 304, got %+v", second)
+	}
+}
+
+func TestClient_GetDocument_NoETag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("If-None-Match"); got != "" {
+			t.Errorf("Expected no If-None-Match without a prior ETag, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithConditionalRequests())
+	client.baseURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetDocument("doc123"); err != nil {
+			t.Fatalf("GetDocument failed: %v", err)
+		}
+	}
+}
+
+func TestClient_GetDocument_ETagInvalidatedByEdit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/threads/doc123" {
+			if got := r.Header.Get("If-None-Match"); got != "" {
+				t.Errorf("Expected no If-None-Match after an edit, got %q", got)
+			}
+			w.Header().Set("ETag", `"v1"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithConditionalRequests())
+	client.baseURL = server.URL
+
+	if _, err := client.GetDocument("doc123"); err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+	if _, err := client.AppendToDocument("doc123", "note", ""); err != nil {
+		t.Fatalf("AppendToDocument failed: %v", err)
+	}
+	if _, err := client.GetDocument("doc123"); err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+}
+
+func TestClient_GetDocument_ETagPersistedInCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			if got := r.Header.Get("If-None-Match"); got != `"v1"` {
+				t.Errorf("Expected the persisted If-None-Match \"v1\", got %q", got)
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{
+			Thread: Document{ID: "doc123", Title: "Cached Doc"},
+			HTML:   "<p>content</p>",
+		})
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	first := NewClient("test-token", WithConditionalRequests(), WithCache(cache, 0))
+	first.baseURL = server.URL
+	if _, err := first.GetDocument("doc123"); err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+
+	// A new client (e.g. after a restart) revalidates from the cache instead of downloading
+	restarted := NewClient("test-token", WithConditionalRequests(), WithCache(cache, 0))
+	restarted.baseURL = server.URL
+	doc, err := restarted.GetDocument("doc123")
+	if err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+	if doc.HTML != "<p>content</p>" {
+		t.Errorf("Expected the persisted document on 304, got %+v", doc)
+	}
+}
@@ -0,0 +1,115 @@
+package quip
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// expandQuery asks Quip to embed the user and folder objects a thread refers to
+const expandQuery = "expand=users,folders"
+
+// GetDocumentExpanded returns a document together with its author, members and folders in
+// Users and Folders, so "who owns this" needs no GetUser calls. With API v1 the thread is
+// requested in expanded form; anything the response doesn't embed is filled in with at most
+// one batched GetUsers and one GetFolders call. A document served from the cache, or fetched
+// through API v2 or a conditional request, has no embedded data and is filled in the same way.
+func (c *Client) GetDocumentExpanded(id string) (*Document, error) {
+	doc, err := c.getDocumentExpanded(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.fillExpandedUsers(doc); err != nil {
+		return nil, err
+	}
+	if err := c.fillExpandedFolders(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// getDocumentExpanded fetches a thread in expanded form where the API supports it, going
+// through GetDocument otherwise
+func (c *Client) getDocumentExpanded(id string) (*Document, error) {
+	if c.APIVersion() == APIVersion2 || c.validators != nil {
+		return c.GetDocument(id)
+	}
+	if c.cacheDocuments {
+		var cached Document
+		if c.cacheGet(documentCacheKey(id), &cached) {
+			return &cached, nil
+		}
+	}
+
+	endpoint := fmt.Sprintf("/threads/%s?%s", url.PathEscape(id), expandQuery)
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cacheDocuments {
+		c.cacheSet(documentCacheKey(id), doc)
+	}
+	return doc, nil
+}
+
+// Author returns the document's author from its expanded users, if present
+func (d *Document) Author() (User, bool) {
+	user, ok := d.Users[d.AuthorID]
+	return user, ok
+}
+
+// fillExpandedUsers adds the author and members that the response didn't embed to doc.Users
+func (c *Client) fillExpandedUsers(doc *Document) error {
+	var missing []string
+	for _, id := range append([]string{doc.AuthorID}, doc.ExpandedUserIds...) {
+		if _, ok := doc.Users[id]; id != "" && !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	users, err := c.GetUsers(missing)
+	if err != nil {
+		return fmt.Errorf("failed to get document members: %w", err)
+	}
+	if doc.Users == nil {
+		doc.Users = make(map[string]User, len(users))
+	}
+	for id, user := range users {
+		doc.Users[id] = user
+	}
+	return nil
+}
+
+// fillExpandedFolders adds the document's folder to doc.Folders if the response didn't embed it
+func (c *Client) fillExpandedFolders(doc *Document) error {
+	if _, ok := doc.Folders[doc.SharedFolderID]; doc.SharedFolderID == "" || ok {
+		return nil
+	}
+
+	folders, err := c.GetFolders([]string{doc.SharedFolderID})
+	if err != nil {
+		return fmt.Errorf("failed to get document folder: %w", err)
+	}
+	if doc.Folders == nil {
+		doc.Folders = make(map[string]Folder, len(folders))
+	}
+	for id, folder := range folders {
+		doc.Folders[id] = folder.Folder
+	}
+	return nil
+}
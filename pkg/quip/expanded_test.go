@@ -0,0 +1,101 @@
+package quip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_GetDocumentExpanded(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc1":
+			if r.URL.RawQuery != expandQuery {
+				t.Errorf("Expected query %q, got %q", expandQuery, r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{
+				"thread": {"id": "doc1", "title": "Roadmap", "author_id": "ann", "shared_folder_id": "fold1"},
+				"html": "<p>Plan</p>",
+				"expanded_user_ids": ["ann", "bob"],
+				"users": {
+					"ann": {"id": "ann", "name": "Ann Lee", "email": "ann@example.com"},
+					"bob": {"id": "bob", "name": "Bob Roy"}
+				},
+				"folders": {
+					"fold1": {"id": "fold1", "title": "Product", "creator_id": "ann"}
+				}
+			}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	doc, err := client.GetDocumentExpanded("doc1")
+	if err != nil {
+		t.Fatalf("GetDocumentExpanded failed: %v", err)
+	}
+
+	author, ok := doc.Author()
+	if !ok || author.Name != "Ann Lee" || author.Email != "ann@example.com" {
+		t.Errorf("Expected author Ann Lee from the expanded users, got %+v (found: %v)", author, ok)
+	}
+	if doc.Users["bob"].Name != "Bob Roy" {
+		t.Errorf("Expected member Bob Roy, got %+v", doc.Users)
+	}
+	if doc.Folders["fold1"].Title != "Product" {
+		t.Errorf("Expected folder Product, got %+v", doc.Folders)
+	}
+	if doc.HTML != "<p>Plan</p>" || len(doc.ExpandedUserIds) != 2 {
+		t.Errorf("Expected the HTML and member IDs beside the thread to be merged, got %q and %v", doc.HTML, doc.ExpandedUserIds)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected a single request, got %d", got)
+	}
+}
+
+func TestClient_GetDocumentExpanded_FillsGaps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc1":
+			// Only the author is embedded
+			_, _ = w.Write([]byte(`{
+				"thread": {"id": "doc1", "author_id": "ann", "shared_folder_id": "fold1"},
+				"expanded_user_ids": ["ann", "bob", "cy"],
+				"users": {"ann": {"id": "ann", "name": "Ann Lee"}}
+			}`))
+		case "/users/":
+			if ids := r.URL.Query().Get("ids"); ids != "bob,cy" {
+				t.Errorf("Expected one lookup for the missing members, got ids=%s", ids)
+			}
+			_, _ = w.Write([]byte(`{"bob": {"id": "bob", "name": "Bob Roy"}, "cy": {"id": "cy", "name": "Cy Tan"}}`))
+		case "/folders/":
+			_, _ = w.Write([]byte(`{"fold1": {"folder": {"id": "fold1", "title": "Product"}}}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	doc, err := client.GetDocumentExpanded("doc1")
+	if err != nil {
+		t.Fatalf("GetDocumentExpanded failed: %v", err)
+	}
+	for id, name := range map[string]string{"ann": "Ann Lee", "bob": "Bob Roy", "cy": "Cy Tan"} {
+		if doc.Users[id].Name != name {
+			t.Errorf("Expected user %s to be %s, got %+v", id, name, doc.Users[id])
+		}
+	}
+	if doc.Folders["fold1"].Title != "Product" {
+		t.Errorf("Expected folder Product, got %+v", doc.Folders)
+	}
+}
@@ -0,0 +1,319 @@
+package quip
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Kinds of top-level folder returned by GetRootFolders
+const (
+	FolderKindPrivate = "private"
+	FolderKindShared  = "shared"
+	FolderKindGroup   = "group"
+)
+
+// Folder represents a Quip folder
+type Folder struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Color     string `json:"color,omitempty"`
+	ParentID  string `json:"parent_id,omitempty"`
+	CreatorID string `json:"creator_id"`
+	Created   int64  `json:"created_usec"`
+	Updated   int64  `json:"updated_usec"`
+}
+
+// FolderChild is an entry in a folder: either a thread or a subfolder
+type FolderChild struct {
+	ThreadID string `json:"thread_id,omitempty"`
+	FolderID string `json:"folder_id,omitempty"`
+}
+
+// FolderData is a folder together with its members and children, as returned by /folders
+type FolderData struct {
+	Folder    Folder        `json:"folder"`
+	MemberIDs []string      `json:"member_ids,omitempty"`
+	Children  []FolderChild `json:"children,omitempty"`
+}
+
+// ThreadIDs returns the IDs of the threads directly inside the folder
+func (f *FolderData) ThreadIDs() []string {
+	var ids []string
+	for _, child := range f.Children {
+		if child.ThreadID != "" {
+			ids = append(ids, child.ThreadID)
+		}
+	}
+	return ids
+}
+
+// SubfolderIDs returns the IDs of the folders directly inside the folder
+func (f *FolderData) SubfolderIDs() []string {
+	var ids []string
+	for _, child := range f.Children {
+		if child.FolderID != "" {
+			ids = append(ids, child.FolderID)
+		}
+	}
+	return ids
+}
+
+// RootFolder is one of the current user's top-level folders
+type RootFolder struct {
+	Kind string // FolderKindPrivate, FolderKindShared or FolderKindGroup
+	FolderData
+}
+
+// How a user has access to a folder, as reported by UserFolder.Access. Quip's folder API
+// reports membership rather than edit or view rights, so these describe where access comes from.
+const (
+	FolderAccessOwner  = "owner"  // the user's private folder, or a folder they created
+	FolderAccessMember = "member" // a shared folder the user is a member of
+	FolderAccessGroup  = "group"  // a group folder the user belongs to
+)
+
+// maxFoldersPerRequest is how many folder IDs GetUserFolders asks for in one /folders request
+const maxFoldersPerRequest = 100
+
+// UserFolder is a top-level folder a user can see, with how they come to see it
+type UserFolder struct {
+	Access string // FolderAccessOwner, FolderAccessMember or FolderAccessGroup
+	FolderData
+}
+
+// GetFolder returns a folder and its children
+func (c *Client) GetFolder(folderID string) (*FolderData, error) {
+	endpoint := fmt.Sprintf("/folders/%s", url.PathEscape(folderID))
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var folder FolderData
+	if err := json.NewDecoder(resp.Body).Decode(&folder); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &folder, nil
+}
+
+// GetFolders fetches several folders in one request, keyed by folder ID.
+// Folders the user can't access are omitted from the result.
+func (c *Client) GetFolders(folderIDs []string) (map[string]FolderData, error) {
+	var ids []string
+	for _, id := range folderIDs {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return map[string]FolderData{}, nil
+	}
+
+	endpoint := fmt.Sprintf("/folders/?ids=%s", url.QueryEscape(strings.Join(ids, ",")))
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var folders map[string]FolderData
+	if err := json.NewDecoder(resp.Body).Decode(&folders); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return folders, nil
+}
+
+// GetRootFolders returns the current user's top-level folders: their private folder,
+// followed by shared and group folders in the order Quip lists them
+func (c *Client) GetRootFolders() ([]RootFolder, error) {
+	user, err := c.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	type root struct{ id, kind string }
+	var roots []root
+	if user.PrivateFolderID != "" {
+		roots = append(roots, root{user.PrivateFolderID, FolderKindPrivate})
+	}
+	for _, id := range user.SharedFolderIDs {
+		roots = append(roots, root{id, FolderKindShared})
+	}
+	for _, id := range user.GroupFolderIDs {
+		roots = append(roots, root{id, FolderKindGroup})
+	}
+
+	ids := make([]string, 0, len(roots))
+	for _, r := range roots {
+		ids = append(ids, r.id)
+	}
+
+	folders, err := c.GetFolders(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RootFolder, 0, len(roots))
+	for _, r := range roots {
+		if folder, ok := folders[r.id]; ok {
+			result = append(result, RootFolder{Kind: r.kind, FolderData: folder})
+		}
+	}
+
+	return result, nil
+}
+
+// GetUserFolders returns the top-level folders user can access, in the order of their private,
+// shared and group folder IDs. Quip only reports those IDs for the current user; for anyone
+// else the current user's folders are checked for their membership as well, so the result
+// covers at least the folders the two of you share. Folders the current user can't read are
+// omitted.
+func (c *Client) GetUserFolders(user *User) ([]UserFolder, error) {
+	kinds := make(map[string]string)
+	var ids []string
+	add := func(id, access string) {
+		if _, ok := kinds[id]; id != "" && !ok {
+			kinds[id] = access
+			ids = append(ids, id)
+		}
+	}
+
+	add(user.PrivateFolderID, FolderAccessOwner)
+	for _, id := range user.SharedFolderIDs {
+		add(id, FolderAccessMember)
+	}
+	for _, id := range user.GroupFolderIDs {
+		add(id, FolderAccessGroup)
+	}
+
+	current, err := c.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	// Candidates found through the current user only count if user is among the members
+	checkMembership := make(map[string]bool)
+	if current.ID != user.ID {
+		candidate := func(id, access string) {
+			if _, ok := kinds[id]; !ok {
+				checkMembership[id] = true
+				add(id, access)
+			}
+		}
+		for _, id := range current.SharedFolderIDs {
+			candidate(id, FolderAccessMember)
+		}
+		for _, id := range current.GroupFolderIDs {
+			candidate(id, FolderAccessGroup)
+		}
+	}
+
+	folders := make(map[string]FolderData, len(ids))
+	for start := 0; start < len(ids); start += maxFoldersPerRequest {
+		batch, err := c.GetFolders(ids[start:min(start+maxFoldersPerRequest, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		for id, folder := range batch {
+			folders[id] = folder
+		}
+	}
+
+	var result []UserFolder
+	for _, id := range ids {
+		folder, ok := folders[id]
+		if !ok {
+			continue
+		}
+		if checkMembership[id] && !slices.Contains(folder.MemberIDs, user.ID) {
+			continue
+		}
+
+		access := kinds[id]
+		if folder.Folder.CreatorID == user.ID {
+			access = FolderAccessOwner
+		}
+		result = append(result, UserFolder{Access: access, FolderData: folder})
+	}
+
+	return result, nil
+}
+
+// AddToFolder adds a thread to a folder, keeping it in the folders it is already in, and
+// returns the IDs of the folders the thread is in afterwards
+func (c *Client) AddToFolder(threadID, folderID string) ([]string, error) {
+	return c.changeFolderMembership("/threads/add-members", threadID, folderID)
+}
+
+// RemoveFromFolder removes a thread from a folder and returns the IDs of the folders the
+// thread is still in
+func (c *Client) RemoveFromFolder(threadID, folderID string) ([]string, error) {
+	return c.changeFolderMembership("/threads/remove-members", threadID, folderID)
+}
+
+// MoveToFolder adds a thread to folderID and, with removeFromCurrent, takes it out of the
+// folder it currently lives in (its SharedFolderID), so it ends up in the target instead of
+// both. It returns the IDs of the folders the thread is in afterwards. In a dry run both
+// requests are recorded before ErrDryRun is returned.
+func (c *Client) MoveToFolder(threadID, folderID string, removeFromCurrent bool) ([]string, error) {
+	var current string
+	if removeFromCurrent {
+		// Read the folder from Quip: a stale cached copy would remove the thread from the wrong one
+		doc, err := c.fetchDocument(threadID)
+		if err != nil {
+			return nil, err
+		}
+		current = doc.SharedFolderID
+	}
+
+	folderIDs, err := c.AddToFolder(threadID, folderID)
+	// In a dry run carry on, so the removal is recorded too
+	dryRun := errors.Is(err, ErrDryRun)
+	if err != nil && !dryRun {
+		return nil, err
+	}
+
+	if current == "" || current == folderID {
+		return folderIDs, err
+	}
+	folderIDs, err = c.RemoveFromFolder(threadID, current)
+	if dryRun || errors.Is(err, ErrDryRun) {
+		return nil, ErrDryRun
+	}
+	if err != nil {
+		return nil, fmt.Errorf("added to folder %s but failed to remove from folder %s: %w", folderID, current, err)
+	}
+	return folderIDs, nil
+}
+
+// changeFolderMembership posts a thread membership change for a folder and returns the
+// thread's folders from the response
+func (c *Client) changeFolderMembership(endpoint, threadID, folderID string) ([]string, error) {
+	formData := map[string]string{
+		"thread_id":  threadID,
+		"member_ids": folderID,
+	}
+
+	resp, err := c.makeFormRequest("POST", endpoint, formData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	c.invalidateDocument(threadID)
+
+	var thread RecentThreadData
+	if err := json.NewDecoder(resp.Body).Decode(&thread); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return thread.SharedFolderIds, nil
+}
@@ -0,0 +1,216 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_GetFolder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/folders/fold1" {
+			t.Errorf("Expected path /folders/fold1, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"folder": {"id": "fold1", "title": "Projects", "creator_id": "user1"},
+			"member_ids": ["user1"],
+			"children": [{"thread_id": "doc1"}, {"folder_id": "fold2"}, {"thread_id": "doc2"}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	folder, err := client.GetFolder("fold1")
+	if err != nil {
+		t.Fatalf("GetFolder failed: %v", err)
+	}
+
+	if folder.Folder.Title != "Projects" {
+		t.Errorf("Expected title Projects, got %s", folder.Folder.Title)
+	}
+	if threads := folder.ThreadIDs(); len(threads) != 2 || threads[0] != "doc1" || threads[1] != "doc2" {
+		t.Errorf("Expected threads [doc1 doc2], got %v", threads)
+	}
+	if subfolders := folder.SubfolderIDs(); len(subfolders) != 1 || subfolders[0] != "fold2" {
+		t.Errorf("Expected subfolders [fold2], got %v", subfolders)
+	}
+}
+
+func TestClient_GetRootFolders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/current":
+			_ = json.NewEncoder(w).Encode(User{
+				ID:              "user1",
+				PrivateFolderID: "priv",
+				SharedFolderIDs: []string{"shared1", "gone"},
+				GroupFolderIDs:  []string{"group1"},
+			})
+		case "/folders/":
+			if ids := r.URL.Query().Get("ids"); ids != "priv,shared1,gone,group1" {
+				t.Errorf("Expected ids priv,shared1,gone,group1, got %s", ids)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]FolderData{
+				"group1":  {Folder: Folder{ID: "group1", Title: "Team"}},
+				"priv":    {Folder: Folder{ID: "priv", Title: "Private"}},
+				"shared1": {Folder: Folder{ID: "shared1", Title: "Shared"}},
+			})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	roots, err := client.GetRootFolders()
+	if err != nil {
+		t.Fatalf("GetRootFolders failed: %v", err)
+	}
+
+	expected := []struct{ id, kind string }{
+		{"priv", FolderKindPrivate},
+		{"shared1", FolderKindShared},
+		{"group1", FolderKindGroup},
+	}
+	if len(roots) != len(expected) {
+		t.Fatalf("Expected %d root folders, got %d", len(expected), len(roots))
+	}
+	for i, e := range expected {
+		if roots[i].Folder.ID != e.id || roots[i].Kind != e.kind {
+			t.Errorf("Expected root %d to be %s (%s), got %s (%s)", i, e.id, e.kind, roots[i].Folder.ID, roots[i].Kind)
+		}
+	}
+}
+
+func TestClient_MoveToFolder(t *testing.T) {
+	tests := []struct {
+		name              string
+		removeFromCurrent bool
+		expected          []string
+	}{
+		{name: "move", removeFromCurrent: true, expected: []string{"target"}},
+		{name: "add only", removeFromCurrent: false, expected: []string{"current", "target"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folders := []string{"current"}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/threads/doc1":
+					if !tt.removeFromCurrent {
+						t.Error("Expected no lookup of the current folder when only adding")
+					}
+				case "/threads/add-members":
+					if r.FormValue("thread_id") != "doc1" || r.FormValue("member_ids") != "target" {
+						t.Errorf("Unexpected add-members form %v", r.PostForm)
+					}
+					folders = append(folders, r.FormValue("member_ids"))
+				case "/threads/remove-members":
+					if r.FormValue("member_ids") != "current" {
+						t.Errorf("Expected removal from the current folder, got %q", r.FormValue("member_ids"))
+					}
+					folders = folders[1:]
+				default:
+					t.Errorf("Unexpected request %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(RecentThreadData{
+					Thread:          Document{ID: "doc1", SharedFolderID: "current"},
+					SharedFolderIds: folders,
+				})
+			}))
+			defer server.Close()
+
+			// A stale cached copy still lists the folder the document was in before
+			client := NewClient("test-token", WithBaseURL(server.URL), WithCache(NewMemoryCache(), 0), WithDocumentCache())
+			client.cacheSet(documentCacheKey("doc1"), Document{ID: "doc1", SharedFolderID: "previous"})
+
+			got, err := client.MoveToFolder("doc1", "target", tt.removeFromCurrent)
+			if err != nil {
+				t.Fatalf("MoveToFolder failed: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected folders %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClient_MoveToFolder_DryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc1", SharedFolderID: "current"}})
+	}))
+	defer server.Close()
+
+	recorder := &DryRunRecorder{}
+	client := NewClient("test-token", WithBaseURL(server.URL)).WithContext(WithDryRunRecorder(context.Background(), recorder))
+
+	if _, err := client.MoveToFolder("doc1", "target", true); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("Expected ErrDryRun, got %v", err)
+	}
+
+	var endpoints []string
+	for _, request := range recorder.Requests() {
+		endpoints = append(endpoints, request.Endpoint)
+	}
+	if strings.Join(endpoints, ",") != "/threads/add-members,/threads/remove-members" {
+		t.Errorf("Expected both the add and the removal to be recorded, got %v", endpoints)
+	}
+}
+
+func TestClient_GetUserFolders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/current":
+			_ = json.NewEncoder(w).Encode(User{ID: "me", SharedFolderIDs: []string{"shared1", "mine"}, GroupFolderIDs: []string{"group1"}})
+		case "/folders/":
+			if ids := r.URL.Query().Get("ids"); ids != "shared2,shared1,mine,group1" {
+				t.Errorf("Expected ids shared2,shared1,mine,group1, got %s", ids)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]FolderData{
+				"shared1": {Folder: Folder{ID: "shared1", Title: "Design"}, MemberIDs: []string{"me", "ann"}},
+				"shared2": {Folder: Folder{ID: "shared2", Title: "Ann's notes", CreatorID: "ann"}, MemberIDs: []string{"ann"}},
+				"mine":    {Folder: Folder{ID: "mine", Title: "Mine"}, MemberIDs: []string{"me"}},
+				"group1":  {Folder: Folder{ID: "group1", Title: "Engineering"}, MemberIDs: []string{"me", "ann"}},
+			})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	// Another user: their own folder IDs plus the current user's folders they are a member of
+	folders, err := client.GetUserFolders(&User{ID: "ann", SharedFolderIDs: []string{"shared2"}})
+	if err != nil {
+		t.Fatalf("GetUserFolders failed: %v", err)
+	}
+
+	expected := []struct{ id, access string }{
+		{"shared2", FolderAccessOwner},
+		{"shared1", FolderAccessMember},
+		{"group1", FolderAccessGroup},
+	}
+	if len(folders) != len(expected) {
+		t.Fatalf("Expected %d folders, got %+v", len(expected), folders)
+	}
+	for i, want := range expected {
+		if folders[i].Folder.ID != want.id || folders[i].Access != want.access {
+			t.Errorf("Folder %d: expected %s (%s), got %s (%s)", i, want.id, want.access, folders[i].Folder.ID, folders[i].Access)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package quip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// DocumentVersion is one entry in a document's edit history
+type DocumentVersion struct {
+	ID         string   `json:"id"`
+	AuthorID   string   `json:"author_id"`
+	Created    int64    `json:"created_usec"`
+	SectionIDs []string `json:"section_ids,omitempty"`
+}
+
+// HistoryOptions controls paging through a document's edit history
+type HistoryOptions struct {
+	// Limit is the maximum number of versions to return (Quip's default when 0)
+	Limit int
+	// Cursor continues from a previous page's NextCursor
+	Cursor string
+}
+
+// HistoryPage is one page of a document's edit history, newest first
+type HistoryPage struct {
+	Versions   []DocumentVersion
+	NextCursor string // empty when there are no more versions
+}
+
+// historyResponse is the API response structure for /2/threads/{id}/edit-history
+type historyResponse struct {
+	History          []DocumentVersion `json:"history"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+// GetDocumentVersions returns a page of a document's edit history with the time and
+// author of each change. Edit history is only available from the v2 API.
+func (c *Client) GetDocumentVersions(threadID string, opts HistoryOptions) (*HistoryPage, error) {
+	params := url.Values{}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+
+	endpoint := fmt.Sprintf("/threads/%s/edit-history", url.PathEscape(threadID))
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := c.makeV2Request("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var history historyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &HistoryPage{
+		Versions:   history.History,
+		NextCursor: history.ResponseMetadata.NextCursor,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package quip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetDocumentVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/2/threads/doc123/edit-history"
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("Expected limit '2', got %s", r.URL.Query().Get("limit"))
+		}
+
+		if r.URL.Query().Get("cursor") != "abc" {
+			t.Errorf("Expected cursor 'abc', got %s", r.URL.Query().Get("cursor"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"history": [
+				{"id": "v2", "author_id": "user456", "created_usec": 1640995300000000},
+				{"id": "v1", "author_id": "user123", "created_usec": 1640995200000000}
+			],
+			"response_metadata": {"next_cursor": "def"}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL+"/1"))
+
+	page, err := client.GetDocumentVersions("doc123", HistoryOptions{Limit: 2, Cursor: "abc"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(page.Versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(page.Versions))
+	}
+
+	if page.Versions[0].AuthorID != "user456" || page.Versions[0].Created != 1640995300000000 {
+		t.Errorf("Expected newest version by user456, got %+v", page.Versions[0])
+	}
+
+	if page.NextCursor != "def" {
+		t.Errorf("Expected next cursor 'def', got %s", page.NextCursor)
+	}
+}
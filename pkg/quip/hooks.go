@@ -0,0 +1,43 @@
+package quip
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// RequestEvent describes an API request that is about to be sent
+type RequestEvent struct {
+	Method   string
+	Endpoint string // path without query string, e.g. /threads/search
+}
+
+// ResponseEvent describes the outcome of an API request
+type ResponseEvent struct {
+	Method     string
+	Endpoint   string
+	StatusCode int           // 0 if no response was received
+	Duration   time.Duration // time until the response headers arrived
+	Err        error         // transport error, or the API error for status >= 400
+}
+
+// Hooks are optional callbacks invoked around every API request, for wiring up
+// metrics or tracing without this package depending on a particular library.
+// Either field may be nil. Callbacks run synchronously on the request path.
+type Hooks struct {
+	OnRequest  func(ctx context.Context, event RequestEvent)
+	OnResponse func(ctx context.Context, event ResponseEvent)
+}
+
+// WithHooks installs instrumentation callbacks on the client
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = hooks
+	}
+}
+
+// hookEndpoint strips the query string so events group by endpoint rather than by arguments
+func hookEndpoint(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	return path
+}
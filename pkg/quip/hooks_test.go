@@ -0,0 +1,73 @@
+package quip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Hooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/threads/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var requests []RequestEvent
+	var responses []ResponseEvent
+	client := NewClient("test-token", WithHooks(Hooks{
+		OnRequest: func(ctx context.Context, event RequestEvent) {
+			requests = append(requests, event)
+		},
+		OnResponse: func(ctx context.Context, event ResponseEvent) {
+			responses = append(responses, event)
+		},
+	}))
+	client.baseURL = server.URL
+
+	if _, err := client.SearchDocuments("notes", 5); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetDocument("missing"); err == nil {
+		t.Fatal("Expected error for missing document, got nil")
+	}
+
+	if len(requests) != 2 || len(responses) != 2 {
+		t.Fatalf("Expected 2 request and 2 response events, got %d and %d", len(requests), len(responses))
+	}
+
+	if requests[0].Method != "GET" || requests[0].Endpoint != "/threads/search" {
+		t.Errorf("Expected GET /threads/search without query, got %s %s", requests[0].Method, requests[0].Endpoint)
+	}
+
+	if responses[0].StatusCode != http.StatusOK || responses[0].Err != nil {
+		t.Errorf("Expected successful response event, got %+v", responses[0])
+	}
+
+	if responses[1].Endpoint != "/threads/missing" || responses[1].StatusCode != http.StatusNotFound || responses[1].Err == nil {
+		t.Errorf("Expected 404 response event with error, got %+v", responses[1])
+	}
+}
+
+func TestClient_NoHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "user123"}`))
+	}))
+	defer server.Close()
+
+	// Only one callback set: the other must be skipped safely
+	client := NewClient("test-token", WithHooks(Hooks{
+		OnRequest: func(ctx context.Context, event RequestEvent) {},
+	}))
+	client.baseURL = server.URL
+
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
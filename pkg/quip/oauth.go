@@ -0,0 +1,136 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// OAuthCredentials lets the client refresh a short-lived OAuth access token
+type OAuthCredentials struct {
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+
+	// OnRotate, if set, is called with the new refresh token when Quip rotates it. The old
+	// token stops working, so the new one has to be persisted to be usable after a restart.
+	OnRotate func(refreshToken string)
+}
+
+// WithOAuthRefresh makes the client treat its token as an OAuth access token: when a request
+// fails with 401 Unauthorized, the token is refreshed with creds and the request retried once.
+// Without this option the token is a personal token and 401s are returned as errors.
+func WithOAuthRefresh(creds OAuthCredentials) Option {
+	return func(c *Client) {
+		c.oauth = &oauthTokenSource{creds: creds}
+	}
+}
+
+// oauthTokenSource holds the current access token, shared by every copy of a client
+// (see WithContext) so a refresh is seen by all of them
+type oauthTokenSource struct {
+	mu          sync.Mutex
+	accessToken string
+	creds       OAuthCredentials
+}
+
+// oauthTokenResponse is the API response structure for /oauth/access_token
+type oauthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// token returns the current access token
+func (o *oauthTokenSource) token() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.accessToken
+}
+
+// accessToken returns the token to authenticate requests with
+func (c *Client) accessToken() string {
+	if c.oauth != nil {
+		return c.oauth.token()
+	}
+	return c.token
+}
+
+// refreshAccessToken exchanges the refresh token for a new access token, unless another
+// request already replaced rejected (the token that got a 401) in the meantime
+func (c *Client) refreshAccessToken(ctx context.Context, rejected string) (string, error) {
+	o := c.oauth
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.accessToken != rejected {
+		return o.accessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", o.creds.RefreshToken)
+	form.Set("client_id", o.creds.ClientID)
+	form.Set("client_secret", o.creds.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/oauth/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("POST /oauth/access_token: failed to make request: %w", redactURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", &APIError{Method: "POST", Endpoint: "/oauth/access_token", StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+	}
+
+	var token oauthTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("no access token in response: %s %s", token.Error, token.Description)
+	}
+
+	o.accessToken = token.AccessToken
+	// Quip may rotate the refresh token; the old one stops working once a new one is issued
+	if token.RefreshToken != "" && token.RefreshToken != o.creds.RefreshToken {
+		o.creds.RefreshToken = token.RefreshToken
+		if o.creds.OnRotate != nil {
+			o.creds.OnRotate(token.RefreshToken)
+		}
+	}
+
+	return o.accessToken, nil
+}
+
+// rewindRequest returns a copy of req that can be sent again, with a fresh body
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body can't be replayed")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry.Body = body
+	return retry, nil
+}
@@ -0,0 +1,104 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_OAuthRefresh(t *testing.T) {
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+
+		if r.URL.Path == "/oauth/access_token" {
+			atomic.AddInt32(&refreshes, 1)
+			if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != "refresh-1" ||
+				r.FormValue("client_id") != "client" || r.FormValue("client_secret") != "secret" {
+				t.Errorf("Unexpected refresh request %v", r.Form)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(oauthTokenResponse{AccessToken: "fresh-token", RefreshToken: "refresh-2"})
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "Token expired"}`))
+			return
+		}
+
+		// The retried request must carry the original body
+		if r.FormValue("content") != "Looks good" {
+			t.Errorf("Expected content 'Looks good' on retry, got %q", r.FormValue("content"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Comment{ID: "msg123", Text: "Looks good"})
+	}))
+	defer server.Close()
+
+	var rotated []string
+	client := NewClient("expired-token", WithBaseURL(server.URL), WithOAuthRefresh(OAuthCredentials{
+		RefreshToken: "refresh-1",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		OnRotate:     func(refreshToken string) { rotated = append(rotated, refreshToken) },
+	}))
+
+	comment, err := client.AddComment("doc123", "Looks good")
+	if err != nil {
+		t.Fatalf("Expected the request to succeed after a refresh, got %v", err)
+	}
+	if comment.ID != "msg123" {
+		t.Errorf("Expected comment msg123, got %+v", comment)
+	}
+
+	// Copies made with WithContext share the refreshed token
+	if _, err := client.WithContext(context.Background()).AddComment("doc123", "Looks good"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if refreshes != 1 {
+		t.Errorf("Expected 1 refresh, got %d", refreshes)
+	}
+	if client.oauth.creds.RefreshToken != "refresh-2" {
+		t.Errorf("Expected the rotated refresh token to be kept, got %q", client.oauth.creds.RefreshToken)
+	}
+	if len(rotated) != 1 || rotated[0] != "refresh-2" {
+		t.Errorf("Expected the rotated refresh token to be handed to OnRotate once, got %v", rotated)
+	}
+}
+
+func TestClient_OAuthRefresh_Failures(t *testing.T) {
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/access_token" {
+			atomic.AddInt32(&refreshes, 1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	// Personal tokens are never refreshed
+	client := NewClient("personal-token", WithBaseURL(server.URL))
+	if _, err := client.GetCurrentUser(); err == nil || !strings.Contains(err.Error(), "API error 401") {
+		t.Errorf("Expected a 401 error, got %v", err)
+	}
+	if refreshes != 0 {
+		t.Errorf("Expected no refresh for a personal token, got %d", refreshes)
+	}
+
+	client = NewClient("expired-token", WithBaseURL(server.URL), WithOAuthRefresh(OAuthCredentials{RefreshToken: "revoked"}))
+	_, err := client.GetCurrentUser()
+	if err == nil || !strings.Contains(err.Error(), "API error 401") || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Expected the 401 and the refresh failure, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package quip
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pacing defaults used by WithRatePacing
+const (
+	// DefaultPacingReserve is how many requests may remain in the rate-limit window before
+	// requests are spread out over the rest of it
+	DefaultPacingReserve = 10
+	// maxPacingWait caps how long a single request is held back. Quip also has hourly limits;
+	// rather than stall a tool call for most of an hour, the request is sent and Quip's 429
+	// reports the problem.
+	maxPacingWait = time.Minute
+)
+
+// requestPacer spaces out requests once the rate-limit headers show the quota running low.
+// It works like a token bucket holding the requests Quip says remain, refilled when the
+// window resets: while more than reserve remain requests go out immediately, and after that
+// the remaining tokens are released evenly over the time left in the window, so a long batch
+// job slows down instead of running into 429s. It is shared by copies of a Client made with
+// WithContext.
+type requestPacer struct {
+	reserve int
+
+	mu   sync.Mutex
+	next time.Time // the earliest time the next paced request may be sent
+	now  func() time.Time
+}
+
+// WithRatePacing makes the client pace its requests using the X-Ratelimit-* headers of
+// earlier responses. Once reserve or fewer requests remain in the current window, requests
+// are spaced evenly until it resets; with none left they wait for the reset (for at most a
+// minute). A non-positive reserve selects DefaultPacingReserve.
+func WithRatePacing(reserve int) Option {
+	return func(c *Client) {
+		if reserve <= 0 {
+			reserve = DefaultPacingReserve
+		}
+		c.pacer = &requestPacer{reserve: reserve, now: time.Now}
+	}
+}
+
+// delay returns how long to hold back a request given the latest rate-limit state, and
+// reserves its slot. A nil pacer never delays.
+func (p *requestPacer) delay(limit RateLimit, seen bool) time.Duration {
+	if p == nil || !seen || limit.Reset.IsZero() || limit.Remaining > p.reserve {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	window := limit.Reset.Sub(now)
+	if window <= 0 {
+		// The headers are from a window that has already reset
+		return 0
+	}
+	if limit.Remaining <= 0 {
+		return min(window, maxPacingWait)
+	}
+
+	interval := window / time.Duration(limit.Remaining+1)
+	start := now
+	if p.next.After(start) {
+		start = p.next
+	}
+	// Don't book slots past the cap, or a queue of callers would all be released at once
+	// when their waits are cut short
+	if latest := now.Add(maxPacingWait); start.After(latest) {
+		start = latest
+	}
+	p.next = start.Add(interval)
+	return start.Sub(now)
+}
+
+// pace waits until the request may be sent, returning early with ctx's error if it is
+// cancelled first
+func (c *Client) pace(ctx context.Context) error {
+	limit, seen := c.RateLimit()
+	wait := c.pacer.delay(limit, seen)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+package quip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestPacer_Delay(t *testing.T) {
+	now := time.Unix(1640995200, 0)
+	pacer := &requestPacer{reserve: 10, now: func() time.Time { return now }}
+	reset := now.Add(30 * time.Second)
+
+	// Plenty left: no pacing
+	if d := pacer.delay(RateLimit{Limit: 50, Remaining: 40, Reset: reset}, true); d != 0 {
+		t.Errorf("Expected no delay with 40 remaining, got %s", d)
+	}
+	// No headers seen yet, or a window that has already reset
+	if d := pacer.delay(RateLimit{}, false); d != 0 {
+		t.Errorf("Expected no delay without rate-limit headers, got %s", d)
+	}
+	if d := pacer.delay(RateLimit{Limit: 50, Remaining: 1, Reset: now.Add(-time.Second)}, true); d != 0 {
+		t.Errorf("Expected no delay after the window reset, got %s", d)
+	}
+
+	// With 5 left in 30s, requests are released every 5s
+	low := RateLimit{Limit: 50, Remaining: 5, Reset: reset}
+	for i, want := range []time.Duration{0, 5 * time.Second, 10 * time.Second} {
+		if d := pacer.delay(low, true); d != want {
+			t.Errorf("Request %d: expected delay %s, got %s", i+1, want, d)
+		}
+	}
+
+	// None left: wait for the reset, but never longer than maxPacingWait
+	if d := pacer.delay(RateLimit{Limit: 50, Remaining: 0, Reset: reset}, true); d != 30*time.Second {
+		t.Errorf("Expected to wait for the reset, got %s", d)
+	}
+	if d := pacer.delay(RateLimit{Limit: 750, Remaining: 0, Reset: now.Add(time.Hour)}, true); d != maxPacingWait {
+		t.Errorf("Expected the wait to be capped at %s, got %s", maxPacingWait, d)
+	}
+
+	// Reservations never run past the cap, so later callers are still spread out
+	pacer = &requestPacer{reserve: 10, now: func() time.Time { return now }}
+	slow := RateLimit{Limit: 750, Remaining: 1, Reset: now.Add(time.Hour)}
+	for i := 0; i < 5; i++ {
+		if d := pacer.delay(slow, true); d > maxPacingWait {
+			t.Errorf("Request %d: expected at most %s, got %s", i+1, maxPacingWait, d)
+		}
+	}
+	if latest := now.Add(maxPacingWait + 30*time.Minute); pacer.next.After(latest) {
+		t.Errorf("Expected reservations to stop at the cap, next slot is %s", pacer.next.Sub(now))
+	}
+
+	// A nil pacer (pacing disabled) never delays
+	var disabled *requestPacer
+	if d := disabled.delay(low, true); d != 0 {
+		t.Errorf("Expected no delay without pacing, got %s", d)
+	}
+}
+
+func TestClient_RatePacing(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-Ratelimit-Limit", "50")
+		w.Header().Set("X-Ratelimit-Remaining", "0")
+		w.Header().Set("X-Ratelimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+		_, _ = w.Write([]byte(`{"id": "user1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL), WithRatePacing(0))
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Fatalf("Expected the first request to go straight out, got %v", err)
+	}
+
+	// The quota is used up, so the next request waits for the reset and its deadline passes first
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.WithContext(ctx).GetCurrentUser()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the request to be held back until its deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected the request to wait, returned after %s", elapsed)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected the paced request not to be sent, got %d requests", got)
+	}
+}
@@ -0,0 +1,51 @@
+package quip
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit describes the per-user API quota reported by Quip in response headers
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// rateLimitTracker remembers the most recent rate-limit headers. It is shared by
+// copies of a Client made with WithContext.
+type rateLimitTracker struct {
+	mu    sync.Mutex
+	limit RateLimit
+	seen  bool
+}
+
+// update records the rate-limit headers of resp, if present
+func (t *rateLimitTracker) update(resp *http.Response) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return
+	}
+
+	rl := RateLimit{Limit: limit}
+	rl.Remaining, _ = strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+
+	t.mu.Lock()
+	t.limit = rl
+	t.seen = true
+	t.mu.Unlock()
+}
+
+// RateLimit returns the rate-limit state from the most recent API response, reporting
+// false if no response has carried rate-limit headers yet
+func (c *Client) RateLimit() (RateLimit, bool) {
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+
+	return c.rateLimit.limit, c.rateLimit.seen
+}
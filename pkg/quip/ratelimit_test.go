@@ -0,0 +1,43 @@
+package quip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Limit", "50")
+		w.Header().Set("X-Ratelimit-Remaining", "49")
+		w.Header().Set("X-Ratelimit-Reset", "1640995200")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(User{ID: "user123"})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	if _, ok := client.RateLimit(); ok {
+		t.Error("Expected no rate-limit info before any request")
+	}
+
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rl, ok := client.RateLimit()
+	if !ok {
+		t.Fatal("Expected rate-limit info after request")
+	}
+
+	if rl.Limit != 50 || rl.Remaining != 49 {
+		t.Errorf("Expected limit 50 with 49 remaining, got %d with %d remaining", rl.Limit, rl.Remaining)
+	}
+
+	if rl.Reset.Unix() != 1640995200 {
+		t.Errorf("Expected reset at 1640995200, got %d", rl.Reset.Unix())
+	}
+}
@@ -0,0 +1,147 @@
+package quip
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// Retry defaults used by WithRetry for non-positive settings
+const (
+	DefaultMaxRetries   = 2
+	DefaultRetryBackoff = 500 * time.Millisecond
+)
+
+// idempotentWrites are the POST endpoints that set state rather than add to it, so sending
+// one twice has the same effect as sending it once. Creates, edits, copies, comments and
+// deletes are deliberately absent: repeating them can duplicate a document or an edit.
+var idempotentWrites = map[string]bool{
+	"/threads/add-members":    true,
+	"/threads/remove-members": true,
+	"/threads/follow":         true,
+	"/threads/unfollow":       true,
+	"/threads/lock-edits":     true,
+}
+
+// retryStatuses are the responses worth trying again: Quip is overloaded or briefly down
+var retryStatuses = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// retryPolicy decides which failed requests are sent again and how long to wait in between.
+// It is shared by copies of a Client made with WithContext.
+type retryPolicy struct {
+	maxRetries int
+	backoff    time.Duration
+	idempotent map[string]bool
+}
+
+// WithRetry retries failed requests up to maxRetries times, waiting backoff before the first
+// retry and twice as long before each one after that. Non-positive values select
+// DefaultMaxRetries and DefaultRetryBackoff.
+//
+// Only requests that are safe to repeat are retried after a response or a dropped connection:
+// GETs and the POST endpoints in idempotentWrites (see WithIdempotentEndpoints). Other writes,
+// such as creating, editing or deleting a document, are retried only when the connection
+// failed before anything was sent, since Quip may otherwise have applied the first attempt
+// and a retry would apply it twice. Their errors are returned to the caller as they are.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		if maxRetries <= 0 {
+			maxRetries = DefaultMaxRetries
+		}
+		if backoff <= 0 {
+			backoff = DefaultRetryBackoff
+		}
+		idempotent := make(map[string]bool, len(idempotentWrites))
+		if c.retry != nil {
+			idempotent = c.retry.idempotent
+		}
+		for endpoint := range idempotentWrites {
+			idempotent[endpoint] = true
+		}
+		c.retry = &retryPolicy{maxRetries: maxRetries, backoff: backoff, idempotent: idempotent}
+	}
+}
+
+// WithIdempotentEndpoints marks further POST endpoints, such as "/threads/edit-document",
+// as safe for WithRetry to repeat like a GET. Only list endpoints whose requests really do
+// have the same effect when applied twice.
+func WithIdempotentEndpoints(endpoints ...string) Option {
+	return func(c *Client) {
+		if c.retry == nil {
+			c.retry = &retryPolicy{idempotent: make(map[string]bool)}
+		}
+		for _, endpoint := range endpoints {
+			c.retry.idempotent[endpoint] = true
+		}
+	}
+}
+
+// isIdempotent reports whether a request can be repeated without changing its outcome
+func (p *retryPolicy) isIdempotent(method, endpoint string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return p.idempotent[hookEndpoint(endpoint)]
+}
+
+// shouldRetry reports whether a request that failed with err and status may be sent again.
+// connected says whether a connection to Quip was established, after which part of the
+// request may have been sent.
+func (p *retryPolicy) shouldRetry(ctx context.Context, err error, status int, idempotent, connected bool) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, ErrDryRun) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if status != 0 {
+		return idempotent && retryStatuses[status]
+	}
+
+	// Only transport failures are retried; errors after a response arrived are not
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	return idempotent || !connected
+}
+
+// sendWithRetries makes the attempts the retry policy allows at req, returning the result of
+// the last one. Without WithRetry the request is sent once.
+func (c *Client) sendWithRetries(req *http.Request, endpoint, token string) (*http.Response, int, error) {
+	if c.retry == nil || c.retry.maxRetries == 0 {
+		return c.send(req, endpoint, token)
+	}
+
+	idempotent := c.retry.isIdempotent(req.Method, endpoint)
+	wait := c.retry.backoff
+	for attempt := 0; ; attempt++ {
+		var connected atomic.Bool
+		trace := &httptrace.ClientTrace{GotConn: func(httptrace.GotConnInfo) { connected.Store(true) }}
+		attemptReq := req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+		resp, status, err := c.send(attemptReq, endpoint, token)
+		if attempt >= c.retry.maxRetries || !c.retry.shouldRetry(req.Context(), err, status, idempotent, connected.Load()) {
+			return resp, status, err
+		}
+
+		retry, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return resp, status, err
+		}
+		select {
+		case <-req.Context().Done():
+			return resp, status, err
+		case <-time.After(wait):
+		}
+		req = retry
+		wait *= 2
+	}
+}
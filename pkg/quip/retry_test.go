@@ -0,0 +1,119 @@
+package quip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, then answers with a document
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"thread": {"id": "doc1", "title": "Doc"}, "id": "user1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestClient_Retry_Reads(t *testing.T) {
+	server, requests := flakyServer(t, 2, http.StatusServiceUnavailable)
+	client := NewClient("test-token", WithBaseURL(server.URL), WithRetry(2, time.Millisecond))
+
+	if _, err := client.GetCurrentUser(); err != nil {
+		t.Fatalf("Expected the read to succeed after retrying, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestClient_Retry_GivesUp(t *testing.T) {
+	server, requests := flakyServer(t, 10, http.StatusServiceUnavailable)
+	client := NewClient("test-token", WithBaseURL(server.URL), WithRetry(2, time.Millisecond))
+
+	if _, err := client.GetCurrentUser(); StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the last 503 to be returned, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestClient_Retry_NonIdempotentWrites(t *testing.T) {
+	server, requests := flakyServer(t, 1, http.StatusServiceUnavailable)
+	client := NewClient("test-token", WithBaseURL(server.URL), WithRetry(3, time.Millisecond))
+
+	// Quip may have created the document before failing, so a retry could create it twice
+	if _, err := client.CreateDocument("Notes", "hello"); StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the 503 to be returned to the caller, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected CreateDocument not to be retried, got %d requests", got)
+	}
+}
+
+func TestClient_Retry_IdempotentWrites(t *testing.T) {
+	server, requests := flakyServer(t, 1, http.StatusBadGateway)
+	client := NewClient("test-token", WithBaseURL(server.URL), WithRetry(1, time.Millisecond))
+
+	if _, err := client.AddToFolder("doc1", "folder1"); err != nil {
+		t.Fatalf("Expected adding to a folder to succeed after a retry, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+
+	// Endpoints can be marked safe to repeat
+	server, requests = flakyServer(t, 1, http.StatusBadGateway)
+	client = NewClient("test-token", WithBaseURL(server.URL), WithRetry(1, time.Millisecond), WithIdempotentEndpoints("/threads/new-document"))
+	if _, err := client.CreateDocument("Notes", "hello"); err != nil {
+		t.Fatalf("Expected a marked endpoint to be retried, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestClient_Retry_ConnectionFailures(t *testing.T) {
+	// Nothing listens on a closed listener's address, so connecting fails before anything is sent
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	var attempts int32
+	hooks := Hooks{OnRequest: func(context.Context, RequestEvent) { atomic.AddInt32(&attempts, 1) }}
+	client := NewClient("test-token", WithBaseURL("http://"+addr), WithRetry(2, time.Millisecond), WithHooks(hooks))
+
+	if _, err := client.CreateDocument("Notes", "hello"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("Expected a write that never reached Quip to be retried, got %d attempts", got)
+	}
+}
+
+func TestClient_Retry_ClientErrors(t *testing.T) {
+	server, requests := flakyServer(t, 10, http.StatusNotFound)
+	client := NewClient("test-token", WithBaseURL(server.URL), WithRetry(2, time.Millisecond))
+
+	if _, err := client.GetCurrentUser(); StatusCode(err) != http.StatusNotFound {
+		t.Fatalf("Expected a 404, got %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("Expected a 404 not to be retried, got %d requests", got)
+	}
+}
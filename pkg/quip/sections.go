@@ -0,0 +1,208 @@
+package quip
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SectionLocation says where EditSection puts content relative to a section
+type SectionLocation int
+
+// Section-relative edit locations accepted by /threads/edit-document
+const (
+	SectionAfter   SectionLocation = locationAfterSection
+	SectionBefore  SectionLocation = locationBeforeSection
+	SectionReplace SectionLocation = locationReplaceSection
+	SectionDelete  SectionLocation = locationDeleteSection
+)
+
+// Section is a heading in a document together with everything below it up to the next heading
+// of the same or a higher level. Every element in Quip's HTML carries a section ID, which is
+// what targeted edits address.
+type Section struct {
+	ID      string `json:"id"`
+	Heading string `json:"heading"`
+	// Level is 1 for <h1> through 6 for <h6>
+	Level int `json:"level"`
+	// BlockIDs are the section IDs of the paragraphs, lists, tables and subsection headings
+	// under the heading, in document order
+	BlockIDs []string `json:"block_ids,omitempty"`
+}
+
+// ParseSections finds the headings in a document's HTML and the blocks under each one.
+// Headings without a section ID can't be edited and are treated as ordinary blocks.
+func ParseSections(htmlContent string) ([]Section, error) {
+	dom, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document HTML: %w", err)
+	}
+
+	var sections []Section
+	var open []int // indexes into sections of the headings the current block is under
+
+	dom.Find("body").Children().Each(func(_ int, block *goquery.Selection) {
+		ids := blockIDs(block)
+		if len(ids) == 0 {
+			return
+		}
+
+		level := headingLevel(block)
+		if level > 0 && block.AttrOr("id", "") != "" {
+			for len(open) > 0 && sections[open[len(open)-1]].Level >= level {
+				open = open[:len(open)-1]
+			}
+			for _, i := range open {
+				sections[i].BlockIDs = append(sections[i].BlockIDs, ids...)
+			}
+			sections = append(sections, Section{ID: ids[0], Heading: strings.TrimSpace(block.Text()), Level: level})
+			open = append(open, len(sections)-1)
+			return
+		}
+
+		for _, i := range open {
+			sections[i].BlockIDs = append(sections[i].BlockIDs, ids...)
+		}
+	})
+
+	return sections, nil
+}
+
+// FindSection returns the section whose heading has the given section ID or, failing that,
+// the given text (ignoring case). A heading text shared by several sections is ambiguous.
+func FindSection(sections []Section, ref string) (*Section, error) {
+	ref = strings.TrimSpace(ref)
+
+	var matches []*Section
+	for i := range sections {
+		if sections[i].ID == ref {
+			return &sections[i], nil
+		}
+		if strings.EqualFold(sections[i].Heading, ref) {
+			matches = append(matches, &sections[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no section with heading or ID %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, match := range matches {
+			ids[i] = match.ID
+		}
+		return nil, fmt.Errorf("%d sections are headed %q; pass one of their IDs instead: %s", len(matches), ref, strings.Join(ids, ", "))
+	}
+}
+
+// GetSections fetches a document and returns its sections in document order
+func (c *Client) GetSections(threadID string) ([]Section, error) {
+	doc, err := c.GetDocument(threadID)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSections(doc.HTML)
+}
+
+// EditSection inserts, replaces or deletes content relative to the element with the given
+// section ID. Content is ignored for SectionDelete; an empty format sends DefaultContentFormat.
+func (c *Client) EditSection(threadID, sectionID string, location SectionLocation, content, format string) (*Document, error) {
+	if err := c.checkContentSize(content); err != nil {
+		return nil, err
+	}
+
+	formData := map[string]string{
+		"thread_id":  threadID,
+		"section_id": sectionID,
+		"location":   strconv.Itoa(int(location)),
+	}
+	if location != SectionDelete {
+		if format == "" {
+			format = DefaultContentFormat
+		}
+		formData["content"] = content
+		formData["format"] = format
+	}
+
+	return c.postThreadForm("/threads/edit-document", formData)
+}
+
+// ReplaceSectionContent replaces everything under a heading, named by its section ID or text,
+// with content. The heading itself and the rest of the document are left alone. Quip edits one
+// element per request, so the first block is replaced and the others are then deleted; if a
+// deletion fails the document is left with the new content followed by some of the old.
+func (c *Client) ReplaceSectionContent(threadID, section, content, format string) (*Document, error) {
+	if err := c.checkContentSize(content); err != nil {
+		return nil, err
+	}
+
+	// Read the block IDs from Quip, not the document cache: deleting blocks from a stale copy
+	// would remove the wrong content
+	doc, err := c.fetchDocument(threadID)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := ParseSections(doc.HTML)
+	if err != nil {
+		return nil, err
+	}
+	target, err := FindSection(sections, section)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(target.BlockIDs) == 0 {
+		return c.EditSection(threadID, target.ID, SectionAfter, content, format)
+	}
+
+	doc, err = c.EditSection(threadID, target.BlockIDs[0], SectionReplace, content, format)
+	// In a dry run carry on, so every request the replacement needs is recorded
+	if err != nil && !errors.Is(err, ErrDryRun) {
+		return nil, err
+	}
+	for _, id := range target.BlockIDs[1:] {
+		updated, err := c.EditSection(threadID, id, SectionDelete, "", "")
+		if err != nil && !errors.Is(err, ErrDryRun) {
+			return nil, fmt.Errorf("section replaced, but removing its old content failed: %w", err)
+		}
+		if updated != nil {
+			doc = updated
+		}
+	}
+	// Only a dry run gets this far without an updated document
+	if doc == nil {
+		return nil, ErrDryRun
+	}
+
+	return doc, nil
+}
+
+// blockIDs returns the section IDs of a top-level block: its own, or for containers without
+// one (some lists), those of its direct children
+func blockIDs(block *goquery.Selection) []string {
+	if id := block.AttrOr("id", ""); id != "" {
+		return []string{id}
+	}
+
+	var ids []string
+	block.Children().Each(func(_ int, child *goquery.Selection) {
+		if id := child.AttrOr("id", ""); id != "" {
+			ids = append(ids, id)
+		}
+	})
+	return ids
+}
+
+// headingLevel returns 1-6 for <h1>-<h6> and 0 for anything else
+func headingLevel(block *goquery.Selection) int {
+	name := goquery.NodeName(block)
+	if len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' {
+		return int(name[1] - '0')
+	}
+	return 0
+}
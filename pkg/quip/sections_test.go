@@ -0,0 +1,184 @@
+package quip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testSectionsHTML = `<h1 id="h-title">Plan</h1>
+<p id="p-intro">Intro</p>
+<h2 id="h-goals">Goals</h2>
+<p id="p-goal1">Ship it</p>
+<ul><li id="li-1">One</li><li id="li-2">Two</li></ul>
+<h3 id="h-stretch">Stretch</h3>
+<p id="p-stretch">Maybe</p>
+<h2 id="h-risks">Risks</h2>
+<h2 id="h-notes">Notes</h2>
+<p id="p-notes">Later</p>
+<h2 id="h-dup">Notes</h2>`
+
+func TestParseSections(t *testing.T) {
+	sections, err := ParseSections(testSectionsHTML)
+	if err != nil {
+		t.Fatalf("ParseSections failed: %v", err)
+	}
+
+	expected := []Section{
+		{ID: "h-title", Heading: "Plan", Level: 1, BlockIDs: []string{"p-intro", "h-goals", "p-goal1", "li-1", "li-2", "h-stretch", "p-stretch", "h-risks", "h-notes", "p-notes", "h-dup"}},
+		{ID: "h-goals", Heading: "Goals", Level: 2, BlockIDs: []string{"p-goal1", "li-1", "li-2", "h-stretch", "p-stretch"}},
+		{ID: "h-stretch", Heading: "Stretch", Level: 3, BlockIDs: []string{"p-stretch"}},
+		{ID: "h-risks", Heading: "Risks", Level: 2},
+		{ID: "h-notes", Heading: "Notes", Level: 2, BlockIDs: []string{"p-notes"}},
+		{ID: "h-dup", Heading: "Notes", Level: 2},
+	}
+	if !reflect.DeepEqual(sections, expected) {
+		t.Errorf("ParseSections() = %+v, expected %+v", sections, expected)
+	}
+}
+
+func TestFindSection(t *testing.T) {
+	sections, _ := ParseSections(testSectionsHTML)
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{name: "by ID", ref: "h-goals", want: "h-goals"},
+		{name: "by heading", ref: " goals ", want: "h-goals"},
+		{name: "duplicate heading by ID", ref: "h-dup", want: "h-dup"},
+		{name: "ambiguous heading", ref: "Notes", wantErr: "pass one of their IDs instead: h-notes, h-dup"},
+		{name: "missing", ref: "Budget", wantErr: "no section"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			section, err := FindSection(sections, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || section.ID != tt.want {
+				t.Errorf("Expected section %s, got %+v, %v", tt.want, section, err)
+			}
+		})
+	}
+}
+
+// newSectionEditServer serves testSectionsHTML and records the edit-document forms it receives
+func newSectionEditServer(t *testing.T) (*httptest.Server, func() []map[string]string) {
+	var mu sync.Mutex
+	var edits []map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc1":
+			_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc1", Title: "Plan"}, HTML: testSectionsHTML})
+		case "/threads/edit-document":
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("Failed to parse form data: %v", err)
+			}
+			form := make(map[string]string)
+			for key := range r.PostForm {
+				form[key] = r.PostForm.Get(key)
+			}
+			mu.Lock()
+			edits = append(edits, form)
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc1", Title: "Plan"}})
+		default:
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+	}))
+
+	return server, func() []map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return edits
+	}
+}
+
+func TestClient_ReplaceSectionContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		section  string
+		expected []map[string]string
+	}{
+		{
+			name:    "section with content and a subsection",
+			section: "Goals",
+			expected: []map[string]string{
+				{"thread_id": "doc1", "section_id": "p-goal1", "location": "4", "content": "<p>New</p>", "format": "html"},
+				{"thread_id": "doc1", "section_id": "li-1", "location": "5"},
+				{"thread_id": "doc1", "section_id": "li-2", "location": "5"},
+				{"thread_id": "doc1", "section_id": "h-stretch", "location": "5"},
+				{"thread_id": "doc1", "section_id": "p-stretch", "location": "5"},
+			},
+		},
+		{
+			name:    "empty section",
+			section: "h-risks",
+			expected: []map[string]string{
+				{"thread_id": "doc1", "section_id": "h-risks", "location": "2", "content": "<p>New</p>", "format": "html"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, edits := newSectionEditServer(t)
+			defer server.Close()
+
+			client := NewClient("test-token", WithBaseURL(server.URL))
+			doc, err := client.ReplaceSectionContent("doc1", tt.section, "<p>New</p>", "html")
+			if err != nil {
+				t.Fatalf("ReplaceSectionContent failed: %v", err)
+			}
+			if doc.ID != "doc1" {
+				t.Errorf("Expected the updated document, got %+v", doc)
+			}
+			if got := edits(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected edits %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClient_ReplaceSectionContent_UnknownSection(t *testing.T) {
+	server, edits := newSectionEditServer(t)
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+	if _, err := client.ReplaceSectionContent("doc1", "Budget", "New", ""); err == nil || !strings.Contains(err.Error(), "no section") {
+		t.Errorf("Expected an unknown section error, got %v", err)
+	}
+	if got := edits(); len(got) != 0 {
+		t.Errorf("Expected no edits, got %v", got)
+	}
+}
+
+func TestClient_ReplaceSectionContent_IgnoresCachedDocument(t *testing.T) {
+	server, edits := newSectionEditServer(t)
+	defer server.Close()
+
+	// A stale cached copy whose Goals section has other blocks than the live document
+	cache := NewMemoryCache()
+	client := NewClient("test-token", WithBaseURL(server.URL), WithCache(cache, 0), WithDocumentCache())
+	client.cacheSet(documentCacheKey("doc1"), Document{ID: "doc1", HTML: `<h2 id="h-goals">Goals</h2><p id="p-stale">Old</p>`})
+
+	if _, err := client.ReplaceSectionContent("doc1", "Goals", "<p>New</p>", "html"); err != nil {
+		t.Fatalf("ReplaceSectionContent failed: %v", err)
+	}
+	if got := edits(); len(got) == 0 || got[0]["section_id"] != "p-goal1" {
+		t.Errorf("Expected the live document's blocks to be replaced, got %v", got)
+	}
+}
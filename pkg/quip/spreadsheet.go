@@ -0,0 +1,176 @@
+package quip
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Spreadsheet represents the parsed contents of a Quip spreadsheet thread
+type Spreadsheet struct {
+	ThreadID string  `json:"thread_id"`
+	Title    string  `json:"title"`
+	Sheets   []Sheet `json:"sheets"`
+}
+
+// Sheet represents a single sheet (tab) of a Quip spreadsheet
+type Sheet struct {
+	ID      string     `json:"id"`
+	Title   string     `json:"title"`
+	Headers []string   `json:"headers"`
+	Rows    [][]string `json:"rows"`
+
+	// cellIDs holds the section ID of every cell in Rows, used for targeted edits
+	cellIDs [][]string
+}
+
+// FindSheet returns the sheet with the given title, or the first sheet if title is empty
+func (s *Spreadsheet) FindSheet(title string) (*Sheet, error) {
+	if len(s.Sheets) == 0 {
+		return nil, fmt.Errorf("spreadsheet %s has no sheets", s.ThreadID)
+	}
+	if title == "" {
+		return &s.Sheets[0], nil
+	}
+	for i := range s.Sheets {
+		if strings.EqualFold(s.Sheets[i].Title, title) {
+			return &s.Sheets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("sheet %q not found in spreadsheet %s", title, s.ThreadID)
+}
+
+// GetSpreadsheetData retrieves a spreadsheet thread and parses its HTML tables into rows and columns
+func (c *Client) GetSpreadsheetData(threadID string) (*Spreadsheet, error) {
+	doc, err := c.GetDocument(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSpreadsheet(doc)
+}
+
+// ParseSpreadsheet parses an already-fetched spreadsheet thread into rows and columns
+func ParseSpreadsheet(doc *Document) (*Spreadsheet, error) {
+	if doc.Type != "" && !strings.EqualFold(doc.Type, "spreadsheet") {
+		return nil, fmt.Errorf("thread %s is a %s, not a spreadsheet", doc.ID, doc.Type)
+	}
+
+	sheets, err := parseSpreadsheetHTML(doc.HTML)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Spreadsheet{
+		ThreadID: doc.ID,
+		Title:    doc.Title,
+		Sheets:   sheets,
+	}, nil
+}
+
+// UpdateSpreadsheetCell replaces the value of a single cell, addressed in A1 notation (e.g. "B3").
+// Row numbers count data rows only, so "A1" is the first row below the column headers.
+// An empty sheet name selects the first sheet.
+func (c *Client) UpdateSpreadsheetCell(threadID, sheet, cell, value string) (*Document, error) {
+	row, col, err := parseCellReference(cell)
+	if err != nil {
+		return nil, err
+	}
+
+	spreadsheet, err := c.GetSpreadsheetData(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := spreadsheet.FindSheet(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	if row >= len(target.cellIDs) || col >= len(target.cellIDs[row]) {
+		return nil, fmt.Errorf("cell %s is outside sheet %q (%d rows)", cell, target.Title, len(target.Rows))
+	}
+
+	sectionID := target.cellIDs[row][col]
+	if sectionID == "" {
+		return nil, fmt.Errorf("cell %s in sheet %q has no section ID and cannot be edited", cell, target.Title)
+	}
+
+	return c.EditSection(threadID, sectionID, SectionReplace, html.EscapeString(value), "html")
+}
+
+// parseSpreadsheetHTML extracts every <table> in the thread HTML as a sheet
+func parseSpreadsheetHTML(htmlContent string) ([]Sheet, error) {
+	dom, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spreadsheet HTML: %w", err)
+	}
+
+	var sheets []Sheet
+	dom.Find("table").Each(func(i int, table *goquery.Selection) {
+		sheet := Sheet{
+			ID:    table.AttrOr("id", ""),
+			Title: table.AttrOr("title", ""),
+		}
+		if sheet.Title == "" {
+			sheet.Title = fmt.Sprintf("Sheet%d", i+1)
+		}
+
+		table.Find("thead tr").First().Find("th, td").Each(func(_ int, th *goquery.Selection) {
+			sheet.Headers = append(sheet.Headers, cellText(th))
+		})
+
+		rows := table.Find("tbody tr")
+		if rows.Length() == 0 {
+			rows = table.Find("tr").Not("thead tr")
+		}
+		rows.Each(func(_ int, tr *goquery.Selection) {
+			var values, ids []string
+			tr.Find("td").Each(func(_ int, td *goquery.Selection) {
+				values = append(values, cellText(td))
+				ids = append(ids, td.AttrOr("id", ""))
+			})
+			sheet.Rows = append(sheet.Rows, values)
+			sheet.cellIDs = append(sheet.cellIDs, ids)
+		})
+
+		// Quip renders an empty corner header above the row-number column; drop it
+		// when it would otherwise shift the headers out of line with the cells
+		if len(sheet.Rows) > 0 && len(sheet.Headers) == len(sheet.Rows[0])+1 && sheet.Headers[0] == "" {
+			sheet.Headers = sheet.Headers[1:]
+		}
+
+		sheets = append(sheets, sheet)
+	})
+
+	return sheets, nil
+}
+
+// cellText returns the trimmed visible text of a spreadsheet cell
+func cellText(s *goquery.Selection) string {
+	return strings.TrimSpace(s.Text())
+}
+
+// parseCellReference converts an A1-style reference into zero-based row and column indexes
+func parseCellReference(ref string) (row, col int, err error) {
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+
+	i := 0
+	for i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z' {
+		col = col*26 + int(ref[i]-'A'+1)
+		i++
+	}
+	if i == 0 || i == len(ref) {
+		return 0, 0, fmt.Errorf("invalid cell reference %q: expected a column letter followed by a row number, e.g. B3", ref)
+	}
+
+	rowNum, convErr := strconv.Atoi(ref[i:])
+	if convErr != nil || rowNum < 1 {
+		return 0, 0, fmt.Errorf("invalid cell reference %q: row must be a positive number", ref)
+	}
+
+	return rowNum - 1, col - 1, nil
+}
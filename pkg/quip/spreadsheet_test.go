@@ -0,0 +1,192 @@
+package quip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testSpreadsheetHTML = `<table id="sheet1" title="Budget">
+<thead><tr><th id="h0"></th><th id="h1">A</th><th id="h2">B</th></tr></thead>
+<tbody>
+<tr id="r1"><td id="s:r1_c1"><span>Item</span></td><td id="s:r1_c2"><span>Cost</span></td></tr>
+<tr id="r2"><td id="s:r2_c1"><span>Laptop</span></td><td id="s:r2_c2"><span>1200</span></td></tr>
+</tbody>
+</table>
+<table id="sheet2" title="Notes"><tbody><tr><td id="s:n1">Hello &amp; welcome</td></tr></tbody></table>`
+
+func TestParseSpreadsheetHTML(t *testing.T) {
+	sheets, err := parseSpreadsheetHTML(testSpreadsheetHTML)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sheets) != 2 {
+		t.Fatalf("Expected 2 sheets, got %d", len(sheets))
+	}
+
+	budget := sheets[0]
+	if budget.Title != "Budget" {
+		t.Errorf("Expected sheet title 'Budget', got %s", budget.Title)
+	}
+
+	if len(budget.Headers) != 2 || budget.Headers[0] != "A" || budget.Headers[1] != "B" {
+		t.Errorf("Expected headers [A B], got %v", budget.Headers)
+	}
+
+	if len(budget.Rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(budget.Rows))
+	}
+
+	if budget.Rows[1][0] != "Laptop" || budget.Rows[1][1] != "1200" {
+		t.Errorf("Expected row [Laptop 1200], got %v", budget.Rows[1])
+	}
+
+	if budget.cellIDs[1][1] != "s:r2_c2" {
+		t.Errorf("Expected cell ID 's:r2_c2', got %s", budget.cellIDs[1][1])
+	}
+
+	if sheets[1].Rows[0][0] != "Hello & welcome" {
+		t.Errorf("Expected decoded cell text 'Hello & welcome', got %s", sheets[1].Rows[0][0])
+	}
+}
+
+func TestParseCellReference(t *testing.T) {
+	tests := []struct {
+		ref     string
+		row     int
+		col     int
+		wantErr bool
+	}{
+		{ref: "A1", row: 0, col: 0},
+		{ref: "b3", row: 2, col: 1},
+		{ref: "AA10", row: 9, col: 26},
+		{ref: "A0", wantErr: true},
+		{ref: "12", wantErr: true},
+		{ref: "C", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			row, col, err := parseCellReference(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q, got nil", tt.ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if row != tt.row || col != tt.col {
+				t.Errorf("parseCellReference(%q) = (%d, %d), expected (%d, %d)", tt.ref, row, col, tt.row, tt.col)
+			}
+		})
+	}
+}
+
+func TestClient_GetSpreadsheetData_NotSpreadsheet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := RecentThreadData{
+			Thread: Document{ID: "doc123", Title: "Plain Doc", Type: "document"},
+			HTML:   "<p>Not a table</p>",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	if _, err := client.GetSpreadsheetData("doc123"); err == nil {
+		t.Fatal("Expected error for non-spreadsheet thread, got nil")
+	}
+}
+
+func TestClient_UpdateSpreadsheetCell(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/threads/sheet123":
+			response := RecentThreadData{
+				Thread: Document{ID: "sheet123", Title: "Budget", Type: "spreadsheet"},
+				HTML:   testSpreadsheetHTML,
+			}
+			_ = json.NewEncoder(w).Encode(response)
+		case "/threads/edit-document":
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("Failed to parse form data: %v", err)
+			}
+
+			if r.FormValue("section_id") != "s:r2_c2" {
+				t.Errorf("Expected section_id 's:r2_c2', got %s", r.FormValue("section_id"))
+			}
+
+			if r.FormValue("location") != "4" {
+				t.Errorf("Expected location '4' (REPLACE_SECTION), got %s", r.FormValue("location"))
+			}
+
+			if r.FormValue("content") != "1500 &lt;USD&gt;" {
+				t.Errorf("Expected escaped content '1500 &lt;USD&gt;', got %s", r.FormValue("content"))
+			}
+
+			response := RecentThreadData{
+				Thread: Document{ID: "sheet123", Title: "Budget", Type: "spreadsheet"},
+			}
+			_ = json.NewEncoder(w).Encode(response)
+		default:
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	doc, err := client.UpdateSpreadsheetCell("sheet123", "Budget", "B2", "1500 <USD>")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if doc.ID != "sheet123" {
+		t.Errorf("Expected document ID 'sheet123', got %s", doc.ID)
+	}
+
+	if _, err := client.UpdateSpreadsheetCell("sheet123", "Budget", "C9", "x"); err == nil {
+		t.Error("Expected error for out-of-range cell, got nil")
+	}
+}
+
+func TestClient_CreateSpreadsheet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/new-document" {
+			t.Errorf("Expected path /threads/new-document, got %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+		if r.FormValue("type") != "spreadsheet" || r.FormValue("format") != "html" {
+			t.Errorf("Expected type=spreadsheet and format=html, got %v", r.Form)
+		}
+		if r.FormValue("title") != "Budget" {
+			t.Errorf("Expected title 'Budget', got %s", r.FormValue("title"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "sheet123", Title: "Budget", Type: "spreadsheet", Link: "https://quip.com/sheet123"}})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	doc, err := client.CreateSpreadsheet("Budget", "<table><tr><td>Item</td></tr></table>")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if doc.ID != "sheet123" || doc.Link != "https://quip.com/sheet123" {
+		t.Errorf("Expected the new spreadsheet's ID and link, got %+v", doc)
+	}
+}
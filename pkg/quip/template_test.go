@@ -0,0 +1,121 @@
+package quip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_CreateFromTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/copy-document" {
+			t.Errorf("Expected path /threads/copy-document, got %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form data: %v", err)
+		}
+		if r.FormValue("thread_id") != "tmpl123" || r.FormValue("title") != "Search Redesign" {
+			t.Errorf("Expected thread_id=tmpl123 and the new title, got %v", r.Form)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RecentThreadData{
+			Thread: Document{ID: "doc456", Title: "Search Redesign", Type: "document", Link: "https://quip.com/doc456"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	doc, err := client.CreateFromTemplate("tmpl123", "Search Redesign")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if doc.ID != "doc456" || doc.Title != "Search Redesign" {
+		t.Errorf("Expected the new document, got %+v", doc)
+	}
+
+	if _, err := client.CreateFromTemplate("tmpl123", "  "); err == nil {
+		t.Error("Expected an error for an empty title, got nil")
+	}
+}
+
+func TestClient_SetTemplate(t *testing.T) {
+	for _, requested := range []bool{true, false} {
+		for _, applied := range []bool{true, false} {
+			t.Run(fmt.Sprintf("requested=%t/applied=%t", requested, applied), func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					// The flag is read back from the thread, as the edit response doesn't echo it
+					if r.URL.Path == "/threads/doc123" {
+						_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: "doc123", Title: "Design Doc", IsTemplate: applied == requested}})
+						return
+					}
+					if r.URL.Path != "/threads/edit-document" {
+						t.Errorf("Expected path /threads/edit-document, got %s", r.URL.Path)
+					}
+					if r.FormValue("thread_id") != "doc123" || r.FormValue("is_template") != fmt.Sprint(requested) {
+						t.Errorf("Expected thread_id=doc123 and is_template=%t, got %v", requested, r.Form)
+					}
+					// An empty acknowledgement says nothing about the flag
+				}))
+				defer server.Close()
+
+				client := NewClient("test-token", WithBaseURL(server.URL))
+
+				doc, err := client.SetTemplate("doc123", requested)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if doc.ID != "doc123" || doc.IsTemplate != (applied == requested) {
+					t.Errorf("Expected doc123 with the flag Quip reports (%t), got %+v", applied == requested, doc)
+				}
+			})
+		}
+	}
+}
+
+func TestClient_ListTemplates(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if got := r.URL.Query().Get("count"); got != "100" {
+			t.Errorf("Expected the maximum page to be filtered, got count=%s", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/threads/search" {
+			_ = json.NewEncoder(w).Encode([]SearchResponse{{Thread: Document{ID: "t1", Title: "Design Doc", IsTemplate: true}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]RecentThreadData{
+			"t1": {Thread: Document{ID: "t1", Title: "Design Doc", IsTemplate: true, Updated: 4}},
+			"d1": {Thread: Document{ID: "d1", Title: "Search Redesign", Updated: 3}},
+			"t2": {Thread: Document{ID: "t2", Title: "Postmortem", IsTemplate: true, Updated: 2}},
+			"t3": {Thread: Document{ID: "t3", Title: "Retro", IsTemplate: true, Updated: 1}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+
+	templates, err := client.ListTemplates("", 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(templates) != 2 || templates[0].ID != "t1" || templates[1].ID != "t2" {
+		t.Errorf("Expected the first two templates, got %+v", templates)
+	}
+
+	templates, err = client.ListTemplates("design", 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(templates) != 1 || templates[0].ID != "t1" {
+		t.Errorf("Expected the matching template, got %+v", templates)
+	}
+	if len(paths) != 2 || paths[0] != "/threads/recent" || paths[1] != "/threads/search" {
+		t.Errorf("Expected recent threads without a query and a search with one, got %v", paths)
+	}
+}
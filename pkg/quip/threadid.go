@@ -0,0 +1,70 @@
+package quip
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+)
+
+// Quip identifies a thread two ways: the secret path in its URL (quip.com/<secret path>) and
+// an internal thread ID. They aren't interchangeable everywhere:
+//
+//	GET /threads/{id}, GET /threads/?ids=, /2/threads/{id}/...  either form
+//	GET /threads/{id}/messages                                  internal ID
+//	POST forms taking thread_id (edit-document, delete, follow,
+//	lock-edits, copy-document, messages/new, ...)                internal ID
+//
+// Callers may pass either form to any method. When an endpoint that needs the internal ID
+// answers 404, the client looks the thread up through GET /threads/{id}, which accepts both,
+// and retries once with the internal ID. Resolved IDs are remembered for the client's lifetime.
+
+// threadIDCache remembers the internal thread ID for secret paths that have been resolved
+type threadIDCache struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// internalThreadID looks up the internal ID of the thread identified by id, reporting false
+// when id is already internal or the thread can't be found
+func (c *Client) internalThreadID(id string) (string, bool) {
+	if c.threadIDs == nil || id == "" {
+		return "", false
+	}
+
+	c.threadIDs.mu.Lock()
+	internal, ok := c.threadIDs.ids[id]
+	c.threadIDs.mu.Unlock()
+	if ok {
+		return internal, internal != id
+	}
+
+	doc, err := c.GetDocument(id)
+	if err != nil || doc.ID == "" {
+		return "", false
+	}
+
+	c.threadIDs.mu.Lock()
+	if c.threadIDs.ids == nil {
+		c.threadIDs.ids = make(map[string]string)
+	}
+	c.threadIDs.ids[id] = doc.ID
+	c.threadIDs.mu.Unlock()
+
+	return doc.ID, doc.ID != id
+}
+
+// retryWithInternalThreadID reports whether a request for threadID that failed with err
+// should be retried with the thread's internal ID, and returns that ID
+func (c *Client) retryWithInternalThreadID(threadID string, err error) (string, bool) {
+	if threadID == "" || StatusCode(err) != http.StatusNotFound {
+		return "", false
+	}
+	return c.internalThreadID(threadID)
+}
+
+// withThreadID returns a copy of formData with thread_id replaced
+func withThreadID(formData map[string]string, threadID string) map[string]string {
+	retry := maps.Clone(formData)
+	retry["thread_id"] = threadID
+	return retry
+}
@@ -0,0 +1,60 @@
+package quip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_SecretPathThreadIDs(t *testing.T) {
+	const secretPath, internalID = "AbCdEfGhIjK", "WUEAAAx1y2z"
+
+	var lookups int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && (r.URL.Path == "/threads/"+secretPath || r.URL.Path == "/threads/"+internalID):
+			if r.URL.Path == "/threads/"+secretPath {
+				atomic.AddInt32(&lookups, 1)
+			}
+			_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: internalID, Title: "Plan"}})
+		case r.URL.Path == "/threads/"+internalID+"/messages":
+			_ = json.NewEncoder(w).Encode([]Comment{{ID: "msg1", Text: "hi"}})
+		case r.URL.Path == "/threads/edit-document" && r.FormValue("thread_id") == internalID:
+			_ = json.NewEncoder(w).Encode(RecentThreadData{Thread: Document{ID: internalID, Title: r.FormValue("title")}})
+		default:
+			// Endpoints that need the internal ID don't recognize secret paths
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	for _, id := range []string{internalID, secretPath} {
+		t.Run(id, func(t *testing.T) {
+			client := NewClient("test-token", WithBaseURL(server.URL))
+
+			doc, err := client.RenameDocument(id, "Q3 Plan")
+			if err != nil || doc.ID != internalID || doc.Title != "Q3 Plan" {
+				t.Fatalf("Expected the rename to reach %s, got %+v, %v", internalID, doc, err)
+			}
+
+			page, err := client.GetThreadMessages(id, CommentOptions{})
+			if err != nil || len(page.Comments) != 1 {
+				t.Fatalf("Expected the thread's messages, got %+v, %v", page, err)
+			}
+		})
+	}
+
+	// The secret path was looked up once and then remembered
+	if got := atomic.LoadInt32(&lookups); got != 1 {
+		t.Errorf("Expected 1 lookup of the secret path, got %d", got)
+	}
+
+	client := NewClient("test-token", WithBaseURL(server.URL))
+	if _, err := client.RenameDocument("unknown", "Q3 Plan"); StatusCode(err) != http.StatusNotFound {
+		t.Errorf("Expected the original 404 for an unknown thread, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !otel
+
+package quip
+
+import "context"
+
+// startRequestSpan is a no-op unless built with the otel tag
+func startRequestSpan(ctx context.Context, method, endpoint string) (context.Context, func(statusCode int, err error)) {
+	return ctx, func(int, error) {}
+}
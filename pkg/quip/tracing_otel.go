@@ -0,0 +1,37 @@
+//go:build otel
+
+package quip
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/bug-breeder/quip-mcp/pkg/quip")
+
+// startRequestSpan starts a client span for an API request using the global TracerProvider.
+// The returned function ends the span, recording the response status and any error.
+func startRequestSpan(ctx context.Context, method, endpoint string) (context.Context, func(statusCode int, err error)) {
+	ctx, span := tracer.Start(ctx, "quip "+method+" "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("quip.endpoint", endpoint),
+		),
+	)
+
+	return ctx, func(statusCode int, err error) {
+		if statusCode != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", statusCode))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,60 @@
+//go:build otel
+
+package quip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_RequestSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	tracer = otel.Tracer("github.com/bug-breeder/quip-mcp/pkg/quip")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	client := NewClient("test-token").WithContext(ctx)
+	client.baseURL = server.URL
+
+	if _, err := client.GetCurrentUser(); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "quip GET /users/current" {
+		t.Errorf("Expected span 'quip GET /users/current', got %s", span.Name())
+	}
+
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("Expected request span to be a child of the caller's span")
+	}
+
+	found := false
+	for _, attr := range span.Attributes() {
+		if attr.Key == attribute.Key("http.response.status_code") && attr.Value.AsInt64() == http.StatusUnauthorized {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected status code attribute 401, got %v", span.Attributes())
+	}
+}
@@ -0,0 +1,99 @@
+package quip
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ResolveThreadID returns the thread ID referenced by input, which may be a bare ID or a
+// Quip link such as https://quip.com/AbCdEfGhIjK/Some-Title. Links on enterprise hosts
+// (e.g. https://acme.quip.com/...) and links without a scheme are accepted; query strings,
+// anchors and trailing slashes are ignored. Input that isn't a link is returned trimmed but
+// otherwise unchanged.
+func ResolveThreadID(input string) string {
+	input = strings.TrimSpace(input)
+
+	raw := input
+	if !strings.Contains(raw, "://") {
+		// Accept "quip.com/AbCdEfGhIjK" style links that were pasted without a scheme
+		host, _, found := strings.Cut(raw, "/")
+		if !found || !strings.Contains(host, ".") {
+			return input
+		}
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return input
+	}
+
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			return segment
+		}
+	}
+
+	return input
+}
+
+// ParseThreadURL extracts the thread ID from a Quip link. Unlike ResolveThreadID it only
+// accepts links: input that isn't a URL on quip.com, one of its enterprise subdomains or one of
+// hosts (a company's own Quip domain, see WebHost) and their subdomains, or whose path doesn't
+// start with a thread ID, is an error.
+func ParseThreadURL(link string, hosts ...string) (string, error) {
+	link = strings.TrimSpace(link)
+
+	raw := link
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || !strings.Contains(u.Hostname(), ".") {
+		return "", fmt.Errorf("%q is not a URL", link)
+	}
+	if !isQuipHost(u.Hostname(), hosts) {
+		return "", fmt.Errorf("%s is not a Quip host", u.Hostname())
+	}
+
+	id := ResolveThreadID(link)
+	if id == "" || !isAlphanumeric(id) {
+		return "", fmt.Errorf("%q doesn't link to a Quip thread", link)
+	}
+
+	return id, nil
+}
+
+// WebHost returns the host of the Quip site an API base URL belongs to, e.g. quip-acme.com
+// for https://platform.quip-acme.com/1, for use with ParseThreadURL
+func WebHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "platform.")
+}
+
+// isQuipHost reports whether host is quip.com or one of hosts, or a subdomain of either
+func isQuipHost(host string, hosts []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, domain := range append([]string{"quip.com"}, hosts...) {
+		domain = strings.ToLower(domain)
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlphanumeric reports whether s consists only of ASCII letters and digits, like Quip IDs
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package quip
+
+import "testing"
+
+func TestResolveThreadID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "bare ID", input: "AbCdEfGhIjK", expected: "AbCdEfGhIjK"},
+		{name: "bare ID with whitespace", input: "  AbCdEfGhIjK\n", expected: "AbCdEfGhIjK"},
+		{name: "URL with title", input: "https://quip.com/AbCdEfGhIjK/Some-Title", expected: "AbCdEfGhIjK"},
+		{name: "URL without title", input: "https://quip.com/AbCdEfGhIjK", expected: "AbCdEfGhIjK"},
+		{name: "trailing slash", input: "https://quip.com/AbCdEfGhIjK/", expected: "AbCdEfGhIjK"},
+		{name: "anchor", input: "https://quip.com/AbCdEfGhIjK#temp:C:abc123", expected: "AbCdEfGhIjK"},
+		{name: "title and anchor", input: "https://quip.com/AbCdEfGhIjK/Some-Title#section", expected: "AbCdEfGhIjK"},
+		{name: "query string", input: "https://quip.com/AbCdEfGhIjK?utm_source=slack", expected: "AbCdEfGhIjK"},
+		{name: "enterprise host", input: "https://acme.quip.com/AbCdEfGhIjK/Roadmap", expected: "AbCdEfGhIjK"},
+		{name: "custom domain", input: "https://quip-acme.com/AbCdEfGhIjK", expected: "AbCdEfGhIjK"},
+		{name: "no scheme", input: "quip.com/AbCdEfGhIjK/Some-Title", expected: "AbCdEfGhIjK"},
+		{name: "host only", input: "https://quip.com/", expected: "https://quip.com/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveThreadID(tt.input); got != tt.expected {
+				t.Errorf("ResolveThreadID(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseThreadURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "URL with title", input: "https://quip.com/AbCdEfGhIjK/Some-Title", expected: "AbCdEfGhIjK"},
+		{name: "enterprise host and query string", input: "https://acme.quip.com/AbCdEfGhIjK?utm_source=slack#section", expected: "AbCdEfGhIjK"},
+		{name: "custom domain not configured", input: "https://quip-acme.com/AbCdEfGhIjK", wantErr: true},
+		{name: "host containing quip", input: "https://notquip.evil.com/AbCdEfGhIjK", wantErr: true},
+		{name: "quip.com as a subdomain elsewhere", input: "https://quip.com.evil.com/AbCdEfGhIjK", wantErr: true},
+		{name: "no scheme", input: " quip.com/AbCdEfGhIjK/Some-Title ", expected: "AbCdEfGhIjK"},
+		{name: "bare ID", input: "AbCdEfGhIjK", wantErr: true},
+		{name: "other host", input: "https://example.com/AbCdEfGhIjK", wantErr: true},
+		{name: "host only", input: "https://quip.com/", wantErr: true},
+		{name: "not a thread path", input: "https://quip.com/-/blob/abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseThreadURL(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseThreadURL(%q) = %q, expected an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("ParseThreadURL(%q) = %q, %v, expected %q", tt.input, got, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseThreadURL_ConfiguredHost(t *testing.T) {
+	host := WebHost("https://platform.quip-acme.com/1")
+	if host != "quip-acme.com" {
+		t.Fatalf("WebHost() = %q, expected quip-acme.com", host)
+	}
+
+	for _, link := range []string{"https://quip-acme.com/AbCdEfGhIjK", "https://www.quip-acme.com/AbCdEfGhIjK/Plan", "https://quip.com/AbCdEfGhIjK"} {
+		if got, err := ParseThreadURL(link, host); err != nil || got != "AbCdEfGhIjK" {
+			t.Errorf("ParseThreadURL(%q) = %q, %v, expected AbCdEfGhIjK", link, got, err)
+		}
+	}
+	if _, err := ParseThreadURL("https://evilquip-acme.com/AbCdEfGhIjK", host); err == nil {
+		t.Error("Expected a host merely ending in the configured one to be rejected")
+	}
+}
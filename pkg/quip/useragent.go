@@ -0,0 +1,42 @@
+package quip
+
+import "fmt"
+
+// userAgentProduct is the product token at the start of every User-Agent
+const userAgentProduct = "quip-mcp"
+
+// WithVersion reports the build's version and commit in the User-Agent, e.g.
+// "quip-mcp/1.4.0 (abc1234)", so requests can be traced back to a release in Quip's logs.
+// An empty or "none" commit is left out. Without this option the version is "dev".
+func WithVersion(version, commit string) Option {
+	return func(c *Client) {
+		c.version = version
+		c.commit = commit
+	}
+}
+
+// WithUserAgentSuffix appends suffix to the User-Agent, e.g. "acme-assistant/2.1",
+// so an embedding application can identify itself alongside this client
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		c.userAgentSuffix = suffix
+	}
+}
+
+// buildUserAgent assembles the User-Agent header from the configured version details
+func (c *Client) buildUserAgent() string {
+	version := c.version
+	if version == "" {
+		version = "dev"
+	}
+
+	userAgent := fmt.Sprintf("%s/%s", userAgentProduct, version)
+	if c.commit != "" && c.commit != "none" {
+		userAgent += fmt.Sprintf(" (%s)", c.commit)
+	}
+	if c.userAgentSuffix != "" {
+		userAgent += " " + c.userAgentSuffix
+	}
+
+	return userAgent
+}
@@ -0,0 +1,40 @@
+package quip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_UserAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{name: "default", expected: "quip-mcp/dev"},
+		{name: "version and commit", opts: []Option{WithVersion("1.4.0", "abc1234")}, expected: "quip-mcp/1.4.0 (abc1234)"},
+		{name: "unknown commit", opts: []Option{WithVersion("1.4.0", "none")}, expected: "quip-mcp/1.4.0"},
+		{name: "suffix", opts: []Option{WithUserAgentSuffix("acme-bot/2.1"), WithVersion("1.4.0", "")}, expected: "quip-mcp/1.4.0 acme-bot/2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("User-Agent")
+				_, _ = w.Write([]byte(`{"id": "user1"}`))
+			}))
+			defer server.Close()
+
+			client := NewClient("test-token", append(tt.opts, WithBaseURL(server.URL))...)
+			if _, err := client.GetCurrentUser(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("Expected User-Agent %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package quip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Quip API versions. Most endpoints only exist in v1; with WithAPIVersion(APIVersion2) the
+// methods that have a v2 equivalent use it and everything else stays on v1:
+//
+//	GetDocument          v1: GET /1/threads/{id}
+//	                     v2: GET /2/threads/{id}, then GET /2/threads/{id}/html page by page
+//	GetDocumentVersions  v2 only (GET /2/threads/{id}/edit-history), whatever the setting
+//
+// Search, editing, comments, folders, users and the websocket have no v2 endpoints yet.
+// ETag revalidation (WithConditionalRequests) only applies to v1 document fetches; with
+// WithCache, v2 fetches instead reuse the cached HTML while updated_usec is unchanged.
+const (
+	APIVersion1 = 1
+	APIVersion2 = 2
+)
+
+// maxHTMLPages bounds how many pages of a v2 document's HTML GetDocument follows. A document
+// with more is an error rather than being returned cut short.
+const maxHTMLPages = 100
+
+// WithAPIVersion selects the Quip API version used by methods that have a v2 equivalent
+// (default APIVersion1). The version in the base URL is swapped for those requests, so
+// https://platform.quip.com/1 becomes https://platform.quip.com/2; a base URL without a
+// trailing version is treated as the v1 root and v2 requests go under <base>/2.
+func WithAPIVersion(version int) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// APIVersion returns the API version the client prefers where a choice exists
+func (c *Client) APIVersion() int {
+	if c.apiVersion == APIVersion2 {
+		return APIVersion2
+	}
+	return APIVersion1
+}
+
+// versionRoot returns the API root for version, derived from the configured base URL
+func (c *Client) versionRoot(version int) string {
+	root := c.baseURL
+	if i := strings.LastIndex(root, "/"); i >= 0 {
+		if _, err := strconv.Atoi(root[i+1:]); err == nil {
+			return root[:i+1] + strconv.Itoa(version)
+		}
+	}
+	if version == APIVersion1 {
+		return root
+	}
+	return root + "/" + strconv.Itoa(version)
+}
+
+// makeV2Request performs a bodiless request against the v2 API, which lives next to v1
+func (c *Client) makeV2Request(method, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.context(), method, c.versionRoot(APIVersion2)+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.do(req, endpoint)
+}
+
+// threadV2Response is the API response structure for GET /2/threads/{id}
+type threadV2Response struct {
+	Thread Document `json:"thread"`
+}
+
+// threadHTMLV2Response is the API response structure for GET /2/threads/{id}/html
+type threadHTMLV2Response struct {
+	HTML             string `json:"html"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+// fetchDocumentV2 retrieves a document's metadata and then its HTML, page by page, from the v2 API
+func (c *Client) fetchDocumentV2(id string) (*Document, error) {
+	resp, err := c.makeV2Request("GET", "/threads/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var thread threadV2Response
+	if err := json.NewDecoder(resp.Body).Decode(&thread); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	doc := &thread.Thread
+
+	// The metadata is cheap; the HTML of an unchanged revision can come from the cache
+	htmlKey := threadHTMLCacheKey(id, doc.Updated)
+	var cached string
+	if doc.Updated != 0 && c.cacheGet(htmlKey, &cached) {
+		doc.HTML = cached
+		return doc, nil
+	}
+
+	var html strings.Builder
+	cursor := ""
+	for page := 0; ; page++ {
+		if page == maxHTMLPages {
+			return nil, fmt.Errorf("document %s has more than %d pages of HTML; it is too large to read in full", id, maxHTMLPages)
+		}
+
+		endpoint := "/threads/" + url.PathEscape(id) + "/html"
+		if cursor != "" {
+			endpoint += "?cursor=" + url.QueryEscape(cursor)
+		}
+
+		next, err := c.fetchHTMLPageV2(endpoint, &html)
+		if err != nil {
+			return nil, err
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	doc.HTML = html.String()
+	if doc.Updated != 0 {
+		c.cacheSet(htmlKey, doc.HTML)
+	}
+
+	return doc, nil
+}
+
+// fetchHTMLPageV2 appends one page of a document's HTML to html and returns the next cursor
+func (c *Client) fetchHTMLPageV2(endpoint string, html *strings.Builder) (string, error) {
+	resp, err := c.makeV2Request("GET", endpoint)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var page threadHTMLV2Response
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	html.WriteString(page.HTML)
+
+	return page.ResponseMetadata.NextCursor, nil
+}
@@ -0,0 +1,136 @@
+package quip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_VersionRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		version  int
+		expected string
+	}{
+		{name: "default v1", baseURL: BaseURL, version: APIVersion1, expected: "https://platform.quip.com/1"},
+		{name: "default v2", baseURL: BaseURL, version: APIVersion2, expected: "https://platform.quip.com/2"},
+		{name: "enterprise v2", baseURL: "https://platform.acme.quip.com/1/", version: APIVersion2, expected: "https://platform.acme.quip.com/2"},
+		{name: "unversioned v1", baseURL: "http://localhost:8080", version: APIVersion1, expected: "http://localhost:8080"},
+		{name: "unversioned v2", baseURL: "http://localhost:8080", version: APIVersion2, expected: "http://localhost:8080/2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test-token", WithBaseURL(tt.baseURL))
+			if got := client.versionRoot(tt.version); got != tt.expected {
+				t.Errorf("versionRoot(%d) = %q, expected %q", tt.version, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClient_GetDocument_V2(t *testing.T) {
+	pages := map[string]threadHTMLV2Response{
+		"":      {HTML: "<p>one</p>"},
+		"page2": {HTML: "<p>two</p>"},
+	}
+	first := pages[""]
+	first.ResponseMetadata.NextCursor = "page2"
+	pages[""] = first
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/threads/doc123":
+			_ = json.NewEncoder(w).Encode(threadV2Response{Thread: Document{ID: "doc123", Title: "Roadmap"}})
+		case "/2/threads/doc123/html":
+			_ = json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+		default:
+			t.Errorf("Expected only v2 requests, got %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL+"/1"), WithAPIVersion(APIVersion2))
+
+	doc, err := client.GetDocument("doc123")
+	if err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+	if doc.ID != "doc123" || doc.Title != "Roadmap" {
+		t.Errorf("Expected the thread's metadata, got %+v", doc)
+	}
+	if doc.HTML != "<p>one</p><p>two</p>" {
+		t.Errorf("Expected both pages of HTML, got %q", doc.HTML)
+	}
+}
+
+func TestClient_GetDocument_V2_TooManyPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/2/threads/doc123" {
+			_ = json.NewEncoder(w).Encode(threadV2Response{Thread: Document{ID: "doc123"}})
+			return
+		}
+		// Every page points to another one
+		var page threadHTMLV2Response
+		page.HTML = "<p>more</p>"
+		page.ResponseMetadata.NextCursor = "next"
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL+"/1"), WithAPIVersion(APIVersion2))
+
+	if doc, err := client.GetDocument("doc123"); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Expected an error rather than a truncated document, got %v, %v", doc, err)
+	}
+}
+
+func TestClient_GetDocument_V2_CachedHTML(t *testing.T) {
+	var updated int64 = 1
+	var htmlRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/threads/doc123":
+			_ = json.NewEncoder(w).Encode(threadV2Response{Thread: Document{ID: "doc123", Updated: atomic.LoadInt64(&updated)}})
+		case "/2/threads/doc123/html":
+			n := atomic.AddInt32(&htmlRequests, 1)
+			_ = json.NewEncoder(w).Encode(threadHTMLV2Response{HTML: fmt.Sprintf("<p>revision %d</p>", n)})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL+"/1"), WithAPIVersion(APIVersion2),
+		WithCache(NewMemoryCache(), time.Hour))
+
+	for i := 0; i < 2; i++ {
+		doc, err := client.GetDocument("doc123")
+		if err != nil {
+			t.Fatalf("GetDocument failed: %v", err)
+		}
+		if doc.HTML != "<p>revision 1</p>" {
+			t.Errorf("Expected the first revision, got %q", doc.HTML)
+		}
+	}
+	if got := atomic.LoadInt32(&htmlRequests); got != 1 {
+		t.Errorf("Expected the unchanged HTML to be downloaded once, got %d requests", got)
+	}
+
+	atomic.StoreInt64(&updated, 2)
+	doc, err := client.GetDocument("doc123")
+	if err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+	if doc.HTML != "<p>revision 2</p>" {
+		t.Errorf("Expected a changed updated_usec to download the HTML again, got %q", doc.HTML)
+	}
+}
@@ -0,0 +1,182 @@
+package quip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// EventTypeMessage is the only live-update event type Subscribe currently delivers: a new
+// message (comment) posted to a thread the user can see
+const EventTypeMessage = "message"
+
+// Websocket timing. Quip closes connections that go quiet, so a heartbeat is sent
+// periodically; lost connections are retried with exponential backoff.
+var (
+	websocketHeartbeat      = 20 * time.Second
+	subscribeInitialBackoff = time.Second
+	subscribeMaxBackoff     = time.Minute
+)
+
+// Event is a live update received from Quip's websocket
+type Event struct {
+	Type    string   `json:"type"`
+	Thread  Document `json:"thread"`
+	Message Comment  `json:"message"`
+}
+
+// websocketResponse is the API response structure for /websocket/new
+type websocketResponse struct {
+	URL    string `json:"url"`
+	UserID string `json:"user_id"`
+}
+
+// Subscribe opens Quip's live-update websocket and delivers events on the returned channel.
+// Only message events (EventTypeMessage) are parsed; heartbeats and other event types are
+// dropped. If the connection is lost Subscribe reconnects with exponential backoff. The
+// connection is closed and the channel with it once ctx is done; an error is only returned
+// if the first connection can't be established.
+func (c *Client) Subscribe(ctx context.Context) (<-chan Event, error) {
+	conn, err := c.dialWebsocket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go c.runSubscription(ctx, conn, events)
+
+	return events, nil
+}
+
+// dialWebsocket asks Quip for a websocket URL and connects to it
+func (c *Client) dialWebsocket(ctx context.Context) (*websocket.Conn, error) {
+	resp, err := c.WithContext(ctx).makeRequest("GET", "/websocket/new", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ws websocketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ws); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if ws.URL == "" {
+		return nil, fmt.Errorf("no websocket URL in response")
+	}
+
+	conn, _, err := c.websocketDialer().DialContext(ctx, ws.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to websocket: %w", err)
+	}
+
+	return conn, nil
+}
+
+// websocketDialer returns a dialer that connects the way the client's HTTP requests do: through
+// the same proxy, TLS configuration and dialer when the client uses an *http.Transport
+func (c *Client) websocketDialer() *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	if c.httpClient.Timeout > 0 {
+		dialer.HandshakeTimeout = c.httpClient.Timeout
+	}
+
+	transport := c.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if t, ok := transport.(*http.Transport); ok {
+		dialer.Proxy = t.Proxy
+		dialer.NetDialContext = t.DialContext
+		if t.TLSClientConfig != nil {
+			dialer.TLSClientConfig = t.TLSClientConfig.Clone()
+		}
+	}
+	return &dialer
+}
+
+// runSubscription reads events until ctx is done, reconnecting whenever the connection drops
+func (c *Client) runSubscription(ctx context.Context, conn *websocket.Conn, events chan<- Event) {
+	defer close(events)
+
+	for {
+		readEvents(ctx, conn, events)
+		if ctx.Err() != nil {
+			return
+		}
+
+		conn = c.reconnectWebsocket(ctx)
+		if conn == nil {
+			return
+		}
+	}
+}
+
+// reconnectWebsocket dials until it succeeds, backing off between attempts.
+// It returns nil if ctx is done first.
+func (c *Client) reconnectWebsocket(ctx context.Context) *websocket.Conn {
+	backoff := subscribeInitialBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		if conn, err := c.dialWebsocket(ctx); err == nil {
+			return conn
+		}
+
+		backoff = min(backoff*2, subscribeMaxBackoff)
+	}
+}
+
+// readEvents forwards events from conn until it fails or ctx is done, then closes conn
+func readEvents(ctx context.Context, conn *websocket.Conn, events chan<- Event) {
+	// Closing the connection is the only way to interrupt a blocked read
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go sendHeartbeats(conn, done)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil || event.Type != EventTypeMessage {
+			continue
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendHeartbeats keeps the connection alive until done is closed or a write fails
+func sendHeartbeats(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(websocketHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.WriteJSON(map[string]string{"type": "heartbeat"}); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package quip
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestClient_Subscribe(t *testing.T) {
+	originalBackoff := subscribeInitialBackoff
+	subscribeInitialBackoff = 10 * time.Millisecond
+	defer func() { subscribeInitialBackoff = originalBackoff }()
+
+	var connections int32
+	upgrader := websocket.Upgrader{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/websocket/new":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"url": "ws` + strings.TrimPrefix(server.URL, "http") + `/ws", "user_id": "user1"}`))
+		case "/ws":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				t.Errorf("Failed to upgrade: %v", err)
+				return
+			}
+			defer conn.Close()
+
+			// The first connection drops after one message to exercise reconnection
+			switch atomic.AddInt32(&connections, 1) {
+			case 1:
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "alive"}`))
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "message", "thread": {"id": "doc1"}, "message": {"id": "msg1", "text": "first"}}`))
+			default:
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "message", "thread": {"id": "doc1"}, "message": {"id": "msg2", "text": "second"}}`))
+				// Hold the connection open until the client goes away
+				_, _, _ = conn.ReadMessage()
+			}
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	events, err := client.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case event := <-events:
+			if event.Type != EventTypeMessage || event.Thread.ID != "doc1" || event.Message.Text != expected {
+				t.Errorf("Expected message %q on doc1, got %+v", expected, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for message %q", expected)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Expected no more events after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected events channel to close after cancellation")
+	}
+}
+
+func TestClient_Subscribe_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": "Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = server.URL
+
+	if _, err := client.Subscribe(context.Background()); err == nil {
+		t.Error("Expected error when the websocket can't be opened, got nil")
+	}
+}
+
+func TestClient_WebsocketDialer(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.example.com:8080")
+	client := NewClient("test-token")
+	client.httpClient.Transport = &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{ServerName: "quip.example.com"},
+	}
+
+	dialer := client.websocketDialer()
+	if dialer.Proxy == nil {
+		t.Fatal("Expected the dialer to use the client's proxy")
+	}
+	if got, _ := dialer.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "quip.com"}}); got.String() != proxyURL.String() {
+		t.Errorf("Expected proxy %s, got %v", proxyURL, got)
+	}
+	if dialer.TLSClientConfig == nil || dialer.TLSClientConfig.ServerName != "quip.example.com" {
+		t.Errorf("Expected the client's TLS configuration, got %+v", dialer.TLSClientConfig)
+	}
+	if dialer.HandshakeTimeout != Timeout {
+		t.Errorf("Expected the client's timeout for the handshake, got %v", dialer.HandshakeTimeout)
+	}
+}
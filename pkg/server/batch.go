@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxBatchDocuments caps how many documents a single get_documents call may fetch
+const maxBatchDocuments = 20
+
+// maxFetchConcurrency caps the concurrency argument of tools that fetch documents in parallel
+const maxFetchConcurrency = 10
+
+// maxBulkDelete caps how many documents a single delete_documents call may delete
+const maxBulkDelete = 100
+
+// bulkDeleteConcurrency is how many deletions delete_documents runs at once
+const bulkDeleteConcurrency = 4
+
+// registerBatchTools registers tools that operate on several documents at once
+func (s *Server) registerBatchTools() {
+	// Get documents tool
+	getDocsTool := mcp.NewTool(
+		"get_documents",
+		mcp.WithDescription("Get several Quip documents at once, e.g. the top results of a search"),
+		mcp.WithArray("document_ids",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("IDs or URLs of the documents to retrieve (max %d)", maxBatchDocuments)),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.MaxItems(maxBatchDocuments),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description(fmt.Sprintf("How many documents to fetch in parallel (default: %d)", quip.DefaultFetchConcurrency)),
+			mcp.Min(1),
+			mcp.Max(maxFetchConcurrency),
+		),
+	)
+
+	s.addTool(getDocsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentIDs, err := req.RequireStringSlice("document_ids")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_ids argument: %v", err)), nil
+		}
+
+		if len(documentIDs) == 0 {
+			return mcp.NewToolResultError("document_ids must contain at least one ID"), nil
+		}
+		if len(documentIDs) > maxBatchDocuments {
+			return mcp.NewToolResultError(fmt.Sprintf("document_ids may contain at most %d IDs", maxBatchDocuments)), nil
+		}
+
+		for i, id := range documentIDs {
+			documentIDs[i] = quip.ResolveThreadID(id)
+		}
+
+		concurrency := req.GetInt("concurrency", quip.DefaultFetchConcurrency)
+		if concurrency < 1 || concurrency > maxFetchConcurrency {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid concurrency argument: must be between 1 and %d", maxFetchConcurrency)), nil
+		}
+
+		results := s.client(ctx).GetDocuments(documentIDs, concurrency)
+
+		failed := 0
+		for _, result := range results {
+			if result.Err != nil {
+				failed++
+			}
+		}
+
+		response := fmt.Sprintf("Fetched %d of %d documents:\n", len(results)-failed, len(results))
+		for _, result := range results {
+			if result.Err != nil {
+				response += fmt.Sprintf("\n---\n\n❌ **%s**: %v\n", result.ID, result.Err)
+				continue
+			}
+
+			doc := result.Document
+			response += fmt.Sprintf("\n---\n\n**%s**\n\n", doc.Title)
+			response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+			response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+			response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+
+			if doc.HTML != "" {
+				response += fmt.Sprintf("\n**Content:**\n%s\n", s.documentMarkdown(doc))
+			}
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Delete documents tool
+	deleteDocsTool := mcp.NewTool(
+		"delete_documents",
+		mcp.WithDescription("Delete several Quip documents with one confirmation, e.g. to clean up after a test run. Documents are moved to the trash unless permanent is set; a failure on one document doesn't stop the others."),
+		mcp.WithArray("document_ids",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("IDs or URLs of the documents to delete (max %d)", maxBulkDelete)),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.MaxItems(maxBulkDelete),
+		),
+		mcp.WithString("confirm", mcp.Required(), mcp.Description(fmt.Sprintf("Type '%s' to move the documents to the trash, or '%s' when permanent is true", s.bulkDeleteHint(false), s.bulkDeleteHint(true)))),
+		mcp.WithBoolean("permanent", mcp.Description("Delete the documents permanently instead of moving them to the trash (default: false). This cannot be undone.")),
+	)
+
+	s.addTool(deleteDocsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentIDs, err := req.RequireStringSlice("document_ids")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_ids argument: %v", err)), nil
+		}
+
+		if len(documentIDs) == 0 {
+			return mcp.NewToolResultError("document_ids must contain at least one ID"), nil
+		}
+		if len(documentIDs) > maxBulkDelete {
+			return mcp.NewToolResultError(fmt.Sprintf("document_ids may contain at most %d IDs", maxBulkDelete)), nil
+		}
+
+		confirm, err := req.RequireString("confirm")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid confirm argument: %v", err)), nil
+		}
+
+		permanent := req.GetBool("permanent", false)
+
+		if confirm != s.bulkDeletePhrase(permanent, len(documentIDs)) {
+			return mcp.NewToolResultError(fmt.Sprintf("Deletion cancelled. To delete these %d documents, you must set confirm='%s'", len(documentIDs), s.bulkDeletePhrase(permanent, len(documentIDs)))), nil
+		}
+
+		for i, id := range documentIDs {
+			documentIDs[i] = quip.ResolveThreadID(id)
+		}
+
+		results := s.client(ctx).DeleteDocuments(documentIDs, quip.DeleteOptions{Wipeout: permanent}, bulkDeleteConcurrency)
+
+		failed := 0
+		for _, result := range results {
+			if result.Err != nil {
+				failed++
+			}
+		}
+
+		summary := fmt.Sprintf("Moved %d of %d documents to the trash", len(results)-failed, len(results))
+		if permanent {
+			summary = fmt.Sprintf("Permanently deleted %d of %d documents", len(results)-failed, len(results))
+		}
+		response := fmt.Sprintf("🗑️ **%s**\n\n", summary)
+		for _, result := range results {
+			if result.Err != nil {
+				response += fmt.Sprintf("- ❌ %s: %s\n", result.ID, explainError(result.Err))
+				continue
+			}
+			response += fmt.Sprintf("- ✅ %s\n", result.ID)
+		}
+		if !permanent && failed < len(results) {
+			response += "\nDeleted documents can be restored from the Trash folder in Quip.\n"
+		}
+
+		if failed == len(results) {
+			return mcp.NewToolResultError(response), nil
+		}
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// bulkDeleteHint describes the confirm value delete_documents expects
+func (s *Server) bulkDeleteHint(permanent bool) string {
+	if s.deleteRequiresTitle {
+		return s.deletePhraseFor(permanent) + " <number of documents> DOCUMENTS"
+	}
+	return s.deletePhraseFor(permanent)
+}
+
+// bulkDeletePhrase is the confirmation delete_documents requires to delete count documents.
+// When single deletes must name the document, a bulk delete has to state how many documents
+// it removes instead, since there is no one title to type.
+func (s *Server) bulkDeletePhrase(permanent bool, count int) string {
+	if s.deleteRequiresTitle {
+		return fmt.Sprintf("%s %d DOCUMENTS", s.deletePhraseFor(permanent), count)
+	}
+	return s.deletePhraseFor(permanent)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_DeleteDocuments(t *testing.T) {
+	var deletes int32
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("thread_id") == "missing" || r.URL.Path == "/threads/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+		atomic.AddInt32(&deletes, 1)
+	}))
+	defer quipServer.Close()
+
+	ids := []any{"doc1", "https://quip.com/doc2/Scratch", "missing"}
+
+	tests := []struct {
+		name         string
+		requireTitle bool
+		args         map[string]any
+		isError      bool
+		want         []string
+		wantDeletes  int32
+	}{
+		{
+			name:        "trash",
+			args:        map[string]any{"confirm": "DELETE"},
+			want:        []string{"Moved 2 of 3 documents to the trash", "✅ doc2", "❌ missing", "Trash folder"},
+			wantDeletes: 2,
+		},
+		{
+			name:        "permanent",
+			args:        map[string]any{"confirm": "PERMANENTLY DELETE", "permanent": true},
+			want:        []string{"Permanently deleted 2 of 3 documents"},
+			wantDeletes: 2,
+		},
+		{
+			name:    "wrong confirmation",
+			args:    map[string]any{"confirm": "DELETE", "permanent": true},
+			isError: true,
+			want:    []string{"confirm='PERMANENTLY DELETE'"},
+		},
+		{
+			name:         "count required",
+			requireTitle: true,
+			args:         map[string]any{"confirm": "DELETE"},
+			isError:      true,
+			want:         []string{"confirm='DELETE 3 DOCUMENTS'"},
+		},
+		{
+			name:         "count given",
+			requireTitle: true,
+			args:         map[string]any{"confirm": "DELETE 3 DOCUMENTS"},
+			want:         []string{"Moved 2 of 3 documents"},
+			wantDeletes:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&deletes, 0)
+			tt.args["document_ids"] = ids
+
+			s := New("test-token",
+				WithDeleteConfirmation(DefaultDeletePhrase, tt.requireTitle),
+				WithClientOptions(quip.WithBaseURL(quipServer.URL)),
+			)
+
+			text, isError := callTool(t, s, "delete_documents", tt.args)
+			if isError != tt.isError {
+				t.Fatalf("Expected isError=%v, got %v: %q", tt.isError, isError, text)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected result to contain %q, got %q", want, text)
+				}
+			}
+			if got := atomic.LoadInt32(&deletes); got != tt.wantDeletes {
+				t.Errorf("Expected %d delete requests, got %d", tt.wantDeletes, got)
+			}
+		})
+	}
+}
+
+func TestServer_GetDocuments_InvalidConcurrency(t *testing.T) {
+	var requests int32
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	for _, concurrency := range []int{0, maxFetchConcurrency + 1} {
+		text, isError := callTool(t, s, "get_documents", map[string]any{"document_ids": []any{"doc1"}, "concurrency": concurrency})
+		if !isError || !strings.Contains(text, "Invalid concurrency argument") {
+			t.Errorf("Expected concurrency %d to be rejected, got %q", concurrency, text)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("Expected no requests for an invalid concurrency, got %d", got)
+	}
+}
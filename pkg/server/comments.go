@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerCommentTools registers the tools that add, edit and delete individual comments
+func (s *Server) registerCommentTools() {
+	// Add comment tool
+	addCommentTool := mcp.NewTool(
+		"add_comment",
+		mcp.WithDescription("Add a comment to a Quip document"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to comment on")),
+		mcp.WithString("text", mcp.Required(), mcp.Description("The comment text")),
+	)
+
+	s.addTool(addCommentTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		text, err := req.RequireString("text")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid text argument: %v", err)), nil
+		}
+
+		comment, err := s.client(ctx).AddComment(documentID, text)
+		if err != nil {
+			return toolError("add comment", err), nil
+		}
+
+		response := "💬 **Comment added successfully!**\n\n"
+		response += fmt.Sprintf("- **Comment ID:** %s\n", comment.ID)
+		response += fmt.Sprintf("- **Document ID:** %s\n", documentID)
+		response += fmt.Sprintf("- **Text:** %s\n", comment.Text)
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Edit comment tool
+	editCommentTool := mcp.NewTool(
+		"edit_comment",
+		mcp.WithDescription("Edit the text of an existing comment"),
+		mcp.WithString("comment_id", mcp.Required(), mcp.Description("The ID of the comment to edit")),
+		mcp.WithString("text", mcp.Required(), mcp.Description("The new comment text")),
+	)
+
+	s.addTool(editCommentTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		commentID, err := req.RequireString("comment_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid comment_id argument: %v", err)), nil
+		}
+
+		text, err := req.RequireString("text")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid text argument: %v", err)), nil
+		}
+
+		comment, err := s.client(ctx).EditComment(commentID, text)
+		if err != nil {
+			return toolError("edit comment", err), nil
+		}
+
+		response := "✅ **Comment edited successfully!**\n\n"
+		response += fmt.Sprintf("- **Comment ID:** %s\n", comment.ID)
+		response += fmt.Sprintf("- **Text:** %s\n", comment.Text)
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Delete comment tool
+	deleteCommentTool := mcp.NewTool(
+		"delete_comment",
+		mcp.WithDescription("Delete a comment from a Quip document"),
+		mcp.WithString("comment_id", mcp.Required(), mcp.Description("The ID of the comment to delete")),
+	)
+
+	s.addTool(deleteCommentTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		commentID, err := req.RequireString("comment_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid comment_id argument: %v", err)), nil
+		}
+
+		if err := s.client(ctx).DeleteComment(commentID); err != nil {
+			return toolError("delete comment", err), nil
+		}
+
+		response := "🗑️ **Comment deleted successfully!**\n\n"
+		response += fmt.Sprintf("- **Comment ID:** %s\n", commentID)
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// resolveAuthorNames looks up the display names of the given authors in one batch request.
+// Authors that can't be resolved (e.g. deleted users) are missing from the result, as is
+// everyone if the lookup itself fails; callers fall back to the raw ID via authorName.
+func resolveAuthorNames(client *quip.Client, authorIDs []string) map[string]string {
+	seen := make(map[string]bool, len(authorIDs))
+	var distinct []string
+	for _, id := range authorIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			distinct = append(distinct, id)
+		}
+	}
+
+	names := make(map[string]string, len(distinct))
+	if len(distinct) == 0 {
+		return names
+	}
+
+	users, err := client.GetUsers(distinct)
+	if err != nil {
+		return names
+	}
+	for id, user := range users {
+		if user.Name != "" {
+			names[id] = user.Name
+		}
+	}
+
+	return names
+}
+
+// authorName returns the resolved name for authorID, or a placeholder that keeps the ID
+func authorName(names map[string]string, authorID string) string {
+	if name, ok := names[authorID]; ok {
+		return name
+	}
+	if authorID == "" {
+		return "Unknown user"
+	}
+	return fmt.Sprintf("Unknown user (%s)", authorID)
+}
+
+// commentAuthorIDs returns the author of each comment, in order
+func commentAuthorIDs(comments []quip.Comment) []string {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.AuthorID
+	}
+	return ids
+}
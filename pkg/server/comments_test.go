@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_GetDocumentComments_AuthorNames(t *testing.T) {
+	var userLookups int
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc123/messages":
+			_ = json.NewEncoder(w).Encode([]quip.Comment{
+				{ID: "c3", AuthorID: "ada", Text: "Agreed", Created: 1735732800000000},
+				{ID: "c2", AuthorID: "gone", Text: "Ship it?", Created: 1735729200000000},
+				{ID: "c1", AuthorID: "ada", Text: "Draft is ready", Created: 1735725600000000},
+			})
+		case "/users/":
+			userLookups++
+			if ids := r.URL.Query().Get("ids"); ids != "ada,gone" {
+				t.Errorf("Expected distinct author IDs ada,gone, got %q", ids)
+			}
+			// Deleted users are simply missing from the response
+			_ = json.NewEncoder(w).Encode(map[string]quip.User{"ada": {ID: "ada", Name: "Ada Lovelace"}})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_document_comments", map[string]any{"document_id": "doc123"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	for _, want := range []string{
+		"1. **Ada Lovelace** · Jan 1, 2025 12:00 UTC: Agreed",
+		"2. **Unknown user (gone)** · Jan 1, 2025 11:00 UTC: Ship it?",
+		"3. **Ada Lovelace** · Jan 1, 2025 10:00 UTC: Draft is ready",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected result to contain %q, got %q", want, text)
+		}
+	}
+
+	if userLookups != 1 {
+		t.Errorf("Expected authors to be resolved in 1 request, got %d", userLookups)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerContactTools registers the contact lookup tool
+func (s *Server) registerContactTools() {
+	// List contacts tool
+	listContactsTool := mcp.NewTool(
+		"list_contacts",
+		mcp.WithDescription("List the current user's Quip contacts with their names, emails and user IDs"),
+		mcp.WithString("query", mcp.Description("Only show contacts whose name or email contains this text (case-insensitive)")),
+	)
+
+	s.addTool(listContactsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := strings.ToLower(strings.TrimSpace(req.GetString("query", "")))
+
+		contacts, err := s.client(ctx).GetContacts()
+		if err != nil {
+			return toolError("list contacts", err), nil
+		}
+
+		var matches []quip.User
+		for _, contact := range contacts {
+			if query == "" || contactMatches(&contact, query) {
+				matches = append(matches, contact)
+			}
+		}
+
+		if len(matches) == 0 {
+			return mcp.NewToolResultText("No contacts found."), nil
+		}
+
+		response := fmt.Sprintf("Found %d contacts:\n\n", len(matches))
+		for _, contact := range matches {
+			response += fmt.Sprintf("- **%s**", contact.Name)
+			if email := contact.PrimaryEmail(); email != "" {
+				response += fmt.Sprintf(" — %s", email)
+			}
+			response += fmt.Sprintf(" (ID: %s)\n", contact.ID)
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// contactMatches reports whether a contact's name or any email contains the lowercased query
+func contactMatches(user *quip.User, query string) bool {
+	if strings.Contains(strings.ToLower(user.Name), query) {
+		return true
+	}
+	for _, email := range append([]string{user.Email}, user.Emails...) {
+		if strings.Contains(strings.ToLower(email), query) {
+			return true
+		}
+	}
+	return false
+}
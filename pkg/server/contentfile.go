@@ -0,0 +1,204 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxContentFileBytes caps content_file reads when the client has no content size limit
+const maxContentFileBytes = 16 << 20
+
+// WithContentDir lets create_document and edit_document read their content from files in dir
+// through a content_file argument. Paths are resolved inside dir, and anything that leads
+// outside it (.., absolute paths elsewhere, symlinks) is refused. Without this option
+// content_file isn't offered.
+func WithContentDir(dir string) Option {
+	return func(s *Server) {
+		s.contentDir = dir
+	}
+}
+
+// WithExportDir lets export_folder save zips to files in dir through an output_path argument.
+// Files are only ever created, never overwritten, and paths that lead outside dir are refused.
+// It is separate from WithContentDir so that reading content files never makes a directory
+// writable. Without this option output_path isn't offered.
+func WithExportDir(dir string) Option {
+	return func(s *Server) {
+		s.exportDir = dir
+	}
+}
+
+// contentFileArg declares the content_file argument, or nothing if no content directory is set
+func (s *Server) contentFileArg() []mcp.ToolOption {
+	if s.contentDir == "" {
+		return nil
+	}
+	return []mcp.ToolOption{
+		mcp.WithString("content_file", mcp.Description(fmt.Sprintf("Instead of content, read the content from this file on the server, relative to %s (at most %d bytes)", s.contentDir, s.contentFileLimit()))),
+	}
+}
+
+// editContentArg declares edit_document's content argument, which is only optional when
+// content_file can be used instead
+func (s *Server) editContentArg() mcp.ToolOption {
+	description := "The new content for the document" + s.contentLimitNote()
+	if s.contentDir == "" {
+		return mcp.WithString("content", mcp.Required(), mcp.Description(description))
+	}
+	return mcp.WithString("content", mcp.Description(description+"; required unless content_file is given"))
+}
+
+// contentFromRequest returns the content argument, or the contents of content_file.
+// Exactly one of them must be given when required is true; otherwise both may be omitted.
+// Empty content counts as given, so that edit_document can still clear a document.
+func (s *Server) contentFromRequest(req mcp.CallToolRequest, required bool) (string, error) {
+	_, hasContent := req.GetArguments()["content"]
+	content := req.GetString("content", "")
+	path := req.GetString("content_file", "")
+
+	switch {
+	case path != "" && content != "":
+		return "", fmt.Errorf("pass either content or content_file, not both")
+	case path != "":
+		return s.readContentFile(path)
+	case !hasContent && required:
+		if s.contentDir != "" {
+			return "", fmt.Errorf("content or content_file is required")
+		}
+		return "", fmt.Errorf("content is required")
+	}
+	return content, nil
+}
+
+// contentFileLimit is the largest file readContentFile accepts
+func (s *Server) contentFileLimit() int {
+	if limit := s.quipClient.MaxContentBytes(); limit > 0 {
+		return limit
+	}
+	return maxContentFileBytes
+}
+
+// readContentFile reads path, which must resolve to a regular file inside the content directory
+func (s *Server) readContentFile(path string) (string, error) {
+	if s.contentDir == "" {
+		return "", fmt.Errorf("content_file is disabled on this server")
+	}
+
+	root, err := dirRoot(s.contentDir, "content")
+	if err != nil {
+		return "", err
+	}
+
+	target := path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	// Resolve symlinks before checking containment so a link can't point outside the directory
+	target, err = filepath.EvalSymlinks(target)
+	if err != nil {
+		return "", fmt.Errorf("can't read content_file %q: %w", path, err)
+	}
+	if !insideDir(root, target) {
+		return "", fmt.Errorf("content_file %q is outside the content directory", path)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", fmt.Errorf("can't read content_file %q: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("content_file %q is not a regular file", path)
+	}
+
+	limit := s.contentFileLimit()
+	if info.Size() > int64(limit) {
+		return "", fmt.Errorf("content_file %q is %d bytes, over the %d-byte limit", path, info.Size(), limit)
+	}
+
+	f, err := os.Open(target)
+	if err != nil {
+		return "", fmt.Errorf("can't read content_file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	// The file may have grown since Stat; never read past the limit
+	data, err := io.ReadAll(io.LimitReader(f, int64(limit)+1))
+	if err != nil {
+		return "", fmt.Errorf("can't read content_file %q: %w", path, err)
+	}
+	if len(data) > limit {
+		return "", fmt.Errorf("content_file %q is over the %d-byte limit", path, limit)
+	}
+
+	return string(data), nil
+}
+
+// dirRoot returns dir as an absolute path with symlinks resolved; kind names the directory
+// in errors
+func dirRoot(dir, kind string) (string, error) {
+	root, err := filepath.Abs(dir)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s directory is unavailable: %w", kind, err)
+	}
+	return root, nil
+}
+
+// insideDir reports whether target is root or lies below it. Both must be clean absolute paths.
+func insideDir(root, target string) bool {
+	rel, err := filepath.Rel(root, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// writeExportFile writes data to a new file at path inside the export directory. Existing
+// files are never overwritten, the directory the file goes in must already exist, and paths
+// that lead outside the export directory (including through symlinks) are refused.
+func (s *Server) writeExportFile(path string, data []byte) error {
+	if s.exportDir == "" {
+		return fmt.Errorf("saving files is disabled on this server")
+	}
+
+	root, err := dirRoot(s.exportDir, "export")
+	if err != nil {
+		return err
+	}
+
+	target := path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(filepath.Clean(target)))
+	if err != nil {
+		return fmt.Errorf("can't write %q: %w", path, err)
+	}
+	target = filepath.Join(dir, filepath.Base(target))
+	if !insideDir(root, dir) || target == root {
+		return fmt.Errorf("%q is outside the export directory", path)
+	}
+
+	// O_EXCL also refuses a symlink at target, wherever it points
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%q already exists; choose another path", path)
+	}
+	if err != nil {
+		return fmt.Errorf("can't write %q: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("can't write %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("can't write %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_ReadContentFile(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "reports")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	write(filepath.Join(dir, "report.md"), "# Report")
+	write(filepath.Join(dir, "large.md"), strings.Repeat("x", 100))
+	write(filepath.Join(base, "secret.txt"), "secret")
+	if err := os.Symlink(filepath.Join(base, "secret.txt"), filepath.Join(dir, "link.md")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	s := New("test-token", WithContentDir(dir), WithClientOptions(quip.WithMaxContentBytes(50)))
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{name: "relative path", path: "report.md", want: "# Report"},
+		{name: "absolute path inside", path: filepath.Join(dir, "report.md"), want: "# Report"},
+		{name: "parent traversal", path: "../secret.txt", wantErr: "outside the content directory"},
+		{name: "absolute path outside", path: filepath.Join(base, "secret.txt"), wantErr: "outside the content directory"},
+		{name: "symlink outside", path: "link.md", wantErr: "outside the content directory"},
+		{name: "too large", path: "large.md", wantErr: "over the 50-byte limit"},
+		{name: "missing", path: "missing.md", wantErr: "can't read content_file"},
+		{name: "directory", path: ".", wantErr: "not a regular file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.readContentFile(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %q, %v", tt.wantErr, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+
+	if _, err := New("test-token").readContentFile("report.md"); err == nil {
+		t.Error("Expected content_file to be refused without a content directory")
+	}
+}
+
+func TestServer_CreateDocumentFromFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.md"), []byte("# Weekly report"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	var content string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content = r.FormValue("content")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Report"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithContentDir(dir), WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "create_document", map[string]any{"title": "Report", "content_file": "report.md"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(content, "Weekly report") {
+		t.Errorf("Expected the file's content to be sent, got %q", content)
+	}
+
+	text, isError = callTool(t, s, "edit_document", map[string]any{"document_id": "doc123", "content": "x", "content_file": "report.md"})
+	if !isError || !strings.Contains(text, "not both") {
+		t.Errorf("Expected an error when both content and content_file are given, got %q", text)
+	}
+
+	// Empty content is still content, as it was before content_file existed
+	text, isError = callTool(t, s, "edit_document", map[string]any{"document_id": "doc123", "content": ""})
+	if isError {
+		t.Errorf("Expected empty content to be accepted, got error %q", text)
+	}
+	text, isError = callTool(t, s, "edit_document", map[string]any{"document_id": "doc123"})
+	if !isError || !strings.Contains(text, "content or content_file is required") {
+		t.Errorf("Expected an error without content or content_file, got %q", text)
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerConversionTools registers tools that convert content between markdown and Quip HTML
+func (s *Server) registerConversionTools() {
+	// Convert markdown tool
+	convertTool := mcp.NewTool(
+		"convert_markdown",
+		mcp.WithDescription("Preview markdown as Quip-compatible HTML (lists, checklists and tables use Quip's markup)"),
+		mcp.WithString("markdown", mcp.Required(), mcp.Description("The markdown content to convert")),
+	)
+
+	s.addTool(convertTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		markdown, err := req.RequireString("markdown")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid markdown argument: %v", err)), nil
+		}
+
+		html, err := markdownToQuipHTML(markdown)
+		if err != nil {
+			return toolError("convert markdown", err), nil
+		}
+
+		return mcp.NewToolResultText(html), nil
+	})
+}
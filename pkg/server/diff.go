@@ -0,0 +1,262 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxDiffChars caps the size of the diff returned by diff_documents
+const maxDiffChars = 20000
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// maxDiffEdits bounds the edit distance the diff explores before giving up and
+// reporting the inputs as entirely replaced, keeping time and memory bounded
+const maxDiffEdits = 2000
+
+// registerDiffTools registers the document diff tool
+func (s *Server) registerDiffTools() {
+	// Diff documents tool
+	diffTool := mcp.NewTool(
+		"diff_documents",
+		mcp.WithDescription("Show a unified diff of a Quip document's markdown against another document or against earlier content (e.g. to review an edit)"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to treat as the new version")),
+		mcp.WithString("compare_to_document_id", mcp.Description("The ID or URL of the document to treat as the old version")),
+		mcp.WithString("compare_to_content", mcp.Description("Markdown to treat as the old version, e.g. the content read before an edit")),
+	)
+
+	s.addTool(diffTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		otherID := req.GetString("compare_to_document_id", "")
+		oldContent := req.GetString("compare_to_content", "")
+		if (otherID == "") == (oldContent == "") {
+			return mcp.NewToolResultError("Provide exactly one of compare_to_document_id or compare_to_content"), nil
+		}
+
+		client := s.client(ctx)
+		doc, err := client.GetDocument(documentID)
+		if err != nil {
+			return toolError("get document", err), nil
+		}
+
+		oldName := "previous content"
+		if otherID != "" {
+			other, err := client.GetDocument(quip.ResolveThreadID(otherID))
+			if err != nil {
+				return toolError("get comparison document", err), nil
+			}
+			oldName = other.Title
+			oldContent = s.documentMarkdown(other)
+		}
+
+		diff := unifiedDiff(oldName, doc.Title, oldContent, s.documentMarkdown(doc))
+		if diff == "" {
+			return mcp.NewToolResultText("No differences found."), nil
+		}
+
+		response := "```diff\n"
+		truncated := len([]rune(diff)) > maxDiffChars
+		if truncated {
+			diff = truncateText(diff, maxDiffChars) + "\n"
+		}
+		response += diff + "```\n"
+		if truncated {
+			response += fmt.Sprintf("\n⚠️ Diff truncated to %d characters.\n", maxDiffChars)
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// diffOp is one line of a line-based diff: ' ' kept, '-' removed, '+' added
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// diffLines computes a shortest line edit script turning a into b (Myers' algorithm)
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD > maxDiffEdits {
+		maxD = maxDiffEdits
+	}
+
+	// trace[d] holds the furthest x reached on each diagonal k in [-d, d] after d edits
+	var trace [][]int
+	v := map[int]int{1: 0}
+	found := false
+
+	for d := 0; d <= maxD && !found; d++ {
+		row := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			row[k+d] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+		trace = append(trace, row)
+	}
+
+	if !found {
+		ops := make([]diffOp, 0, n+m)
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// Walk the trace backwards to recover the edit script
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[y-1]})
+		} else {
+			ops = append(ops, diffOp{'-', a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{' ', a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// unifiedDiff renders the differences between oldText and newText as a unified diff,
+// returning an empty string when they are identical
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		last := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			} else if i-last > 2*diffContextLines {
+				break
+			}
+		}
+
+		hunkStart := max(first-diffContextLines, start)
+		hunkEnd := min(last+diffContextLines+1, len(ops))
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		oldLine, newLine := lineNumbers(ops, hunkStart)
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+		for _, op := range ops[hunkStart:hunkEnd] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.text)
+			b.WriteByte('\n')
+		}
+
+		start = hunkEnd
+	}
+
+	return b.String()
+}
+
+// lineNumbers returns the 1-based old and new line numbers at ops[index]
+func lineNumbers(ops []diffOp, index int) (oldLine, newLine int) {
+	oldLine, newLine = 1, 1
+	for _, op := range ops[:index] {
+		if op.kind != '+' {
+			oldLine++
+		}
+		if op.kind != '-' {
+			newLine++
+		}
+	}
+	return oldLine, newLine
+}
+
+// hunkRange formats a unified diff range; empty ranges point at the line before
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// splitLines splits text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines_Reconstructs(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{name: "identical", a: "a\nb\nc", b: "a\nb\nc"},
+		{name: "empty to text", a: "", b: "a\nb"},
+		{name: "text to empty", a: "a\nb", b: ""},
+		{name: "insert", a: "a\nc", b: "a\nb\nc"},
+		{name: "delete", a: "a\nb\nc", b: "a\nc"},
+		{name: "replace", a: "a\nb\nc", b: "a\nx\nc"},
+		{name: "mixed", a: "a\nb\nc\na\nb\nb\na", b: "c\nb\na\nb\na\nc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := diffLines(splitLines(tt.a), splitLines(tt.b))
+
+			var oldLines, newLines []string
+			for _, op := range ops {
+				if op.kind != '+' {
+					oldLines = append(oldLines, op.text)
+				}
+				if op.kind != '-' {
+					newLines = append(newLines, op.text)
+				}
+			}
+
+			if strings.Join(oldLines, "\n") != tt.a || strings.Join(newLines, "\n") != tt.b {
+				t.Errorf("Edit script does not reconstruct inputs: %v", ops)
+			}
+		})
+	}
+}
+
+func TestDiffLines_Minimal(t *testing.T) {
+	ops := diffLines(splitLines("a\nb\nc\na\nb\nb\na"), splitLines("c\nb\na\nb\na\nc"))
+
+	edits := 0
+	for _, op := range ops {
+		if op.kind != ' ' {
+			edits++
+		}
+	}
+
+	// The classic Myers example has a shortest edit script of 5 edits
+	if edits != 5 {
+		t.Errorf("Expected 5 edits, got %d: %v", edits, ops)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "line1\nline2\nline3\nline4\nline5\nline6\nline7\nline8\nline9\nline10\nline11\n"
+	newText := "line1\nline2\nline3\nline4 changed\nline5\nline6\nline7\nline8\nline9\nline10\nline11\nline12\n"
+
+	// Changes more than 2*context lines apart get separate hunks
+	expected := `--- old
++++ new
+@@ -1,7 +1,7 @@
+ line1
+ line2
+ line3
+-line4
++line4 changed
+ line5
+ line6
+ line7
+@@ -9,3 +9,4 @@
+ line9
+ line10
+ line11
++line12
+`
+
+	if got := unifiedDiff("old", "new", oldText, newText); got != expected {
+		t.Errorf("unifiedDiff mismatch:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	merged := unifiedDiff("old", "new", "a\nb\nc\nd\ne\nf\ng\nh\n", "a\nB\nc\nd\ne\nf\ng\nH\n")
+	if strings.Count(merged, "@@ -") != 1 {
+		t.Errorf("Expected nearby changes to share one hunk, got:\n%s", merged)
+	}
+
+	if got := unifiedDiff("old", "new", oldText, oldText); got != "" {
+		t.Errorf("Expected empty diff for identical input, got %q", got)
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxDryRunValueChars caps how much of each recorded form value a dry-run result shows
+const maxDryRunValueChars = 500
+
+// WithDryRun makes mutating tools report the Quip requests they would send instead of
+// sending them. Reads still go through so the reported requests are the real ones.
+func WithDryRun(enabled bool) Option {
+	return func(s *Server) {
+		s.dryRun = enabled
+	}
+}
+
+// dryRunToolCall is tool middleware that runs each call with write requests recorded
+// rather than sent. Calls that attempted a write get a "[DRY RUN]" result describing it;
+// read-only calls return their normal result.
+func (s *Server) dryRunToolCall(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		recorder := &quip.DryRunRecorder{}
+		result, err := next(quip.WithDryRunRecorder(ctx, recorder), req)
+
+		requests := recorder.Requests()
+		if len(requests) == 0 {
+			return result, err
+		}
+
+		return mcp.NewToolResultText(formatDryRun(req.Params.Name, requests)), nil
+	}
+}
+
+// formatDryRun describes the write requests a tool call would have sent
+func formatDryRun(tool string, requests []quip.DryRunRequest) string {
+	response := fmt.Sprintf("[DRY RUN] %s would send the following request to Quip; nothing was changed.\n", tool)
+	for _, req := range requests {
+		response += fmt.Sprintf("\n**%s %s**\n", req.Method, req.Endpoint)
+
+		if req.ContentType != "application/x-www-form-urlencoded" {
+			if req.Body != "" {
+				response += fmt.Sprintf("```\n%s\n```\n", truncateText(req.Body, maxDryRunValueChars))
+			}
+			continue
+		}
+
+		values, err := url.ParseQuery(req.Body)
+		if err != nil {
+			response += fmt.Sprintf("```\n%s\n```\n", truncateText(req.Body, maxDryRunValueChars))
+			continue
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value := strings.Join(values[key], ", ")
+			response += fmt.Sprintf("- %s: %s\n", key, truncateText(value, maxDryRunValueChars))
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callTool sends a tools/call request through the MCP server and returns the result's text
+func callTool(t *testing.T, s *Server, name string, args map[string]any) (string, bool) {
+	t.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+
+	response, ok := s.mcpServer.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected a successful JSON-RPC response for %s", name)
+	}
+	result, ok := response.Result.(mcp.CallToolResult)
+	if !ok {
+		t.Fatalf("Expected a tool result for %s, got %T", name, response.Result)
+	}
+
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	return text.String(), result.IsError
+}
+
+func TestServer_DryRun(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected no writes in dry-run mode, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Plan"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithDryRun(true), WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "delete_document", map[string]any{"document_id": "doc123", "confirm": "DELETE"})
+	if isError {
+		t.Fatalf("Expected dry-run result, got error %q", text)
+	}
+	for _, want := range []string{"[DRY RUN] delete_document", "POST /threads/delete", "- thread_id: doc123"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected dry-run result to contain %q, got %q", want, text)
+		}
+	}
+
+	// Reads are unaffected
+	text, isError = callTool(t, s, "get_document", map[string]any{"document_id": "doc123"})
+	if isError || strings.Contains(text, "DRY RUN") || !strings.Contains(text, "**Plan**") {
+		t.Errorf("Expected a normal get_document result, got %q", text)
+	}
+}
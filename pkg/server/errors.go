@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolError builds the error result for a failed action such as "get document". Failures
+// Quip reports are explained so the agent can tell whether to fix its input (4xx) or retry
+// (5xx, network trouble); anything else is reported as is.
+func toolError(action string, err error) *mcp.CallToolResult {
+	return mcp.NewToolResultError(fmt.Sprintf("Failed to %s: %s", action, explainError(err)))
+}
+
+// explainError describes err with advice on what to do about it
+func explainError(err error) string {
+	var apiErr *quip.APIError
+	if errors.As(err, &apiErr) {
+		if advice := statusAdvice(apiErr.StatusCode); advice != "" {
+			return fmt.Sprintf("%s (%v)", advice, err)
+		}
+		return err.Error()
+	}
+
+	if errors.Is(err, quip.ErrCircuitOpen) {
+		return fmt.Sprintf("Quip is temporarily unavailable, so requests are paused instead of adding to the load; retry later (%v)", err)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("couldn't reach Quip; this is usually temporary, so retry in a moment (%v)", err)
+	}
+
+	return err.Error()
+}
+
+// statusAdvice explains an HTTP error status from Quip, or returns "" for statuses without advice
+func statusAdvice(status int) string {
+	switch {
+	case status == http.StatusBadRequest:
+		return "Quip rejected the request as invalid; check the arguments, such as IDs and content format"
+	case status == http.StatusUnauthorized:
+		return "the API token was rejected; it may be expired or revoked, so retrying won't help until it is replaced"
+	case status == http.StatusForbidden:
+		return "you don't have access to this; ask its owner to share it with you"
+	case status == http.StatusNotFound:
+		return "not found; check the ID or URL, since it may be wrong or the thread may have been deleted"
+	case status == http.StatusRequestEntityTooLarge:
+		return "the content is too large for Quip; split it into smaller pieces"
+	case status == http.StatusTooManyRequests:
+		return "Quip's rate limit was reached; wait a minute and retry"
+	case status >= 500:
+		return "Quip had a server error; this is usually temporary, so retry in a moment"
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestExplainError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "bad request", err: &quip.APIError{StatusCode: 400}, want: "check the arguments"},
+		{name: "unauthorized", err: &quip.APIError{StatusCode: 401}, want: "token was rejected"},
+		{name: "forbidden", err: &quip.APIError{StatusCode: 403}, want: "you don't have access"},
+		{name: "not found", err: &quip.APIError{StatusCode: 404}, want: "not found; check the ID or URL"},
+		{name: "rate limited", err: &quip.APIError{StatusCode: 429}, want: "wait a minute and retry"},
+		{name: "server error", err: &quip.APIError{StatusCode: 503}, want: "retry in a moment"},
+		{name: "wrapped", err: fmt.Errorf("failed to get folder: %w", &quip.APIError{StatusCode: 404}), want: "not found"},
+		{name: "other status", err: &quip.APIError{StatusCode: 409, Body: "conflict"}, want: "API error 409: conflict"},
+		{name: "not an API error", err: errors.New("invalid HTML"), want: "invalid HTML"},
+		{name: "circuit open", err: fmt.Errorf("GET /threads/abc: %w", quip.ErrCircuitOpen), want: "Quip is temporarily unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := explainError(tt.err); !strings.Contains(got, tt.want) {
+				t.Errorf("Expected explanation to contain %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestServer_ToolErrors(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/threads/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_document", map[string]any{"document_id": "missing"})
+	if !isError || !strings.Contains(text, "Failed to get document: not found; check the ID or URL") || !strings.Contains(text, "API error 404") {
+		t.Errorf("Expected an actionable not-found error, got %q", text)
+	}
+
+	text, isError = callTool(t, s, "get_document", map[string]any{"document_id": "doc123"})
+	if !isError || !strings.Contains(text, "retry in a moment") {
+		t.Errorf("Expected a retryable server error, got %q", text)
+	}
+
+	quipServer.Close()
+	text, isError = callTool(t, s, "get_document", map[string]any{"document_id": "doc123"})
+	if !isError || !strings.Contains(text, "couldn't reach Quip") {
+		t.Errorf("Expected a network error suggesting a retry, got %q", text)
+	}
+}
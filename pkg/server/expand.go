@@ -0,0 +1,123 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+// Link expansion limits for get_document's expand_links. Each level multiplies the number of
+// documents fetched, so depth is kept shallow and the total is capped.
+const (
+	maxExpandDepth      = 3
+	maxLinkExpansions   = 10
+	maxExpandedDocChars = 4000
+)
+
+// linkedDocument is a thread reached by following links from the requested document
+type linkedDocument struct {
+	id    string
+	depth int
+	doc   *quip.Document
+	err   error
+}
+
+// threadLinkIDs returns the IDs of the Quip threads linked from htmlContent, in document order
+// and without duplicates. @mentions link to people, not threads, and are skipped. Links on
+// hosts other than quip.com are only followed when listed in hosts.
+func threadLinkIDs(htmlContent string, hosts ...string) []string {
+	dom, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	dom.Find("a[href]").Each(func(_ int, link *goquery.Selection) {
+		if isQuipMention(link) {
+			return
+		}
+		id, err := quip.ParseThreadURL(link.AttrOr("href", ""), hosts...)
+		if err != nil || seen[id] {
+			return
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	})
+	return ids
+}
+
+// expandLinks follows thread links from doc breadth-first, up to depth levels, and returns
+// the linked threads in the order they were reached. Each thread is fetched at most once, so
+// documents that link to each other don't loop, and at most maxLinkExpansions are fetched.
+// The second result reports whether links were left unfollowed because of that cap.
+func expandLinks(client *quip.Client, doc *quip.Document, depth int) ([]linkedDocument, bool) {
+	host := quip.WebHost(client.BaseURL())
+	visited := map[string]bool{doc.ID: true}
+	pending := threadLinkIDs(doc.HTML, host)
+	var linked []linkedDocument
+	capped := false
+
+	for level := 1; level <= depth && len(pending) > 0; level++ {
+		var ids []string
+		for _, id := range pending {
+			if visited[id] {
+				continue
+			}
+			if len(linked)+len(ids) >= maxLinkExpansions {
+				capped = true
+				break
+			}
+			visited[id] = true
+			ids = append(ids, id)
+		}
+
+		pending = nil
+		for _, result := range client.GetDocuments(ids, 0) {
+			linked = append(linked, linkedDocument{id: result.ID, depth: level, doc: result.Document, err: result.Err})
+			if result.Err != nil {
+				continue
+			}
+			// A link may use a different ID for the thread than the one it reports
+			visited[result.Document.ID] = true
+			if level < depth {
+				pending = append(pending, threadLinkIDs(result.Document.HTML, host)...)
+			}
+		}
+	}
+
+	return linked, capped
+}
+
+// formatLinkedDocuments renders the threads found by expandLinks, with each one's markdown
+// content (shortened to maxExpandedDocChars) when withContent is set
+func (s *Server) formatLinkedDocuments(linked []linkedDocument, capped, withContent bool) string {
+	if len(linked) == 0 {
+		return "\n---\n\n_No links to other Quip documents were found._\n"
+	}
+
+	response := fmt.Sprintf("\n---\n\n## Linked Documents (%d)\n", len(linked))
+	for i, item := range linked {
+		if item.err != nil {
+			response += fmt.Sprintf("\n### %d. %s (depth %d)\n\n_Couldn't be fetched: %s_\n", i+1, item.id, item.depth, explainError(item.err))
+			continue
+		}
+
+		response += fmt.Sprintf("\n### %d. %s (depth %d)\n\n", i+1, item.doc.Title, item.depth)
+		response += fmt.Sprintf("- **ID:** %s\n", item.doc.ID)
+		if item.doc.Link != "" {
+			response += fmt.Sprintf("- **Link:** %s\n", item.doc.Link)
+		}
+		if withContent {
+			response += "\n" + truncateText(strings.TrimSpace(s.documentMarkdown(item.doc)), maxExpandedDocChars) + "\n"
+		}
+	}
+
+	if capped {
+		response += fmt.Sprintf("\n_Stopped after %d linked documents; fetch the others with get_document._\n", maxLinkExpansions)
+	}
+
+	return response
+}
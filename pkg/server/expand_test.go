@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestThreadLinkIDs(t *testing.T) {
+	html := `<p><a href="https://quip.com/doc2/Budget">Budget</a>, <a href="https://acme.quip.com/doc3">x</a>,
+<a href="https://quip.com/doc2">again</a>, <a href="https://example.com/doc9">elsewhere</a>,
+<a class="mention" href="https://quip.com/user1">@Ann</a></p>`
+
+	if got, want := threadLinkIDs(html), []string{"doc2", "doc3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("threadLinkIDs() = %v, expected %v", got, want)
+	}
+}
+
+func TestServer_GetDocument_ExpandLinks(t *testing.T) {
+	docs := map[string]quip.Document{
+		"doc1": {ID: "doc1", Title: "Plan", HTML: `<p>See <a href="https://quip.com/doc2">Budget</a> and <a href="https://quip.com/gone">old</a></p>`},
+		"doc2": {ID: "doc2", Title: "Budget", Link: "https://quip.com/doc2", HTML: `<p>Back to <a href="https://quip.com/doc1">Plan</a>, see <a href="https://quip.com/doc3">Costs</a></p>`},
+		"doc3": {ID: "doc3", Title: "Costs", HTML: `<p>Detailed costs</p><p><a href="https://quip.com/doc4">Deeper</a></p>`},
+		"doc4": {ID: "doc4", Title: "Deeper", HTML: `<p>Too deep</p>`},
+	}
+	var fetched []string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/threads/")
+		doc, ok := docs[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+			return
+		}
+		fetched = append(fetched, id)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: doc})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL), quip.WithMaxConcurrency(1)))
+
+	text, isError := callTool(t, s, "get_document", map[string]any{"document_id": "doc1", "expand_links": 2, "expand_content": true})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	for _, want := range []string{
+		"## Linked Documents (3)",
+		"### 1. Budget (depth 1)",
+		"- **Link:** https://quip.com/doc2",
+		"### 2. gone (depth 1)\n\n_Couldn't be fetched:",
+		"### 3. Costs (depth 2)",
+		"Detailed costs",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected result to contain %q, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "Too deep") {
+		t.Errorf("Expected links beyond the depth limit not to be followed, got %q", text)
+	}
+	if got := strings.Join(fetched, ","); got != "doc1,doc2,doc3" {
+		t.Errorf("Expected each document to be fetched once, got %s", got)
+	}
+
+	text, _ = callTool(t, s, "get_document", map[string]any{"document_id": "doc1", "expand_links": 1})
+	if !strings.Contains(text, "### 1. Budget (depth 1)") || strings.Contains(text, "Back to") {
+		t.Errorf("Expected titles only without expand_content, got %q", text)
+	}
+}
+
+func TestServer_GetDocument_ExpandLinksCap(t *testing.T) {
+	var links strings.Builder
+	for i := 0; i < maxLinkExpansions+5; i++ {
+		fmt.Fprintf(&links, `<a href="https://quip.com/linked%d">doc</a> `, i)
+	}
+
+	var requests int32
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		id := strings.TrimPrefix(r.URL.Path, "/threads/")
+		html := "<p>leaf</p>"
+		if id == "root" {
+			html = "<p>" + links.String() + "</p>"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: id, Title: id, HTML: html}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_document", map[string]any{"document_id": "root", "expand_links": 1})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(text, fmt.Sprintf("Stopped after %d linked documents", maxLinkExpansions)) {
+		t.Errorf("Expected the expansion cap to be reported, got %q", text)
+	}
+	if got := atomic.LoadInt32(&requests); got != maxLinkExpansions+1 {
+		t.Errorf("Expected %d requests, got %d", maxLinkExpansions+1, got)
+	}
+}
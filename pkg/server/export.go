@@ -0,0 +1,252 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Export limits. A folder tree can be arbitrarily large, so export_folder stops descending
+// after maxExportDepth levels of subfolders and exporting after maxExportDocuments documents.
+const (
+	defaultExportDepth = 1
+	maxExportDepth     = 5
+	maxExportDocuments = 200
+	maxExportNameRunes = 100
+)
+
+// maxInlineExportBytes caps the zip returned inline; larger exports must be saved to a file
+const maxInlineExportBytes = 10 << 20
+
+// exportThread is a thread found while walking a folder tree, with the zip directory it goes in
+type exportThread struct {
+	id  string
+	dir string
+}
+
+// registerExportTools registers the folder export tool
+func (s *Server) registerExportTools() {
+	// Export folder tool
+	opts := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf("Export the documents in a Quip folder as a zip of markdown files, one per document, with subfolders as directories. Chats are skipped and at most %d documents are exported.", maxExportDocuments)),
+		mcp.WithString("folder_id", mcp.Required(), mcp.Description("The ID or URL of the folder to export (see list_folders)")),
+		mcp.WithNumber("depth",
+			mcp.Description(fmt.Sprintf("How many levels of subfolders to include: 0 for the folder's own documents only (default: %d)", defaultExportDepth)),
+			mcp.Min(0),
+			mcp.Max(maxExportDepth),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description(fmt.Sprintf("How many documents to fetch in parallel (default: %d)", quip.DefaultFetchConcurrency)),
+			mcp.Min(1),
+			mcp.Max(maxFetchConcurrency),
+		),
+	}
+	if s.exportDir != "" {
+		opts = append(opts, mcp.WithString("output_path", mcp.Description(fmt.Sprintf("Save the zip to this new file on the server, relative to %s, instead of returning it; existing files aren't overwritten", s.exportDir))))
+	}
+	exportTool := mcp.NewTool("export_folder", opts...)
+
+	s.addTool(exportTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		folderID, err := req.RequireString("folder_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid folder_id argument: %v", err)), nil
+		}
+		folderID = quip.ResolveThreadID(folderID)
+
+		depth := req.GetInt("depth", defaultExportDepth)
+		if depth < 0 || depth > maxExportDepth {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid depth argument: must be between 0 and %d", maxExportDepth)), nil
+		}
+
+		concurrency := req.GetInt("concurrency", quip.DefaultFetchConcurrency)
+		if concurrency < 1 || concurrency > maxFetchConcurrency {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid concurrency argument: must be between 1 and %d", maxFetchConcurrency)), nil
+		}
+
+		outputPath := req.GetString("output_path", "")
+		if outputPath != "" && s.exportDir == "" {
+			return mcp.NewToolResultError("Invalid output_path argument: saving files is disabled on this server"), nil
+		}
+
+		client := s.client(ctx)
+		folder, err := client.GetFolder(folderID)
+		if err != nil {
+			return toolError("get folder", err), nil
+		}
+
+		threads, err := collectFolderThreads(client, folder, depth)
+		if err != nil {
+			return toolError("get subfolders", err), nil
+		}
+		truncated := len(threads) > maxExportDocuments
+		if truncated {
+			threads = threads[:maxExportDocuments]
+		}
+
+		ids := make([]string, len(threads))
+		for i, thread := range threads {
+			ids[i] = thread.id
+		}
+		results := client.GetDocuments(ids, concurrency)
+
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		names := make(map[string]bool)
+		exported, skipped := 0, 0
+		var failures []string
+		for i, result := range results {
+			if result.Err != nil {
+				failures = append(failures, fmt.Sprintf("- %s: %s", result.ID, explainError(result.Err)))
+				continue
+			}
+			if result.Document.Type == "chat" {
+				skipped++
+				continue
+			}
+
+			name := exportFileName(threads[i].dir, result.Document, names)
+			w, err := archive.Create(name)
+			if err == nil {
+				_, err = w.Write([]byte(s.documentMarkdown(result.Document)))
+			}
+			if err != nil {
+				return toolError("build export", err), nil
+			}
+			exported++
+		}
+		if err := archive.Close(); err != nil {
+			return toolError("build export", err), nil
+		}
+
+		title := folder.Folder.Title
+		if title == "" {
+			title = folderID
+		}
+		summary := fmt.Sprintf("📦 **Exported %s from %s**\n\n", pluralize(exported, "document"), title)
+		if skipped > 0 {
+			summary += fmt.Sprintf("- Skipped %s\n", pluralize(skipped, "chat"))
+		}
+		if truncated {
+			summary += fmt.Sprintf("- Stopped after %d documents; export subfolders separately or lower depth to get the rest\n", maxExportDocuments)
+		}
+		if len(failures) > 0 {
+			summary += fmt.Sprintf("\n%s couldn't be fetched:\n%s\n", pluralize(len(failures), "document"), strings.Join(failures, "\n"))
+		}
+
+		if outputPath != "" {
+			if err := s.writeExportFile(outputPath, buf.Bytes()); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to save export: %v", err)), nil
+			}
+			summary += fmt.Sprintf("\nSaved to %s (%d bytes).\n", outputPath, buf.Len())
+			return mcp.NewToolResultText(summary), nil
+		}
+
+		if buf.Len() > maxInlineExportBytes {
+			hint := "export a smaller folder or lower depth"
+			if s.exportDir != "" {
+				hint = "pass output_path to save it to a file instead"
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("The export is %d bytes, more than the %d that can be returned inline; %s", buf.Len(), maxInlineExportBytes, hint)), nil
+		}
+
+		return mcp.NewToolResultResource(summary, mcp.BlobResourceContents{
+			URI:      fmt.Sprintf("quip://folder/%s/export.zip", folderID),
+			MIMEType: "application/zip",
+			Blob:     base64.StdEncoding.EncodeToString(buf.Bytes()),
+		}), nil
+	})
+}
+
+// collectFolderThreads walks folder breadth-first, descending at most depth levels of
+// subfolders, and returns its threads in folder order along with the zip directory each one
+// belongs in. A folder reachable through more than one path is only visited once, and a thread
+// filed in several folders is only exported from the first one it's found in.
+func collectFolderThreads(client *quip.Client, folder *quip.FolderData, depth int) ([]exportThread, error) {
+	type level struct {
+		folder quip.FolderData
+		dir    string
+	}
+
+	visited := map[string]bool{folder.Folder.ID: true}
+	seenThreads := make(map[string]bool)
+	current := []level{{*folder, exportName(folder.Folder.Title, folder.Folder.ID)}}
+	var threads []exportThread
+
+	for d := 0; len(current) > 0; d++ {
+		var subIDs []string
+		parents := make(map[string]string)
+		for _, f := range current {
+			for _, id := range f.folder.ThreadIDs() {
+				if !seenThreads[id] {
+					seenThreads[id] = true
+					threads = append(threads, exportThread{id: id, dir: f.dir})
+				}
+			}
+			if d >= depth {
+				continue
+			}
+			for _, id := range f.folder.SubfolderIDs() {
+				if !visited[id] {
+					visited[id] = true
+					subIDs = append(subIDs, id)
+					parents[id] = f.dir
+				}
+			}
+		}
+		if len(subIDs) == 0 || len(threads) > maxExportDocuments {
+			break
+		}
+
+		subfolders, err := client.GetFolders(subIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		current = nil
+		for _, id := range subIDs {
+			if sub, ok := subfolders[id]; ok {
+				current = append(current, level{sub, path.Join(parents[id], exportName(sub.Folder.Title, id))})
+			}
+		}
+	}
+
+	return threads, nil
+}
+
+// exportFileName picks a unique zip entry name for doc in dir, falling back to the title plus
+// the document ID when the title alone is taken
+func exportFileName(dir string, doc *quip.Document, used map[string]bool) string {
+	name := path.Join(dir, exportName(doc.Title, doc.ID)+".md")
+	if used[strings.ToLower(name)] {
+		name = path.Join(dir, exportName(doc.Title, doc.ID)+" ("+doc.ID+").md")
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// exportName turns a title into something usable as a file or directory name on any platform
+func exportName(title, fallback string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '-'
+		}
+		return r
+	}, title)
+
+	if runes := []rune(name); len(runes) > maxExportNameRunes {
+		name = string(runes[:maxExportNameRunes])
+	}
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
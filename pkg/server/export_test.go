@@ -0,0 +1,232 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestExportName(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{title: "Q3 Roadmap", expected: "Q3 Roadmap"},
+		{title: "Plans: 2024/2025?", expected: "Plans- 2024-2025-"},
+		{title: " .hidden. ", expected: "hidden"},
+		{title: "", expected: "doc1"},
+		{title: strings.Repeat("é", 150), expected: strings.Repeat("é", maxExportNameRunes)},
+	}
+
+	for _, tt := range tests {
+		if got := exportName(tt.title, "doc1"); got != tt.expected {
+			t.Errorf("exportName(%q) = %q, expected %q", tt.title, got, tt.expected)
+		}
+	}
+}
+
+// newExportQuipServer serves a folder tree: fold1 holds doc1, doc2 (same title as doc1), a
+// chat and fold2; fold2 holds doc3 and fold3, which holds doc4 and doc1 again
+func newExportQuipServer(t *testing.T) *httptest.Server {
+	folders := map[string]quip.FolderData{
+		"fold1": {Folder: quip.Folder{ID: "fold1", Title: "Projects"}, Children: []quip.FolderChild{{ThreadID: "doc1"}, {ThreadID: "doc2"}, {ThreadID: "chat1"}, {FolderID: "fold2"}, {ThreadID: "missing"}}},
+		"fold2": {Folder: quip.Folder{ID: "fold2", Title: "Archive/Old"}, Children: []quip.FolderChild{{ThreadID: "doc3"}, {FolderID: "fold3"}}},
+		"fold3": {Folder: quip.Folder{ID: "fold3", Title: "Deep"}, Children: []quip.FolderChild{{ThreadID: "doc4"}, {ThreadID: "doc1"}}},
+	}
+	threads := map[string]quip.Document{
+		"doc1":  {ID: "doc1", Title: "Notes", Type: "document", HTML: "<p>first</p>"},
+		"doc2":  {ID: "doc2", Title: "Notes", Type: "document", HTML: "<p>second</p>"},
+		"chat1": {ID: "chat1", Title: "Chatter", Type: "chat"},
+		"doc3":  {ID: "doc3", Title: "Old plan", Type: "document", HTML: "<p>third</p>"},
+		"doc4":  {ID: "doc4", Title: "Deep doc", Type: "document", HTML: "<p>fourth</p>"},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/folders/":
+			response := make(map[string]quip.FolderData)
+			for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+				response[id] = folders[id]
+			}
+			_ = json.NewEncoder(w).Encode(response)
+		case strings.HasPrefix(r.URL.Path, "/folders/"):
+			_ = json.NewEncoder(w).Encode(folders[strings.TrimPrefix(r.URL.Path, "/folders/")])
+		case strings.HasPrefix(r.URL.Path, "/threads/"):
+			doc, ok := threads[strings.TrimPrefix(r.URL.Path, "/threads/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error": "Not found"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: doc})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+}
+
+// readExportZip returns the file names and contents of the zip in an export_folder result
+func readExportZip(t *testing.T, result *mcp.CallToolResult) map[string]string {
+	t.Helper()
+
+	for _, content := range result.Content {
+		resource, ok := content.(mcp.EmbeddedResource)
+		if !ok {
+			continue
+		}
+		blob, ok := resource.Resource.(mcp.BlobResourceContents)
+		if !ok || blob.MIMEType != "application/zip" {
+			t.Fatalf("Expected a zip blob, got %+v", resource.Resource)
+		}
+		data, err := base64.StdEncoding.DecodeString(blob.Blob)
+		if err != nil {
+			t.Fatalf("Failed to decode blob: %v", err)
+		}
+		return unzip(t, data)
+	}
+
+	t.Fatal("Expected an embedded resource in the result")
+	return nil
+}
+
+func unzip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open zip: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestServer_ExportFolder(t *testing.T) {
+	quipServer := newExportQuipServer(t)
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	// callTool only returns text, so go through the MCP server directly to get the zip
+	message, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "export_folder", "arguments": map[string]any{"folder_id": "fold1"}},
+	})
+	response, ok := s.mcpServer.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatal("Expected a successful JSON-RPC response")
+	}
+	toolResult, ok := response.Result.(mcp.CallToolResult)
+	if !ok {
+		t.Fatalf("Expected a tool result, got %T", response.Result)
+	}
+	if toolResult.IsError {
+		t.Fatalf("Expected success, got %+v", toolResult.Content)
+	}
+
+	files := readExportZip(t, &toolResult)
+	expected := map[string]string{
+		"Projects/Notes.md":                "first",
+		"Projects/Notes (doc2).md":         "second",
+		"Projects/Archive-Old/Old plan.md": "third",
+	}
+	if len(files) != len(expected) {
+		t.Errorf("Expected %d files, got %v", len(expected), files)
+	}
+	for name, want := range expected {
+		if !strings.Contains(files[name], want) {
+			t.Errorf("Expected %s to contain %q, got %q", name, want, files[name])
+		}
+	}
+
+	var summary string
+	for _, content := range toolResult.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			summary += text.Text
+		}
+	}
+	for _, want := range []string{"Exported 3 documents from Projects", "Skipped 1 chat", "1 document couldn't be fetched", "- missing:"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected summary to contain %q, got %q", want, summary)
+		}
+	}
+}
+
+func TestServer_ExportFolder_OutputPath(t *testing.T) {
+	quipServer := newExportQuipServer(t)
+	defer quipServer.Close()
+
+	dir := t.TempDir()
+	s := New("test-token", WithExportDir(dir), WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "export_folder", map[string]any{"folder_id": "fold1", "depth": 2, "output_path": "projects.zip"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(text, "Exported 4 documents") || !strings.Contains(text, "Saved to projects.zip") {
+		t.Errorf("Expected the saved export to be reported, got %q", text)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "projects.zip"))
+	if err != nil {
+		t.Fatalf("Expected the zip to be saved: %v", err)
+	}
+	files := unzip(t, data)
+	if !strings.Contains(files["Projects/Archive-Old/Deep/Deep doc.md"], "fourth") {
+		t.Errorf("Expected the nested folder to be exported, got %v", files)
+	}
+	if _, ok := files["Projects/Archive-Old/Deep/Notes.md"]; ok {
+		t.Errorf("Expected a thread filed in two folders to be exported once, got %v", files)
+	}
+
+	text, isError = callTool(t, s, "export_folder", map[string]any{"folder_id": "fold1", "output_path": "projects.zip"})
+	if !isError || !strings.Contains(text, "already exists") {
+		t.Errorf("Expected an existing file to be left alone, got %q", text)
+	}
+	if saved, _ := os.ReadFile(filepath.Join(dir, "projects.zip")); !bytes.Equal(saved, data) {
+		t.Error("Expected the existing zip to be unchanged")
+	}
+
+	text, isError = callTool(t, s, "export_folder", map[string]any{"folder_id": "fold1", "output_path": "../escape.zip"})
+	if !isError || !strings.Contains(text, "outside the export directory") {
+		t.Errorf("Expected a path outside the export directory to be refused, got %q", text)
+	}
+
+	// A content directory is for reading only
+	readOnly := New("test-token", WithContentDir(dir), WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+	text, isError = callTool(t, readOnly, "export_folder", map[string]any{"folder_id": "fold1", "output_path": "other.zip"})
+	if !isError || !strings.Contains(text, "disabled") {
+		t.Errorf("Expected output_path to be refused without an export directory, got %q", text)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.zip")); err == nil {
+		t.Error("Expected nothing to be written to the content directory")
+	}
+
+	text, isError = callTool(t, s, "export_folder", map[string]any{"folder_id": "fold1", "concurrency": 0})
+	if !isError || !strings.Contains(text, "Invalid concurrency argument") {
+		t.Errorf("Expected an invalid concurrency to be rejected, got %q", text)
+	}
+}
@@ -0,0 +1,283 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Page sizes for list_user_folders
+const (
+	defaultUserFoldersLimit = 50
+	maxUserFoldersLimit     = 100
+)
+
+// registerFolderTools registers the folder browsing and organizing tools
+func (s *Server) registerFolderTools() {
+	// List folders tool
+	listFoldersTool := mcp.NewTool(
+		"list_folders",
+		mcp.WithDescription("List the current user's top-level Quip folders (private, shared and group), optionally with their subfolders"),
+		mcp.WithNumber("depth", mcp.Description("How many levels to show: 1 for top-level folders only (default), 2 to include subfolders"), mcp.Min(1), mcp.Max(2)),
+	)
+
+	s.addTool(listFoldersTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		depth := req.GetInt("depth", 1)
+		if depth < 1 || depth > 2 {
+			return mcp.NewToolResultError("Invalid depth argument: must be 1 or 2"), nil
+		}
+
+		client := s.client(ctx)
+		roots, err := client.GetRootFolders()
+		if err != nil {
+			return toolError("list folders", err), nil
+		}
+
+		if len(roots) == 0 {
+			return mcp.NewToolResultText("No folders found."), nil
+		}
+
+		var subfolders map[string]quip.FolderData
+		if depth > 1 {
+			var ids []string
+			for _, root := range roots {
+				ids = append(ids, root.SubfolderIDs()...)
+			}
+			subfolders, err = client.GetFolders(ids)
+			if err != nil {
+				return toolError("get subfolders", err), nil
+			}
+		}
+
+		response := fmt.Sprintf("Found %d top-level folders:\n\n", len(roots))
+		for _, root := range roots {
+			response += fmt.Sprintf("- %s (%s)\n", formatFolderLine(&root.FolderData), root.Kind)
+			for _, id := range root.SubfolderIDs() {
+				if sub, ok := subfolders[id]; ok {
+					response += fmt.Sprintf("  - %s\n", formatFolderLine(&sub))
+				}
+			}
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// List user folders tool
+	userFoldersTool := mcp.NewTool(
+		"list_user_folders",
+		mcp.WithDescription("List the top-level Quip folders a user can access and how (owner, member or group), for access reviews. For users other than yourself only folders you can see are listed."),
+		mcp.WithString("user_id", mcp.Required(), mcp.Description("The ID or email address of the user (use 'current' for yourself)")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of folders to list (default: %d)", defaultUserFoldersLimit)), mcp.Min(1), mcp.Max(maxUserFoldersLimit)),
+		mcp.WithNumber("offset", mcp.Description("How many folders to skip, to page through long lists (default: 0)"), mcp.Min(0)),
+	)
+
+	s.addTool(userFoldersTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		userID, err := req.RequireString("user_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid user_id argument: %v", err)), nil
+		}
+
+		limit := req.GetInt("limit", defaultUserFoldersLimit)
+		if limit < 1 || limit > maxUserFoldersLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid limit argument: must be between 1 and %d", maxUserFoldersLimit)), nil
+		}
+		offset := req.GetInt("offset", 0)
+		if offset < 0 {
+			return mcp.NewToolResultError("Invalid offset argument: must not be negative"), nil
+		}
+
+		client := s.client(ctx)
+		user, err := lookupUser(client, userID)
+		if err != nil {
+			return toolError("get user", err), nil
+		}
+
+		folders, err := client.GetUserFolders(user)
+		if err != nil {
+			return toolError("list user folders", err), nil
+		}
+
+		return mcp.NewToolResultText(formatUserFolders(user, folders, limit, offset, strings.TrimSpace(userID) == "current")), nil
+	})
+
+	// Get folder documents tool
+	folderDocsTool := mcp.NewTool(
+		"get_folder_documents",
+		mcp.WithDescription("List the documents in a Quip folder with their titles, types, links and last-updated times, plus its subfolders"),
+		mcp.WithString("folder_id", mcp.Required(), mcp.Description("The ID or URL of the folder (see list_folders)")),
+	)
+
+	s.addTool(folderDocsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		folderID, err := req.RequireString("folder_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid folder_id argument: %v", err)), nil
+		}
+		folderID = quip.ResolveThreadID(folderID)
+
+		client := s.client(ctx)
+		folder, err := client.GetFolder(folderID)
+		if err != nil {
+			return toolError("get folder", err), nil
+		}
+
+		threads, err := client.GetThreads(folder.ThreadIDs())
+		if err != nil {
+			return toolError("get folder documents", err), nil
+		}
+		subfolders, err := client.GetFolders(folder.SubfolderIDs())
+		if err != nil {
+			return toolError("get subfolders", err), nil
+		}
+
+		return mcp.NewToolResultText(s.formatFolderContents(folder, threads, subfolders)), nil
+	})
+
+	// Move document tool
+	moveTool := mcp.NewTool(
+		"move_document",
+		mcp.WithDescription("Move a Quip document into a folder. By default it is only added to the target folder and stays in its current one too; set remove_from_current to true to also take it out of the folder it currently lives in."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to move")),
+		mcp.WithString("target_folder_id", mcp.Required(), mcp.Description("The ID or URL of the folder to move it to (see list_folders)")),
+		mcp.WithBoolean("remove_from_current", mcp.Description("Also remove the document from its current folder (default: false)")),
+	)
+
+	s.addTool(moveTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		folderID, err := req.RequireString("target_folder_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid target_folder_id argument: %v", err)), nil
+		}
+		folderID = quip.ResolveThreadID(folderID)
+
+		client := s.client(ctx)
+		folderIDs, err := client.MoveToFolder(documentID, folderID, req.GetBool("remove_from_current", false))
+		if err != nil {
+			return toolError("move document", err), nil
+		}
+
+		// Titles are a nicety; the move itself has succeeded
+		folders, _ := client.GetFolders(folderIDs)
+
+		response := "✅ **Document moved!**\n\n"
+		response += fmt.Sprintf("- **ID:** %s\n", documentID)
+		response += "- **Now in:**\n"
+		for _, id := range folderIDs {
+			if folder, ok := folders[id]; ok && folder.Folder.Title != "" {
+				response += fmt.Sprintf("  - %s (%s)\n", folder.Folder.Title, id)
+			} else {
+				response += fmt.Sprintf("  - %s\n", id)
+			}
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// formatFolderContents renders a folder's subfolders and documents in the folder's own order.
+// Documents missing from threads couldn't be read and are listed by ID only.
+func (s *Server) formatFolderContents(folder *quip.FolderData, threads map[string]quip.Document, subfolders map[string]quip.FolderData) string {
+	response := formatFolderLine(folder) + "\n"
+
+	if ids := folder.SubfolderIDs(); len(ids) > 0 {
+		response += "\n**Subfolders:**\n"
+		for _, id := range ids {
+			if sub, ok := subfolders[id]; ok {
+				response += fmt.Sprintf("- %s\n", formatFolderLine(&sub))
+			} else {
+				response += fmt.Sprintf("- %s (not accessible)\n", id)
+			}
+		}
+	}
+
+	ids := folder.ThreadIDs()
+	if len(ids) == 0 {
+		return response
+	}
+
+	response += "\n**Documents:**\n"
+	for _, id := range ids {
+		doc, ok := threads[id]
+		if !ok {
+			response += fmt.Sprintf("- %s (not accessible)\n", id)
+			continue
+		}
+		response += fmt.Sprintf("- **%s** (%s) — ID: %s, updated %s\n", doc.Title, doc.Type, doc.ID, s.formatTimestamp(doc.Updated))
+		if doc.Link != "" {
+			response += fmt.Sprintf("  %s\n", doc.Link)
+		}
+	}
+
+	return response
+}
+
+// formatUserFolders renders one page of the folders a user can access
+func formatUserFolders(user *quip.User, folders []quip.UserFolder, limit, offset int, isCurrent bool) string {
+	name := user.Name
+	if name == "" {
+		name = user.ID
+	}
+
+	if len(folders) == 0 {
+		return fmt.Sprintf("No folders found that %s can access.", name)
+	}
+	if offset >= len(folders) {
+		return fmt.Sprintf("%s can access %s; offset %d is past the end.", name, pluralize(len(folders), "folder"), offset)
+	}
+
+	page := folders[offset:min(offset+limit, len(folders))]
+	response := fmt.Sprintf("%s can access %s", name, pluralize(len(folders), "folder"))
+	if len(page) < len(folders) {
+		response += fmt.Sprintf(" (showing %d-%d)", offset+1, offset+len(page))
+	}
+	response += ":\n\n"
+
+	for _, folder := range page {
+		response += fmt.Sprintf("- %s — %s, %s\n", formatFolderLine(&folder.FolderData), folder.Access, pluralize(len(folder.MemberIDs), "member"))
+	}
+
+	if next := offset + len(page); next < len(folders) {
+		response += fmt.Sprintf("\nPass offset %d to see more.\n", next)
+	}
+	if !isCurrent {
+		response += "\n_Quip only lists another user's folders through ones you can see, so folders they don't share with you are missing._\n"
+	}
+
+	return response
+}
+
+// formatFolderLine renders a folder's title, ID and child counts on one line
+func formatFolderLine(folder *quip.FolderData) string {
+	title := folder.Folder.Title
+	if title == "" {
+		title = "Untitled"
+	}
+
+	var counts []string
+	if n := len(folder.ThreadIDs()); n > 0 {
+		counts = append(counts, pluralize(n, "document"))
+	}
+	if n := len(folder.SubfolderIDs()); n > 0 {
+		counts = append(counts, pluralize(n, "subfolder"))
+	}
+	if len(counts) == 0 {
+		counts = append(counts, "empty")
+	}
+
+	return fmt.Sprintf("**%s** — ID: %s, %s", title, folder.Folder.ID, strings.Join(counts, ", "))
+}
+
+// pluralize formats a count with a singular or plural noun
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
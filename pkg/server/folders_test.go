@@ -0,0 +1,181 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestFormatFolderLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		folder   quip.FolderData
+		expected string
+	}{
+		{
+			name: "documents and subfolders",
+			folder: quip.FolderData{
+				Folder:   quip.Folder{ID: "fold1", Title: "Projects"},
+				Children: []quip.FolderChild{{ThreadID: "doc1"}, {ThreadID: "doc2"}, {FolderID: "fold2"}},
+			},
+			expected: "**Projects** — ID: fold1, 2 documents, 1 subfolder",
+		},
+		{
+			name:     "empty untitled",
+			folder:   quip.FolderData{Folder: quip.Folder{ID: "fold3"}},
+			expected: "**Untitled** — ID: fold3, empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatFolderLine(&tt.folder); got != tt.expected {
+				t.Errorf("formatFolderLine() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServer_GetFolderDocuments(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/folders/fold1":
+			_ = json.NewEncoder(w).Encode(quip.FolderData{
+				Folder:   quip.Folder{ID: "fold1", Title: "Projects"},
+				Children: []quip.FolderChild{{ThreadID: "doc2"}, {FolderID: "fold2"}, {ThreadID: "doc1"}, {ThreadID: "secret"}},
+			})
+		case "/threads/":
+			if ids := r.URL.Query().Get("ids"); ids != "doc2,doc1,secret" {
+				t.Errorf("Expected one request for all documents, got ids=%s", ids)
+			}
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadsResponse{
+				"doc1": {Thread: quip.Document{ID: "doc1", Title: "Roadmap", Type: "document", Link: "https://quip.com/doc1", Updated: 1700000000000000}},
+				"doc2": {Thread: quip.Document{ID: "doc2", Title: "Budget", Type: "spreadsheet", Link: "https://quip.com/doc2"}},
+			})
+		case "/folders/":
+			_ = json.NewEncoder(w).Encode(map[string]quip.FolderData{
+				"fold2": {Folder: quip.Folder{ID: "fold2", Title: "Archive"}},
+			})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_folder_documents", map[string]any{"folder_id": "https://quip.com/fold1/Projects"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	for _, want := range []string{
+		"**Projects** — ID: fold1, 3 documents, 1 subfolder",
+		"**Subfolders:**\n- **Archive** — ID: fold2, empty",
+		"- **Budget** (spreadsheet) — ID: doc2",
+		"- **Roadmap** (document) — ID: doc1, updated ",
+		"  https://quip.com/doc1",
+		"- secret (not accessible)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected result to contain %q, got %q", want, text)
+		}
+	}
+	if strings.Index(text, "Budget") > strings.Index(text, "Roadmap") {
+		t.Errorf("Expected documents in folder order, got %q", text)
+	}
+}
+
+func TestServer_MoveDocument(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc1":
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc1", SharedFolderID: "inbox"}})
+		case "/threads/add-members":
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{SharedFolderIds: []string{"inbox", "fold2"}})
+		case "/threads/remove-members":
+			if r.FormValue("member_ids") != "inbox" {
+				t.Errorf("Expected removal from inbox, got %q", r.FormValue("member_ids"))
+			}
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{SharedFolderIds: []string{"fold2"}})
+		case "/folders/":
+			_ = json.NewEncoder(w).Encode(map[string]quip.FolderData{
+				"fold2": {Folder: quip.Folder{ID: "fold2", Title: "Archive"}},
+			})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "move_document", map[string]any{"document_id": "doc1", "target_folder_id": "https://quip.com/fold2", "remove_from_current": true})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(text, "**Now in:**\n  - Archive (fold2)\n") || strings.Contains(text, "inbox") {
+		t.Errorf("Expected the document to be only in Archive, got %q", text)
+	}
+
+	// By default the document stays in its current folder as well
+	text, _ = callTool(t, s, "move_document", map[string]any{"document_id": "doc1", "target_folder_id": "fold2"})
+	if !strings.Contains(text, "  - inbox\n") || !strings.Contains(text, "Archive (fold2)") {
+		t.Errorf("Expected the document to stay in inbox too, got %q", text)
+	}
+}
+
+func TestServer_ListUserFolders(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/current":
+			_ = json.NewEncoder(w).Encode(quip.User{ID: "me", Name: "Me", PrivateFolderID: "priv", SharedFolderIDs: []string{"shared1", "shared2"}})
+		case "/folders/":
+			_ = json.NewEncoder(w).Encode(map[string]quip.FolderData{
+				"priv":    {Folder: quip.Folder{ID: "priv", Title: "Private", CreatorID: "me"}, MemberIDs: []string{"me"}},
+				"shared1": {Folder: quip.Folder{ID: "shared1", Title: "Design"}, MemberIDs: []string{"me", "ann"}},
+				"shared2": {Folder: quip.Folder{ID: "shared2", Title: "Budget"}, MemberIDs: []string{"me", "ann", "bob"}},
+			})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "list_user_folders", map[string]any{"user_id": "current", "limit": 2})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	for _, want := range []string{
+		"Me can access 3 folders (showing 1-2):",
+		"- **Private** — ID: priv, empty — owner, 1 member",
+		"- **Design** — ID: shared1, empty — member, 2 members",
+		"Pass offset 2 to see more.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected result to contain %q, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "Budget") || strings.Contains(text, "folders they don't share with you") {
+		t.Errorf("Expected only the first page without the other-user note, got %q", text)
+	}
+
+	text, _ = callTool(t, s, "list_user_folders", map[string]any{"user_id": "current", "limit": 2, "offset": 2})
+	if !strings.Contains(text, "(showing 3-3)") || !strings.Contains(text, "**Budget**") || strings.Contains(text, "Pass offset") {
+		t.Errorf("Expected the last page, got %q", text)
+	}
+
+	text, isError = callTool(t, s, "list_user_folders", map[string]any{"user_id": "current", "offset": -1})
+	if !isError || !strings.Contains(text, "Invalid offset argument") {
+		t.Errorf("Expected an offset error, got %q", text)
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerFollowTools registers the follow/unfollow document tools
+func (s *Server) registerFollowTools() {
+	// Follow document tool
+	followTool := mcp.NewTool(
+		"follow_document",
+		mcp.WithDescription("Follow a Quip document to receive notifications about its changes"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to follow")),
+	)
+
+	s.addTool(followTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		doc, err := s.client(ctx).FollowThread(documentID)
+		if err != nil {
+			return toolError("follow document", err), nil
+		}
+		if !doc.UserIsFollowing {
+			return unchangedFollowError(doc), nil
+		}
+
+		return mcp.NewToolResultText(formatFollowResult("🔔 **Now following document**", doc)), nil
+	})
+
+	// Unfollow document tool
+	unfollowTool := mcp.NewTool(
+		"unfollow_document",
+		mcp.WithDescription("Stop following a Quip document"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to unfollow")),
+	)
+
+	s.addTool(unfollowTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		doc, err := s.client(ctx).UnfollowThread(documentID)
+		if err != nil {
+			return toolError("unfollow document", err), nil
+		}
+		if doc.UserIsFollowing {
+			return unchangedFollowError(doc), nil
+		}
+
+		return mcp.NewToolResultText(formatFollowResult("🔕 **No longer following document**", doc)), nil
+	})
+}
+
+// unchangedFollowError reports a follow/unfollow request that Quip accepted without changing
+// the thread's following state
+func unchangedFollowError(doc *quip.Document) *mcp.CallToolResult {
+	return mcp.NewToolResultError(fmt.Sprintf("Quip accepted the request but still reports following as %t; the following state was not changed.", doc.UserIsFollowing))
+}
+
+// formatFollowResult renders the outcome of a follow/unfollow call
+func formatFollowResult(heading string, doc *quip.Document) string {
+	response := heading + "\n\n"
+	if doc.Title != "" {
+		response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
+	}
+	response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+	response += fmt.Sprintf("- **Following:** %t\n", doc.UserIsFollowing)
+	return response
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_FollowDocument_Unchanged(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Quip accepts the request, but the thread still isn't followed
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Plan"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "follow_document", map[string]any{"document_id": "doc123"})
+	if !isError || !strings.Contains(text, "not changed") {
+		t.Errorf("Expected an error for an unchanged following state, got %q", text)
+	}
+
+	text, isError = callTool(t, s, "unfollow_document", map[string]any{"document_id": "doc123"})
+	if isError || !strings.Contains(text, "- **Following:** false") {
+		t.Errorf("Expected unfollowing to succeed, got %q", text)
+	}
+}
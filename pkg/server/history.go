@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerHistoryTools registers the document edit history tool
+func (s *Server) registerHistoryTools() {
+	// Get document history tool
+	historyTool := mcp.NewTool(
+		"get_document_history",
+		mcp.WithDescription("Get the edit history of a Quip document: when it changed and who changed it"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of versions to return (default: 20)"), mcp.Min(1), mcp.Max(100)),
+		mcp.WithString("cursor", mcp.Description("Cursor from a previous call to fetch older versions")),
+	)
+
+	s.addTool(historyTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		opts := quip.HistoryOptions{
+			Limit:  req.GetInt("limit", 20),
+			Cursor: req.GetString("cursor", ""),
+		}
+
+		client := s.client(ctx)
+		page, err := client.GetDocumentVersions(documentID, opts)
+		if err != nil {
+			return toolError("get document history", err), nil
+		}
+
+		if len(page.Versions) == 0 {
+			return mcp.NewToolResultText("No edit history found for this document."), nil
+		}
+
+		// Resolve editor IDs to names; fall back to the raw IDs if the lookup fails
+		authorIDs := make([]string, 0, len(page.Versions))
+		for _, version := range page.Versions {
+			authorIDs = append(authorIDs, version.AuthorID)
+		}
+		users, err := client.GetUsers(authorIDs)
+		if err != nil {
+			users = nil
+		}
+
+		response := fmt.Sprintf("Found %d versions:\n\n", len(page.Versions))
+		for i, version := range page.Versions {
+			editor := version.AuthorID
+			if user, ok := users[version.AuthorID]; ok && user.Name != "" {
+				editor = fmt.Sprintf("%s (%s)", user.Name, version.AuthorID)
+			}
+
+			response += fmt.Sprintf("%d. **%s**\n", i+1, s.formatTimestamp(version.Created))
+			response += fmt.Sprintf("   - Editor: %s\n", editor)
+			if version.ID != "" {
+				response += fmt.Sprintf("   - Version: %s\n", version.ID)
+			}
+			if len(version.SectionIDs) > 0 {
+				response += fmt.Sprintf("   - Sections changed: %d\n", len(version.SectionIDs))
+			}
+			response += "\n"
+		}
+
+		if page.NextCursor != "" {
+			response += fmt.Sprintf("More versions available. Use cursor=%s to fetch older versions.\n", page.NextCursor)
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
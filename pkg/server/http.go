@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultReadinessTTL is how long a successful or failed token check is reused by /readyz
+const DefaultReadinessTTL = time.Minute
+
+// readinessCache remembers the outcome of the last token check made by /readyz
+type readinessCache struct {
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// WithReadinessTTL sets how long /readyz reuses its last token check. A TTL of zero or
+// less disables the check, so /readyz only reports whether the server is accepting calls.
+func WithReadinessTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.readinessTTL = ttl
+	}
+}
+
+// StartHTTP serves MCP over streamable HTTP at /mcp on addr, alongside /healthz and /readyz
+// probes. Like Start, it blocks until Stop is called or the process receives SIGINT/SIGTERM.
+func (s *Server) StartHTTP(addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := s.verifyToken(ctx); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s.logger.Info("Starting MCP Quip Server", "transport", "http", "addr", ln.Addr().String())
+	return s.serveHTTP(ctx, ln)
+}
+
+// serveHTTP serves on ln until ctx is cancelled, then shuts down and drains in-flight calls
+func (s *Server) serveHTTP(ctx context.Context, ln net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	s.cancel = cancel
+	s.serveCtx = ctx
+	s.mu.Unlock()
+
+	httpServer := &http.Server{
+		Handler:           s.HTTPHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(ln)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancelShutdown()
+	if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil {
+		s.logger.Warn("HTTP shutdown did not complete cleanly", "error", shutdownErr)
+	}
+
+	if !s.drain(ShutdownTimeout) {
+		s.logger.Warn("Shutdown timed out with tool calls still running", "timeout", ShutdownTimeout)
+	}
+	s.logger.Info("MCP Quip Server stopped")
+
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return err
+}
+
+// HTTPHandler returns the handler for the HTTP transport: the MCP endpoint at /mcp plus
+// /healthz (liveness) and /readyz (readiness) probes
+func (s *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", server.NewStreamableHTTPServer(s.mcpServer))
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	return mux
+}
+
+// handleHealthz reports that the process is up and serving
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, http.StatusOK, "ok")
+}
+
+// handleReadyz reports whether the server accepts tool calls and its token still works
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	draining := s.draining
+	s.mu.Unlock()
+
+	if draining {
+		writeProbe(w, http.StatusServiceUnavailable, "shutting down")
+		return
+	}
+
+	if err := s.checkReadiness(r.Context()); err != nil {
+		writeProbe(w, http.StatusServiceUnavailable, fmt.Sprintf("token check failed: %v", err))
+		return
+	}
+
+	writeProbe(w, http.StatusOK, "ready")
+}
+
+// checkReadiness verifies the token with GetCurrentUser, reusing the last result for readinessTTL
+func (s *Server) checkReadiness(ctx context.Context) error {
+	if s.readinessTTL <= 0 {
+		return nil
+	}
+
+	s.readiness.mu.Lock()
+	defer s.readiness.mu.Unlock()
+
+	if !s.readiness.checkedAt.IsZero() && time.Since(s.readiness.checkedAt) < s.readinessTTL {
+		return s.readiness.err
+	}
+
+	_, err := s.client(ctx).GetCurrentUser()
+	s.readiness.checkedAt = time.Now()
+	s.readiness.err = err
+	return err
+}
+
+// writeProbe writes a plain-text probe response
+func writeProbe(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintln(w, message)
+}
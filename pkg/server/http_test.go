@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+// newFakeQuipAPI serves /users/current with the given status, counting requests
+func newFakeQuipAPI(t *testing.T, status *int32, calls *int32) *httptest.Server {
+	t.Helper()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if code := int(atomic.LoadInt32(status)); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.User{ID: "user123"})
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func TestServer_Healthz(t *testing.T) {
+	server := New("test-token")
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Readyz(t *testing.T) {
+	status := int32(http.StatusOK)
+	var calls int32
+	api := newFakeQuipAPI(t, &status, &calls)
+
+	server := New("test-token",
+		WithClientOptions(quip.WithBaseURL(api.URL)),
+		WithReadinessTTL(50*time.Millisecond),
+	)
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	get := func() int {
+		resp, err := http.Get(ts.URL + "/readyz")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get(); code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", code)
+	}
+	if code := get(); code != http.StatusOK {
+		t.Errorf("Expected cached status 200, got %d", code)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 token check within the TTL, got %d", n)
+	}
+
+	// Once the cached result is stale, a revoked token makes the server unready
+	atomic.StoreInt32(&status, http.StatusUnauthorized)
+	time.Sleep(60 * time.Millisecond)
+
+	if code := get(); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", code)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected a second token check after the TTL, got %d", n)
+	}
+}
+
+func TestServer_ReadyzWithoutTokenCheck(t *testing.T) {
+	status := int32(http.StatusUnauthorized)
+	var calls int32
+	api := newFakeQuipAPI(t, &status, &calls)
+
+	server := New("test-token", WithClientOptions(quip.WithBaseURL(api.URL)), WithReadinessTTL(0))
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("Expected 200 without contacting Quip, got %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestServer_ServeHTTPStop(t *testing.T) {
+	server := New("test-token", WithReadinessTTL(0))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.serveHTTP(context.Background(), ln)
+	}()
+
+	url := "http://" + ln.Addr().String() + "/healthz"
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Server did not come up: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	server.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected clean shutdown, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected serveHTTP to return after Stop")
+	}
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerLockTools registers the edit locking tool
+func (s *Server) registerLockTools() {
+	// Lock document tool
+	lockTool := mcp.NewTool(
+		"lock_document",
+		mcp.WithDescription("Lock a Quip document so others can't edit it during a multi-step change, or unlock it again. Remember to unlock when done."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to lock or unlock")),
+		mcp.WithBoolean("locked", mcp.Description("true to lock editing (default), false to unlock")),
+	)
+
+	s.addTool(lockTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		locked := req.GetBool("locked", true)
+
+		doc, err := s.client(ctx).LockEdits(documentID, locked)
+		if err != nil {
+			return toolError("update document lock", err), nil
+		}
+		if doc.EditsDisabled != locked {
+			return mcp.NewToolResultError(fmt.Sprintf("Quip accepted the request but the document's edits disabled flag is still %t; the lock was not changed.", doc.EditsDisabled)), nil
+		}
+
+		response := "🔓 **Document unlocked**\n\n"
+		if doc.EditsDisabled {
+			response = "🔒 **Document locked for editing**\n\n"
+		}
+		if doc.Title != "" {
+			response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
+		}
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Edits disabled:** %t\n", doc.EditsDisabled)
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
@@ -0,0 +1,372 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// Quip list styles, set through the data-section-style attribute on <ul>/<ol>
+const (
+	quipBulletedList = "5"
+	quipNumberedList = "6"
+	quipChecklist    = "7"
+)
+
+// Link styles for MarkdownOptions.Links
+const (
+	markdownLinksInline     = "inline"
+	markdownLinksReferenced = "referenced"
+	markdownLinksText       = "text"
+)
+
+// MarkdownOptions customizes the markdown documents are rendered as. Zero values keep the
+// defaults: ATX headings, "-" bullets and inline links.
+type MarkdownOptions struct {
+	// HeadingStyle is "atx" (# Heading) or "setext" (underlined, for levels 1 and 2)
+	HeadingStyle string
+	// BulletMarker is the bullet list marker: "-", "*" or "+"
+	BulletMarker string
+	// Links is "inline" ([text](url)), "referenced" ([text][1] with the URLs listed at the
+	// end) or "text" (link text only, URLs dropped)
+	Links string
+}
+
+// WithMarkdownOptions sets how documents are rendered as markdown
+func WithMarkdownOptions(opts MarkdownOptions) Option {
+	return func(s *Server) {
+		s.markdownOptions = opts
+	}
+}
+
+// markdownRendererVersion is part of every markdown cache key; bump it whenever a change to the
+// converter alters its output, so renderings cached by an older build are not served.
+const markdownRendererVersion = 1
+
+// markdownCacheTTL bounds how long a rendering is kept. Keys already change with every edit;
+// the TTL only lets entries for documents that are no longer read age out of a disk cache.
+const markdownCacheTTL = 7 * 24 * time.Hour
+
+// WithMarkdownCache keeps converted documents in cache so unchanged documents aren't converted
+// again. Entries are keyed by renderer version, token and base URL, thread ID, updated_usec and
+// the markdown options, so an edit, an option change or an upgrade simply misses, and accounts
+// sharing a store never see each other's documents. Pair it with a quip.DiskCache to keep them
+// across restarts.
+func WithMarkdownCache(cache quip.Cache) Option {
+	return func(s *Server) {
+		s.markdownCache = cache
+	}
+}
+
+// Markdown conversion modes for WithMarkdownConversion
+const (
+	MarkdownConversionClient = "client"
+	MarkdownConversionServer = "server"
+)
+
+// WithMarkdownConversion selects where markdown given to create_document, edit_document and
+// the append/prepend tools is rendered. MarkdownConversionClient (the default) converts it to
+// Quip HTML here and sends that, which renders task lists as checklists; MarkdownConversionServer
+// sends the markdown as is and leaves the rendering to Quip.
+func WithMarkdownConversion(mode string) Option {
+	return func(s *Server) {
+		s.markdownConversion = mode
+	}
+}
+
+// documentContent prepares content for creating or editing a document according to the
+// server's markdown conversion mode, returning the content and the format to send it in
+func (s *Server) documentContent(content, format string) (string, string, error) {
+	if s.markdownConversion == MarkdownConversionServer && (format == "" || format == "markdown") {
+		return content, "markdown", nil
+	}
+	return quipContent(content, format)
+}
+
+// toMarkdown converts HTML content to markdown using the server's markdown options
+func (s *Server) toMarkdown(htmlContent string) string {
+	return htmlToMarkdown(htmlContent, s.markdownOptions, s.threadHosts...)
+}
+
+// documentMarkdown converts a document's content to markdown, going through the markdown cache
+// when one is configured and the document carries an update timestamp
+func (s *Server) documentMarkdown(doc *quip.Document) string {
+	if s.markdownCache == nil || doc.ID == "" || doc.Updated == 0 {
+		return s.renderMarkdown(doc)
+	}
+
+	key := s.markdownCacheKey(doc)
+	if cached, ok := s.markdownCache.Get(key); ok {
+		return string(cached)
+	}
+
+	markdown := s.renderMarkdown(doc)
+	s.markdownCache.Set(key, []byte(markdown), markdownCacheTTL)
+	return markdown
+}
+
+// renderMarkdown converts a document's content to markdown. Spreadsheets are rendered sheet by
+// sheet as markdown tables; the generic converter mangles their row-numbered table HTML.
+func (s *Server) renderMarkdown(doc *quip.Document) string {
+	if strings.EqualFold(doc.Type, "spreadsheet") {
+		if markdown, ok := spreadsheetMarkdown(doc); ok {
+			return markdown
+		}
+	}
+	return s.toMarkdown(doc.HTML)
+}
+
+// markdownCacheKey identifies one rendering of one revision of a document. The thread type is
+// part of the key because it decides how the content is rendered.
+func (s *Server) markdownCacheKey(doc *quip.Document) string {
+	opts := s.markdownOptions
+	return fmt.Sprintf("markdown:v%d:%s:%s:%d:%s:%s|%s|%s", markdownRendererVersion, s.cacheScope, doc.ID, doc.Updated, strings.ToLower(doc.Type), opts.HeadingStyle, opts.BulletMarker, opts.Links)
+}
+
+// htmlToMarkdown converts HTML content to clean markdown.
+// The converter decodes HTML entities exactly once, so the output must not be unescaped again:
+// a document that literally contains "&amp;" has to keep it.
+func htmlToMarkdown(htmlContent string, opts MarkdownOptions, hosts ...string) string {
+	converter := md.NewConverter("", true, &md.Options{
+		HeadingStyle:     opts.HeadingStyle,
+		BulletListMarker: opts.BulletMarker,
+		LinkStyle:        converterLinkStyle(opts.Links),
+	})
+	converter.Before(func(selec *goquery.Selection) { labelThreadLinks(selec, hosts) })
+	converter.AddRules(quipMarkdownRules(opts)...)
+
+	markdown, err := converter.ConvertString(htmlContent)
+	if err != nil {
+		// If conversion fails, return a cleaned version of the HTML
+		cleaned := strings.ReplaceAll(htmlContent, "<", "&lt;")
+		cleaned = strings.ReplaceAll(cleaned, ">", "&gt;")
+		return cleaned
+	}
+
+	// Non-breaking spaces survive conversion as U+00A0; render them as plain spaces
+	markdown = strings.ReplaceAll(markdown, "\u00a0", " ")
+
+	return collapseBlankLines(markdown)
+}
+
+// converterLinkStyle maps MarkdownOptions.Links to the converter's link style
+func converterLinkStyle(links string) string {
+	if links == markdownLinksReferenced {
+		return "referenced"
+	}
+	return "inlined"
+}
+
+// labelThreadLinks gives links to other Quip documents a readable label. Quip usually labels
+// them with the title already; bare links fall back to the title Quip stores on the element,
+// or the thread ID.
+func labelThreadLinks(selec *goquery.Selection, hosts []string) {
+	selec.Find("a[href]").Each(func(_ int, link *goquery.Selection) {
+		if isQuipMention(link) {
+			return
+		}
+		href := strings.TrimSpace(link.AttrOr("href", ""))
+		id, err := quip.ParseThreadURL(href, hosts...)
+		if err != nil {
+			return
+		}
+		if label := strings.TrimSpace(link.Text()); label != "" && label != href {
+			return
+		}
+
+		label := id
+		for _, attr := range []string{"data-title", "title"} {
+			if title := strings.TrimSpace(link.AttrOr(attr, "")); title != "" {
+				label = title
+				break
+			}
+		}
+		// The title is now the label; don't repeat it as the link's tooltip
+		link.RemoveAttr("title")
+		link.SetText(label)
+	})
+}
+
+// quipMarkdownRules keep the meaning of Quip-specific markup that the generic converter would
+// flatten: @mentions and highlighted text, plus dropping URLs when opts asks for link text only.
+// The converter tries rules added later first, so mentions win over links; returning nil from
+// a replacement falls back to the next rule for the element.
+func quipMarkdownRules(opts MarkdownOptions) []md.Rule {
+	rules := []md.Rule{
+		{
+			Filter: []string{"mark", "span"},
+			Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+				// <span> has no default rule to fall back to, so ordinary spans keep their content
+				if goquery.NodeName(selec) == "span" && !isQuipHighlight(selec) {
+					return md.String(content)
+				}
+				trimmed := strings.TrimSpace(content)
+				if trimmed == "" {
+					return md.String(content)
+				}
+				return md.String(opt.EmDelimiter + trimmed + opt.EmDelimiter)
+			},
+		},
+	}
+
+	if opts.Links == markdownLinksText {
+		rules = append(rules, md.Rule{
+			Filter: []string{"a"},
+			Replacement: func(content string, _ *goquery.Selection, _ *md.Options) *string {
+				return md.String(content)
+			},
+		})
+	}
+
+	return append(rules, md.Rule{
+		Filter: []string{"a", "span"},
+		Replacement: func(_ string, selec *goquery.Selection, _ *md.Options) *string {
+			if !isQuipMention(selec) {
+				return nil
+			}
+			name := strings.TrimPrefix(strings.TrimSpace(selec.Text()), "@")
+			if name == "" {
+				return nil
+			}
+			return md.String("@" + name)
+		},
+	})
+}
+
+// isQuipMention reports whether selec is an @mention of a person
+func isQuipMention(selec *goquery.Selection) bool {
+	if _, ok := selec.Attr("data-mention-id"); ok {
+		return true
+	}
+	return hasClassContaining(selec, "mention")
+}
+
+// isQuipHighlight reports whether the span selec marks highlighted text, either by class or
+// by a background color
+func isQuipHighlight(selec *goquery.Selection) bool {
+	if hasClassContaining(selec, "highlight") {
+		return true
+	}
+	style := strings.ToLower(selec.AttrOr("style", ""))
+	return strings.Contains(style, "background-color") || strings.Contains(style, "background:")
+}
+
+// hasClassContaining reports whether any of selec's classes contains substr
+func hasClassContaining(selec *goquery.Selection, substr string) bool {
+	for _, class := range strings.Fields(selec.AttrOr("class", "")) {
+		if strings.Contains(strings.ToLower(class), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseBlankLines trims trailing whitespace and reduces runs of blank lines to a single
+// paragraph break, leaving indentation and the contents of fenced code blocks untouched
+func collapseBlankLines(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	cleanLines := make([]string, 0, len(lines))
+	inFence := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, " \t")
+
+		if strings.HasPrefix(strings.TrimSpace(trimmed), "```") {
+			inFence = !inFence
+		}
+
+		if trimmed == "" && !inFence {
+			// Skip leading blank lines and any blank line that follows another
+			if len(cleanLines) == 0 || cleanLines[len(cleanLines)-1] == "" {
+				continue
+			}
+		}
+
+		cleanLines = append(cleanLines, trimmed)
+	}
+
+	// Drop a trailing paragraph break
+	for len(cleanLines) > 0 && cleanLines[len(cleanLines)-1] == "" {
+		cleanLines = cleanLines[:len(cleanLines)-1]
+	}
+
+	return strings.Join(cleanLines, "\n")
+}
+
+// markdownToQuipHTML converts markdown (including GitHub-flavored tables and task lists)
+// into HTML that Quip renders natively. It is the inverse of htmlToMarkdown.
+func markdownToQuipHTML(markdown string) (string, error) {
+	var buf bytes.Buffer
+	// Inline HTML is passed through, as Quip's own markdown import does, rather than dropped
+	converter := goldmark.New(goldmark.WithExtensions(extension.GFM), goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()))
+	if err := converter.Convert([]byte(markdown), &buf); err != nil {
+		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	}
+
+	dom, err := goquery.NewDocumentFromReader(&buf)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse generated HTML: %w", err)
+	}
+
+	// Quip doesn't understand checkbox inputs; it marks checklists with a list style
+	// and completed items with the "checked" class
+	dom.Find("ul").Each(func(_ int, list *goquery.Selection) {
+		checkboxes := list.ChildrenFiltered("li").ChildrenFiltered("input[type=checkbox]")
+		if checkboxes.Length() == 0 {
+			list.SetAttr("data-section-style", quipBulletedList)
+			return
+		}
+
+		list.SetAttr("data-section-style", quipChecklist)
+		checkboxes.Each(func(_ int, input *goquery.Selection) {
+			if _, checked := input.Attr("checked"); checked {
+				input.Parent().AddClass("checked")
+			}
+			input.Remove()
+		})
+	})
+	dom.Find("ol").SetAttr("data-section-style", quipNumberedList)
+
+	// Strip the space the checkbox left at the start of each item
+	dom.Find("ul[data-section-style='" + quipChecklist + "'] > li").Each(func(_ int, item *goquery.Selection) {
+		// Edit the text node in place: its text is already decoded, so parsing it again as
+		// HTML would turn escaped markup like "&lt;img&gt;" into live elements
+		if first := item.Contents().First(); goquery.NodeName(first) == "#text" {
+			node := first.Get(0)
+			node.Data = strings.TrimLeft(node.Data, " ")
+		}
+	})
+
+	html, err := dom.Find("body").Html()
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML: %w", err)
+	}
+
+	return strings.TrimSpace(html), nil
+}
+
+// quipContent prepares content given to a tool in format ("markdown" or "html") for
+// sending to Quip. Quip's own markdown import renders task lists as literal "[ ]" text,
+// so markdown is converted to Quip HTML first; HTML is passed through unchanged.
+func quipContent(content, format string) (string, string, error) {
+	switch format {
+	case "", "markdown":
+		html, err := markdownToQuipHTML(content)
+		if err != nil {
+			return "", "", err
+		}
+		return html, "html", nil
+	case "html":
+		return content, "html", nil
+	default:
+		return "", "", fmt.Errorf("unsupported format %q: must be markdown or html", format)
+	}
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "ampersand decoded once",
+			html:     "<p>Tom &amp; Jerry</p>",
+			expected: "Tom & Jerry",
+		},
+		{
+			name:     "literal entity text preserved",
+			html:     "<p>Write &amp;amp; to escape an ampersand</p>",
+			expected: "Write &amp; to escape an ampersand",
+		},
+		{
+			name:     "escaped markup in code block",
+			html:     "<pre><code>if a &lt; b &amp;&amp; c {\n\n    return &quot;&amp;lt;&quot;\n}</code></pre>",
+			expected: "```\nif a < b && c {\n\n    return \"&lt;\"\n}\n```",
+		},
+		{
+			name:     "ampersand in URL",
+			html:     `<p><a href="https://example.com/?a=1&amp;b=2">link</a></p>`,
+			expected: "[link](https://example.com/?a=1&b=2)",
+		},
+		{
+			name:     "non-breaking spaces",
+			html:     "<p>a&nbsp;b&nbsp;&nbsp;c</p>",
+			expected: "a b  c",
+		},
+		{
+			name:     "paragraph breaks preserved",
+			html:     "<p>one</p><p>two</p><p></p><p></p><p>three</p>",
+			expected: "one\n\ntwo\n\nthree",
+		},
+		{
+			name:     "mention",
+			html:     `<p>Thanks <a href="https://quip.com/KaXAEAiKo0l" class="user-mention">@Kevin Gibbs</a>!</p>`,
+			expected: "Thanks @Kevin Gibbs!",
+		},
+		{
+			name:     "mention span",
+			html:     `<p>cc <span data-mention-id="KaXAEAiKo0l">Ana</span></p>`,
+			expected: "cc @Ana",
+		},
+		{
+			name:     "internal link with title",
+			html:     `<p>See <a href="https://quip.com/AbCdEfGhIjK/Roadmap">Roadmap 2024</a></p>`,
+			expected: "See [Roadmap 2024](https://quip.com/AbCdEfGhIjK/Roadmap)",
+		},
+		{
+			name:     "bare internal link",
+			html:     `<p>See <a href="https://quip.com/AbCdEfGhIjK" title="Roadmap 2024">https://quip.com/AbCdEfGhIjK</a></p>`,
+			expected: "See [Roadmap 2024](https://quip.com/AbCdEfGhIjK)",
+		},
+		{
+			name:     "bare internal link without title",
+			html:     `<p><a href="https://quip.com/AbCdEfGhIjK"></a></p>`,
+			expected: "[AbCdEfGhIjK](https://quip.com/AbCdEfGhIjK)",
+		},
+		{
+			name:     "highlight",
+			html:     `<p>This is <span class="highlight-yellow">important</span> and <mark>this</mark> too</p>`,
+			expected: "This is _important_ and _this_ too",
+		},
+		{
+			name:     "plain span",
+			html:     `<p>This is <span class="note">plain</span></p>`,
+			expected: "This is plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := htmlToMarkdown(tt.html, MarkdownOptions{})
+			if result != tt.expected {
+				t.Errorf("htmlToMarkdown(%q) = %q, expected %q", tt.html, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollapseBlankLines(t *testing.T) {
+	input := "\n\n# Title\n\n\n\nBody   \n- item\n    - nested\n\n```\ncode\n\n\nmore\n```\n\n\n"
+	expected := "# Title\n\nBody\n- item\n    - nested\n\n```\ncode\n\n\nmore\n```"
+
+	result := collapseBlankLines(input)
+	if result != expected {
+		t.Errorf("collapseBlankLines() = %q, expected %q", result, expected)
+	}
+}
+
+func TestMarkdownToQuipHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "heading and paragraph",
+			markdown: "# Plan\n\nShip it & celebrate",
+			contains: []string{"<h1>Plan</h1>", "<p>Ship it &amp; celebrate</p>"},
+		},
+		{
+			name:     "bulleted and numbered lists",
+			markdown: "- one\n- two\n\n1. first\n2. second",
+			contains: []string{`<ul data-section-style="5">`, `<ol data-section-style="6">`},
+		},
+		{
+			name:     "checklist",
+			markdown: "- [x] done\n- [ ] todo",
+			contains: []string{`<ul data-section-style="7">`, `<li class="checked">done</li>`, `<li>todo</li>`},
+			excludes: []string{"<input", "type=\"checkbox\""},
+		},
+		{
+			name:     "escaped markup in a checklist item stays text",
+			markdown: "- [ ] a &lt;img src=x onerror=alert(1)&gt; b",
+			contains: []string{"<li>a &lt;img src=x onerror=alert(1)&gt; b</li>"},
+			excludes: []string{"<img", "onerror=\""},
+		},
+		{
+			name:     "inline HTML",
+			markdown: "Hello <u>under</u>",
+			contains: []string{"<p>Hello <u>under</u></p>"},
+			excludes: []string{"raw HTML omitted"},
+		},
+		{
+			name:     "table",
+			markdown: "| A | B |\n|---|---|\n| 1 | 2 |",
+			contains: []string{"<table>", "<th>A</th>", "<td>2</td>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := markdownToQuipHTML(tt.markdown)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(result, want) {
+					t.Errorf("Expected HTML to contain %q, got %q", want, result)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("Expected HTML not to contain %q, got %q", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestQuipContent(t *testing.T) {
+	content, format, err := quipContent("# Tasks\n\n- [ ] write tests\n- [x] ship\n\n| A | B |\n|---|---|\n| 1 | 2 |", "markdown")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if format != "html" {
+		t.Errorf("Expected markdown to be sent as html, got %s", format)
+	}
+	for _, want := range []string{"<h1>Tasks</h1>", `<ul data-section-style="7">`, `<li class="checked">ship</li>`, "<td>2</td>"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected content to contain %q, got %q", want, content)
+		}
+	}
+	if strings.Contains(content, "[ ]") {
+		t.Errorf("Expected task list syntax to be converted, got %q", content)
+	}
+
+	if content, format, err := quipContent("<p>as-is</p>", "html"); err != nil || content != "<p>as-is</p>" || format != "html" {
+		t.Errorf("Expected HTML to pass through unchanged, got %q (%s, %v)", content, format, err)
+	}
+
+	if _, _, err := quipContent("text", "rtf"); err == nil {
+		t.Error("Expected error for unsupported format, got nil")
+	}
+}
+
+func TestHTMLToMarkdown_Options(t *testing.T) {
+	html := `<h1>Plan</h1><ul><li>Ship <a href="https://example.com/spec">the spec</a></li><li>Review <a href="https://quip.com/AbCdEfGhIjK"></a></li></ul>`
+
+	tests := []struct {
+		name     string
+		opts     MarkdownOptions
+		expected string
+	}{
+		{
+			name:     "defaults",
+			expected: "# Plan\n\n- Ship [the spec](https://example.com/spec)\n- Review [AbCdEfGhIjK](https://quip.com/AbCdEfGhIjK)",
+		},
+		{
+			name:     "setext headings and star bullets",
+			opts:     MarkdownOptions{HeadingStyle: "setext", BulletMarker: "*"},
+			expected: "Plan\n====\n\n* Ship [the spec](https://example.com/spec)\n* Review [AbCdEfGhIjK](https://quip.com/AbCdEfGhIjK)",
+		},
+		{
+			name:     "link text only",
+			opts:     MarkdownOptions{Links: "text"},
+			expected: "# Plan\n\n- Ship the spec\n- Review AbCdEfGhIjK",
+		},
+		{
+			name:     "referenced links",
+			opts:     MarkdownOptions{Links: "referenced", BulletMarker: "+"},
+			expected: "# Plan\n\n+ Ship [the spec][1]\n+ Review [AbCdEfGhIjK][2]\n\n[1]: https://example.com/spec\n[2]: https://quip.com/AbCdEfGhIjK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToMarkdown(html, tt.opts); got != tt.expected {
+				t.Errorf("htmlToMarkdown with %+v = %q, expected %q", tt.opts, got, tt.expected)
+			}
+		})
+	}
+
+	// Mentions stay mentions even when links are reduced to text
+	mention := `<p>cc <a href="https://quip.com/KaXAEAiKo0l" class="user-mention">Ana</a></p>`
+	if got := htmlToMarkdown(mention, MarkdownOptions{Links: "text"}); got != "cc @Ana" {
+		t.Errorf("Expected the mention to be kept, got %q", got)
+	}
+}
+
+func TestServer_MarkdownCache(t *testing.T) {
+	cache, err := quip.NewDiskCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+	s := New("test-token", WithMarkdownCache(cache))
+
+	doc := &quip.Document{ID: "doc123", Updated: 1640995200000000, HTML: "<h1>First</h1>"}
+	if got := s.documentMarkdown(doc); got != "# First" {
+		t.Fatalf("Expected the converted document, got %q", got)
+	}
+
+	// Same revision: the cached markdown is returned without converting the HTML again
+	doc.HTML = "<h1>Not converted</h1>"
+	if got := s.documentMarkdown(doc); got != "# First" {
+		t.Errorf("Expected a cache hit for an unchanged updated_usec, got %q", got)
+	}
+
+	// A new updated_usec misses and converts the current content
+	doc.Updated++
+	if got := s.documentMarkdown(doc); got != "# Not converted" {
+		t.Errorf("Expected a cache miss after the document changed, got %q", got)
+	}
+
+	// Different markdown options render separately
+	other := New("test-token", WithMarkdownCache(cache), WithMarkdownOptions(MarkdownOptions{HeadingStyle: "setext"}))
+	if got := other.documentMarkdown(doc); got != "Not converted\n=============" {
+		t.Errorf("Expected the other options to miss the cache, got %q", got)
+	}
+
+	// Another token sharing the store never sees this account's renderings
+	otherAccount := New("other-token", WithMarkdownCache(cache))
+	doc.HTML = "<h1>Theirs</h1>"
+	if got := otherAccount.documentMarkdown(doc); got != "# Theirs" {
+		t.Errorf("Expected another token to miss the cache, got %q", got)
+	}
+}
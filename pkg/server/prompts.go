@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxPromptDocumentChars bounds the document content embedded in a prompt, so a huge document
+// doesn't crowd out the instructions
+const maxPromptDocumentChars = 100000
+
+// actionItemsInstructions is the request extract_action_items puts before the document
+const actionItemsInstructions = `Review the Quip document below and extract its action items.
+
+For each action item, give:
+- **Task:** what needs to be done
+- **Owner:** who is responsible, by name (the people the document mentions are listed below), or "Unassigned"
+- **Due date:** the date or deadline as the document states it, or "None"
+
+Only list tasks the document states or clearly assigns; don't invent owners or dates. Unchecked checklist items are open tasks. List checked items separately as completed.`
+
+// registerPrompts registers the prompt templates
+func (s *Server) registerPrompts() {
+	// Extract action items prompt
+	actionItemsPrompt := mcp.NewPrompt(
+		"extract_action_items",
+		mcp.WithPromptDescription("Review a Quip document and list its action items with owners and due dates"),
+		mcp.WithArgument("document_id", mcp.RequiredArgument(), mcp.ArgumentDescription("The ID or URL of the document to review")),
+	)
+
+	s.mcpServer.AddPrompt(actionItemsPrompt, func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		documentID := strings.TrimSpace(req.Params.Arguments["document_id"])
+		if documentID == "" {
+			return nil, fmt.Errorf("invalid document_id argument: required argument is missing")
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		client := s.client(ctx)
+		doc, err := client.GetDocument(documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document: %s", explainError(err))
+		}
+
+		people := append([]string{doc.AuthorID}, mentionedUserIDs(doc.HTML, s.threadHosts...)...)
+		names := resolveAuthorNames(client, people)
+
+		return mcp.NewGetPromptResult(
+			fmt.Sprintf("Action items in %s", doc.Title),
+			[]mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(s.actionItemsPrompt(doc, names, people[1:]))),
+			},
+		), nil
+	})
+}
+
+// actionItemsPrompt builds the extract_action_items request for doc. names resolves user IDs
+// to display names; mentioned are the users the document @mentions.
+func (s *Server) actionItemsPrompt(doc *quip.Document, names map[string]string, mentioned []string) string {
+	prompt := actionItemsInstructions + "\n\n"
+	prompt += fmt.Sprintf("**Document:** %s (ID: %s)\n", doc.Title, doc.ID)
+	if doc.AuthorID != "" {
+		prompt += fmt.Sprintf("**Author:** %s\n", authorName(names, doc.AuthorID))
+	}
+	prompt += fmt.Sprintf("**Last updated:** %s\n", s.formatTimestamp(doc.Updated))
+
+	if len(mentioned) > 0 {
+		prompt += "**People mentioned:**\n"
+		for _, id := range mentioned {
+			prompt += fmt.Sprintf("- %s (ID: %s)\n", authorName(names, id), id)
+		}
+	}
+
+	content := strings.TrimSpace(s.documentMarkdown(doc))
+	if content == "" {
+		content = "_This document has no content._"
+	}
+	return prompt + "\n---\n\n" + truncateText(content, maxPromptDocumentChars) + "\n"
+}
+
+// mentionedUserIDs returns the IDs of the people @mentioned in htmlContent, in document order
+// and without duplicates
+func mentionedUserIDs(htmlContent string, hosts ...string) []string {
+	dom, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	dom.Find("a, span").Each(func(_ int, selec *goquery.Selection) {
+		if !isQuipMention(selec) {
+			return
+		}
+		id := selec.AttrOr("data-mention-id", "")
+		if id == "" {
+			// Mention links point at the person's profile, https://quip.com/<user ID>
+			id, _ = quip.ParseThreadURL(selec.AttrOr("href", ""), hosts...)
+		}
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	})
+	return ids
+}
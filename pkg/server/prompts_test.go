@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getPrompt sends a prompts/get request through the MCP server and returns the prompt's text,
+// or the error message if the request failed
+func getPrompt(t *testing.T, s *Server, name string, args map[string]string) (string, bool) {
+	t.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "prompts/get",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+
+	switch response := s.mcpServer.HandleMessage(context.Background(), message).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := response.Result.(mcp.GetPromptResult)
+		if !ok {
+			t.Fatalf("Expected a prompt result for %s, got %T", name, response.Result)
+		}
+		var text strings.Builder
+		for _, msg := range result.Messages {
+			if textContent, ok := msg.Content.(mcp.TextContent); ok {
+				text.WriteString(textContent.Text)
+			}
+		}
+		return text.String(), false
+	case mcp.JSONRPCError:
+		return response.Error.Message, true
+	default:
+		t.Fatalf("Unexpected response %T for %s", response, name)
+		return "", true
+	}
+}
+
+func TestMentionedUserIDs(t *testing.T) {
+	html := `<p><a class="mention" href="https://quip.com/ada">@Ada</a> and <span data-mention-id="bob">@Bob</span>
+own this; <a href="https://quip.com/doc2">see plan</a>, ping <a class="mention" href="https://quip.com/ada">@Ada</a></p>`
+
+	if got, want := mentionedUserIDs(html), []string{"ada", "bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mentionedUserIDs() = %v, expected %v", got, want)
+	}
+}
+
+func TestServer_ExtractActionItemsPrompt(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc123":
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{
+				ID:       "doc123",
+				Title:    "Launch Plan",
+				AuthorID: "cy",
+				HTML:     `<p><a class="mention" href="https://quip.com/ada">@Ada</a> to draft the announcement by Friday</p><p><span data-mention-id="gone">@Former</span> books the venue</p>`,
+			}})
+		case "/users/":
+			if ids := r.URL.Query().Get("ids"); ids != "cy,ada,gone" {
+				t.Errorf("Expected the author and mentioned IDs cy,ada,gone, got %q", ids)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]quip.User{
+				"cy":  {ID: "cy", Name: "Cy Young"},
+				"ada": {ID: "ada", Name: "Ada Lovelace"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "Not found"}`))
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := getPrompt(t, s, "extract_action_items", map[string]string{"document_id": "https://quip.com/doc123/Launch-Plan"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	for _, want := range []string{
+		"- **Owner:**",
+		"- **Due date:**",
+		"**Document:** Launch Plan (ID: doc123)",
+		"**Author:** Cy Young",
+		"- Ada Lovelace (ID: ada)",
+		"- Unknown user (gone) (ID: gone)",
+		"to draft the announcement by Friday",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected prompt to contain %q, got %q", want, text)
+		}
+	}
+
+	if text, isError := getPrompt(t, s, "extract_action_items", map[string]string{"document_id": "missing"}); !isError || !strings.Contains(text, "failed to get document") {
+		t.Errorf("Expected an error for a missing document, got %q", text)
+	}
+	if text, isError := getPrompt(t, s, "extract_action_items", nil); !isError || !strings.Contains(text, "document_id") {
+		t.Errorf("Expected an error without document_id, got %q", text)
+	}
+}
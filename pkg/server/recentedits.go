@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxRecentEditPages bounds how many pages of recent threads list_recent_edits reads while
+// looking for the current user's threads
+const maxRecentEditPages = 5
+
+// registerRecentEditsTools registers the "what have I been working on" tool
+func (s *Server) registerRecentEditsTools() {
+	// List recent edits tool
+	recentEditsTool := mcp.NewTool(
+		"list_recent_edits",
+		mcp.WithDescription("List the threads you created, most recently updated first: a quick answer to \"what have I been working on?\". Unlike get_recent_threads, threads other people shared with you are left out."),
+		limitArg("Maximum number of threads to list", s.recentLimit),
+		mcp.WithString("type", mcp.Description("Only list threads of this type (default: all)"), mcp.Enum("all", "document", "spreadsheet", "chat")),
+	)
+
+	s.addTool(recentEditsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limit, err := quip.NormalizeLimit(req.GetInt("limit", s.recentLimit))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid limit argument: %v", err)), nil
+		}
+
+		client := s.client(ctx)
+		user, err := client.GetCurrentUser()
+		if err != nil {
+			return toolError("get current user", err), nil
+		}
+
+		threads, err := recentThreadsBy(client, user.ID, req.GetString("type", "all"), limit)
+		if err != nil {
+			return toolError("get recent threads", err), nil
+		}
+
+		if len(threads) == 0 {
+			return mcp.NewToolResultText("No recent threads created by you were found."), nil
+		}
+
+		response := fmt.Sprintf("Your %d most recently updated threads:\n\n", len(threads))
+		for i, thread := range threads {
+			response += fmt.Sprintf("%d. **%s** (%s)\n", i+1, thread.Title, thread.Type)
+			response += fmt.Sprintf("   - ID: %s\n", thread.ID)
+			response += fmt.Sprintf("   - Link: %s\n", thread.Link)
+			response += fmt.Sprintf("   - Updated: %s\n\n", s.formatTimestamp(thread.Updated))
+		}
+		response += "Quip doesn't report who last edited a thread, so only threads you created are listed.\n"
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// recentThreadsBy pages through the current user's recent threads, newest first, collecting up
+// to limit threads of threadType authored by authorID
+func recentThreadsBy(client *quip.Client, authorID, threadType string, limit int) ([]quip.Document, error) {
+	var threads []quip.Document
+	opts := quip.RecentOptions{Limit: quip.MaxLimit, Type: threadType}
+
+	for page := 0; page < maxRecentEditPages; page++ {
+		result, err := client.GetRecentThreadsWithOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, thread := range result.Threads {
+			if thread.AuthorID != authorID {
+				continue
+			}
+			threads = append(threads, thread)
+			if len(threads) == limit {
+				return threads, nil
+			}
+		}
+
+		if result.NextCursor == 0 {
+			break
+		}
+		opts.MaxUpdatedUsec = result.NextCursor
+	}
+
+	return threads, nil
+}
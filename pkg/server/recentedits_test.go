@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_ListRecentEdits(t *testing.T) {
+	var userLookups int32
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/current":
+			atomic.AddInt32(&userLookups, 1)
+			_ = json.NewEncoder(w).Encode(quip.User{ID: "me"})
+		case "/threads/recent":
+			// A full first page with one of my threads, then an older page with another
+			threads := quip.RecentThreadsResponse{}
+			if r.URL.Query().Get("max_updated_usec") == "" {
+				for i := 0; i < quip.MaxLimit; i++ {
+					id := fmt.Sprintf("shared%d", i)
+					threads[id] = quip.RecentThreadData{Thread: quip.Document{ID: id, AuthorID: "someone", Updated: int64(1000 - i)}}
+				}
+				threads["shared0"] = quip.RecentThreadData{Thread: quip.Document{ID: "mine1", Title: "Roadmap", Type: "document", AuthorID: "me", Updated: 1000}}
+			} else {
+				threads["mine2"] = quip.RecentThreadData{Thread: quip.Document{ID: "mine2", Title: "Budget", Type: "spreadsheet", AuthorID: "me", Updated: 500}}
+			}
+			_ = json.NewEncoder(w).Encode(threads)
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "list_recent_edits", map[string]any{"limit": 2})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(text, "1. **Roadmap** (document)") || !strings.Contains(text, "2. **Budget** (spreadsheet)") {
+		t.Errorf("Expected my two threads newest first, got %q", text)
+	}
+	if strings.Contains(text, "shared") {
+		t.Errorf("Expected other people's threads to be left out, got %q", text)
+	}
+	if got := atomic.LoadInt32(&userLookups); got != 1 {
+		t.Errorf("Expected the current user to be looked up once, got %d", got)
+	}
+}
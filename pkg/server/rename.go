@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerRenameTools registers the document renaming tool
+func (s *Server) registerRenameTools() {
+	// Rename document tool
+	renameTool := mcp.NewTool(
+		"rename_document",
+		mcp.WithDescription("Change a Quip document's title without touching its content"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to rename")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("The new title")),
+	)
+
+	s.addTool(renameTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		title, err := req.RequireString("title")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid title argument: %v", err)), nil
+		}
+
+		doc, err := s.client(ctx).RenameDocument(documentID, title)
+		if err != nil {
+			return toolError("rename document", err), nil
+		}
+
+		response := "✏️ **Document renamed**\n\n"
+		response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		if doc.Link != "" {
+			response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_RenameDocument(t *testing.T) {
+	var threadID, title string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		threadID, title = r.FormValue("thread_id"), r.FormValue("title")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{
+			Thread: quip.Document{ID: "doc123", Title: title, Link: "https://quip.com/doc123"},
+		})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "rename_document", map[string]any{
+		"document_id": "https://quip.com/doc123/Old-Title",
+		"title":       "Q3 Plan",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if threadID != "doc123" || title != "Q3 Plan" {
+		t.Errorf("Expected doc123 to be renamed to 'Q3 Plan', got %q, %q", threadID, title)
+	}
+	if !strings.Contains(text, "Document renamed") || !strings.Contains(text, "- **Title:** Q3 Plan") {
+		t.Errorf("Expected the new title to be confirmed, got %q", text)
+	}
+
+	if text, isError := callTool(t, s, "rename_document", map[string]any{"document_id": "doc123", "title": "  "}); !isError {
+		t.Errorf("Expected an error for an empty title, got %q", text)
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Heading levels add_section accepts; Quip documents have three heading styles
+const (
+	defaultSectionLevel = 2
+	maxSectionLevel     = 3
+)
+
+// registerSectionTools registers the tools that read and edit a document by heading
+func (s *Server) registerSectionTools() {
+	// List sections tool
+	listSectionsTool := mcp.NewTool(
+		"list_sections",
+		mcp.WithDescription("List the headed sections of a Quip document with their section IDs, for use with edit_section"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document")),
+	)
+
+	s.addTool(listSectionsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		sections, err := s.client(ctx).GetSections(documentID)
+		if err != nil {
+			return toolError("get sections", err), nil
+		}
+
+		if len(sections) == 0 {
+			return mcp.NewToolResultText("This document has no headings, so it has no sections to edit. Use edit_document instead."), nil
+		}
+
+		return mcp.NewToolResultText(formatSections(sections)), nil
+	})
+
+	// Edit section tool
+	editSectionTool := mcp.NewTool(
+		"edit_section",
+		mcp.WithDescription("Replace the content under one heading of a Quip document, leaving the heading and the rest of the document untouched. Subsections below the heading are part of its content and are replaced too."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to edit")),
+		mcp.WithString("section", mcp.Required(), mcp.Description("The section's heading text or section ID (see list_sections)")),
+		mcp.WithString("content", mcp.Required(), mcp.Description("The new content for the section"+s.contentLimitNote())),
+		mcp.WithString("format", mcp.Description("Content format: markdown (default), html"), mcp.Enum("markdown", "html"), mcp.DefaultString(quip.DefaultContentFormat)),
+	)
+
+	s.addTool(editSectionTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		section, err := req.RequireString("section")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid section argument: %v", err)), nil
+		}
+
+		content, err := req.RequireString("content")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content argument: %v", err)), nil
+		}
+
+		content, format, err := s.documentContent(content, req.GetString("format", quip.DefaultContentFormat))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content: %v", err)), nil
+		}
+
+		doc, err := s.client(ctx).ReplaceSectionContent(documentID, section, content, format)
+		if err != nil {
+			return toolError("edit section", err), nil
+		}
+
+		response := "✅ **Section updated!**\n\n"
+		response += fmt.Sprintf("- **Section:** %s\n", section)
+		response += fmt.Sprintf("- **Document:** %s\n", doc.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+		response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Add section tool
+	addSectionTool := mcp.NewTool(
+		"add_section",
+		mcp.WithDescription("Append a titled section to the end of a Quip document: a heading followed by markdown content. The heading becomes a real heading, so the section shows up in list_sections and can be edited with edit_section."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to add to")),
+		mcp.WithString("heading", mcp.Required(), mcp.Description("The section's heading text (plain text, not markdown)")),
+		mcp.WithString("content", mcp.Required(), mcp.Description("The section's content in markdown"+s.contentLimitNote())),
+		mcp.WithNumber("level",
+			mcp.Description(fmt.Sprintf("Heading level, 1 for the largest (default: %d)", defaultSectionLevel)),
+			mcp.Min(1),
+			mcp.Max(maxSectionLevel),
+		),
+	)
+
+	s.addTool(addSectionTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		heading, err := req.RequireString("heading")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid heading argument: %v", err)), nil
+		}
+		heading = strings.TrimSpace(heading)
+		if heading == "" {
+			return mcp.NewToolResultError("Invalid heading argument: must not be empty"), nil
+		}
+
+		content, err := req.RequireString("content")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content argument: %v", err)), nil
+		}
+
+		level := req.GetInt("level", defaultSectionLevel)
+		if level < 1 || level > maxSectionLevel {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid level argument: must be between 1 and %d", maxSectionLevel)), nil
+		}
+
+		section, err := sectionHTML(heading, level, content)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content: %v", err)), nil
+		}
+
+		doc, err := s.client(ctx).EditDocument(documentID, section, "APPEND", contentFormatHTML)
+		if err != nil {
+			return toolError("add section", err), nil
+		}
+
+		response := "✅ **Section added!**\n\n"
+		response += fmt.Sprintf("- **Section:** %s\n", heading)
+		response += fmt.Sprintf("- **Document:** %s\n", doc.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+		response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// sectionHTML builds the Quip HTML for a section: a heading element at level, escaped so the
+// heading text is taken literally, followed by the markdown content converted to Quip HTML
+func sectionHTML(heading string, level int, content string) (string, error) {
+	body, err := markdownToQuipHTML(content)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<h%d>%s</h%d>%s", level, html.EscapeString(heading), level, body), nil
+}
+
+// formatSections renders sections as an outline, indented by heading level
+func formatSections(sections []quip.Section) string {
+	top := sections[0].Level
+	for _, section := range sections {
+		top = min(top, section.Level)
+	}
+
+	response := fmt.Sprintf("Found %s:\n\n", pluralize(len(sections), "section"))
+	for _, section := range sections {
+		heading := section.Heading
+		if heading == "" {
+			heading = "(untitled)"
+		}
+		indent := strings.Repeat("  ", section.Level-top)
+		response += fmt.Sprintf("%s- **%s** — ID: %s, %s\n", indent, heading, section.ID, pluralize(len(section.BlockIDs), "block"))
+	}
+
+	return response
+}
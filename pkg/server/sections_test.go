@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_Sections(t *testing.T) {
+	var replaced []string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc1":
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{
+				Thread: quip.Document{ID: "doc1", Title: "Plan"},
+				HTML:   `<h1 id="h1">Plan</h1><h2 id="h2">Goals</h2><p id="p1">Old</p><p id="p2">Older</p><h2 id="h3">Risks</h2><p id="p3">Keep</p>`,
+			})
+		case "/threads/edit-document":
+			replaced = append(replaced, r.FormValue("section_id")+":"+r.FormValue("location"))
+			if r.FormValue("location") == "4" && !strings.Contains(r.FormValue("content"), "<li") {
+				t.Errorf("Expected the markdown list to be converted to HTML, got %q", r.FormValue("content"))
+			}
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc1", Title: "Plan", Link: "https://quip.com/doc1"}})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)), WithMarkdownConversion(MarkdownConversionClient))
+
+	text, isError := callTool(t, s, "list_sections", map[string]any{"document_id": "doc1"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	for _, want := range []string{"Found 3 sections", "- **Plan** — ID: h1, 5 blocks", "  - **Goals** — ID: h2, 2 blocks", "  - **Risks** — ID: h3, 1 block"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected list to contain %q, got %q", want, text)
+		}
+	}
+
+	text, isError = callTool(t, s, "edit_section", map[string]any{"document_id": "doc1", "section": "goals", "content": "- one\n- two"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(text, "Section updated") {
+		t.Errorf("Expected a success message, got %q", text)
+	}
+	if got := strings.Join(replaced, ","); got != "p1:4,p2:5" {
+		t.Errorf("Expected only the Goals blocks to be edited, got %s", got)
+	}
+
+	text, isError = callTool(t, s, "edit_section", map[string]any{"document_id": "doc1", "section": "Budget", "content": "x"})
+	if !isError || !strings.Contains(text, "no section") {
+		t.Errorf("Expected an unknown section error, got %q", text)
+	}
+}
+
+func TestSectionHTML(t *testing.T) {
+	got, err := sectionHTML("Q3 <Results> & Next", 2, "Revenue grew.\n\n- one")
+	if err != nil {
+		t.Fatalf("sectionHTML() error = %v", err)
+	}
+	if want := "<h2>Q3 &lt;Results&gt; &amp; Next</h2><p>Revenue grew.</p>"; !strings.HasPrefix(got, want) {
+		t.Errorf("Expected the escaped heading followed by the content, got %q", got)
+	}
+	if !strings.Contains(got, "<li>one</li>") {
+		t.Errorf("Expected the markdown list to be converted, got %q", got)
+	}
+
+	// The heading is plain text, so HTML that is already escaped stays literal
+	got, err = sectionHTML("Fish &amp; <b>Chips</b>", 1, "Menu")
+	if err != nil {
+		t.Fatalf("sectionHTML() error = %v", err)
+	}
+	if want := "<h1>Fish &amp;amp; &lt;b&gt;Chips&lt;/b&gt;</h1>"; !strings.HasPrefix(got, want) {
+		t.Errorf("Expected the escaped HTML to be escaped again, got %q", got)
+	}
+}
+
+func TestServer_AddSection(t *testing.T) {
+	var form map[string]string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/edit-document" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		form = map[string]string{"thread_id": r.FormValue("thread_id"), "content": r.FormValue("content"), "format": r.FormValue("format"), "location": r.FormValue("location")}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc1", Title: "Report", Link: "https://quip.com/doc1"}})
+	}))
+	defer quipServer.Close()
+
+	// Server-side conversion sends other markdown as is, but a section still needs its heading
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)), WithMarkdownConversion(MarkdownConversionServer))
+
+	text, isError := callTool(t, s, "add_section", map[string]any{"document_id": "https://quip.com/doc1/Report", "heading": "Findings", "content": "All **good**", "level": 1})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(text, "Section added") || !strings.Contains(text, "- **Section:** Findings") {
+		t.Errorf("Expected a success message, got %q", text)
+	}
+	if form["thread_id"] != "doc1" || form["format"] != "html" || form["location"] != "0" {
+		t.Errorf("Expected HTML appended to doc1, got %v", form)
+	}
+	if !strings.HasPrefix(form["content"], "<h1>Findings</h1><p>All <strong>good</strong></p>") {
+		t.Errorf("Expected a heading followed by the content, got %q", form["content"])
+	}
+
+	text, isError = callTool(t, s, "add_section", map[string]any{"document_id": "doc1", "heading": "  ", "content": "x"})
+	if !isError || !strings.Contains(text, "Invalid heading argument") {
+		t.Errorf("Expected an empty heading error, got %q", text)
+	}
+}
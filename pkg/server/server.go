@@ -1,50 +1,317 @@
 package server
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
-	md "github.com/JohannesKaufmann/html-to-markdown"
 	"github.com/bug-breeder/quip-mcp/pkg/quip"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// DefaultVersion is the version reported to MCP clients when WithVersion isn't used
+const DefaultVersion = "1.4.0"
+
+// DefaultDeletePhrase is the confirmation delete_document requires to move a document to the
+// trash; permanent deletion requires it prefixed with "PERMANENTLY "
+const DefaultDeletePhrase = "DELETE"
+
+// ShutdownTimeout bounds how long Start waits for in-flight tool calls after a shutdown is requested
+const ShutdownTimeout = 10 * time.Second
+
 // Server represents the MCP Quip server
 type Server struct {
-	mcpServer  *server.MCPServer
-	quipClient *quip.Client
+	mcpServer     *server.MCPServer
+	quipClient    *quip.Client
+	clientOptions []quip.Option
+	logger        *slog.Logger
+	version       string
+
+	readinessTTL time.Duration
+	readiness    readinessCache
+
+	startupCheckTimeout time.Duration
+
+	dryRun bool
+
+	searchLimit int
+	recentLimit int
+
+	maxResponseBytes int
+
+	contentDir string
+	exportDir  string
+
+	toolTimeout  time.Duration
+	toolTimeouts map[string]time.Duration
+
+	deletePhrase        string
+	deleteRequiresTitle bool
+
+	markdownOptions MarkdownOptions
+	markdownCache   quip.Cache
+	cacheScope      string
+	threadHosts     []string
+
+	markdownConversion string
+
+	timeLocation *time.Location
+	dateFormat   string
+
+	enabledTools  map[string]bool
+	disabledTools map[string]bool
+	knownTools    []mcp.Tool
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	serveCtx context.Context // cancelled when the running transport shuts down
+	draining bool
+	inFlight sync.WaitGroup
 }
 
-// New creates a new MCP Quip server
-func New(token string) *Server {
-	mcpServer := server.NewMCPServer(
-		"Quip MCP Server",
-		"1.4.0",
-	)
+// Option configures optional Server behaviour
+type Option func(*Server)
 
-	quipClient := quip.NewClient(token)
+// WithLogger sets the structured logger used by the server (default: slog.Default())
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithClientOptions passes options through to the underlying Quip client
+func WithClientOptions(opts ...quip.Option) Option {
+	return func(s *Server) {
+		s.clientOptions = append(s.clientOptions, opts...)
+	}
+}
+
+// WithVersion sets the version reported to MCP clients and, together with commit, sent to
+// Quip in the User-Agent (see quip.WithVersion)
+func WithVersion(version, commit string) Option {
+	return func(s *Server) {
+		s.version = version
+		s.clientOptions = append(s.clientOptions, quip.WithVersion(version, commit))
+	}
+}
+
+// WithDefaultLimits sets how many results search_documents and get_recent_threads return
+// when no limit is given (default: quip.DefaultLimit). Zero keeps the default.
+func WithDefaultLimits(search, recent int) Option {
+	return func(s *Server) {
+		if search > 0 {
+			s.searchLimit = search
+		}
+		if recent > 0 {
+			s.recentLimit = recent
+		}
+	}
+}
 
+// WithDeleteConfirmation sets the phrase delete_document's confirm argument must match
+// (default: DefaultDeletePhrase). With requireTitle, the phrase must be followed by a space
+// and the document's exact title, e.g. "DELETE Q3 Roadmap". An empty phrase keeps the default.
+func WithDeleteConfirmation(phrase string, requireTitle bool) Option {
+	return func(s *Server) {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			s.deletePhrase = phrase
+		}
+		s.deleteRequiresTitle = requireTitle
+	}
+}
+
+// New creates a new MCP Quip server
+func New(token string, opts ...Option) *Server {
 	s := &Server{
-		mcpServer:  mcpServer,
-		quipClient: quipClient,
+		logger:           slog.Default(),
+		version:          DefaultVersion,
+		searchLimit:      quip.DefaultLimit,
+		recentLimit:      quip.DefaultLimit,
+		maxResponseBytes: DefaultMaxResponseBytes,
+		toolTimeout:      DefaultToolTimeout,
+		deletePhrase:     DefaultDeletePhrase,
+		readinessTTL:     DefaultReadinessTTL,
+		timeLocation:     time.UTC,
+		dateFormat:       DefaultDateFormat,
+
+		startupCheckTimeout: DefaultStartupCheckTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.quipClient = quip.NewClient(token, s.clientOptions...)
+	s.cacheScope = quip.CacheScope(token, s.quipClient.BaseURL())
+	s.threadHosts = []string{quip.WebHost(s.quipClient.BaseURL())}
+
+	serverOpts := []server.ServerOption{
+		server.WithToolHandlerMiddleware(s.trackInFlight),
+		server.WithToolHandlerMiddleware(s.traceToolCall),
+		server.WithToolHandlerMiddleware(s.logToolCall),
+		server.WithToolHandlerMiddleware(s.truncateToolResult),
+		server.WithToolHandlerMiddleware(s.timeoutToolCall),
+	}
+	if s.dryRun {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(s.dryRunToolCall))
 	}
 
+	s.mcpServer = server.NewMCPServer("Quip MCP Server", s.version, serverOpts...)
+
 	// Register tools
 	s.registerTools()
 	// Register resources
 	s.registerResources()
+	// Register prompts
+	s.registerPrompts()
 
 	return s
 }
 
-// Start starts the MCP server
+// client returns the Quip client bound to ctx, so API calls follow the tool call's
+// cancellation and tracing
+func (s *Server) client(ctx context.Context) *quip.Client {
+	return s.quipClient.WithContext(ctx)
+}
+
+// Start starts the MCP server on stdio and blocks until stdin closes, Stop is called,
+// or the process receives SIGINT/SIGTERM. In-flight tool calls are given ShutdownTimeout to finish.
+// Unless disabled with WithStartupCheck, the token is verified first and a rejected token is an error.
 func (s *Server) Start() error {
-	log.Println("Starting MCP Quip Server...")
-	return server.ServeStdio(s.mcpServer)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := s.verifyToken(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("Starting MCP Quip Server")
+	return s.serve(ctx, os.Stdin, os.Stdout)
+}
+
+// Stop requests a graceful shutdown of a running server
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// serve runs the stdio transport until ctx is cancelled or input ends, then drains in-flight calls
+func (s *Server) serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	s.cancel = cancel
+	s.serveCtx = ctx
+	s.mu.Unlock()
+
+	// Only JSON-RPC frames may be written to out; transport errors go through the logger (stderr)
+	stdio := server.NewStdioServer(s.mcpServer)
+	stdio.SetErrorLogger(slog.NewLogLogger(s.logger.Handler(), slog.LevelError))
+
+	err := stdio.Listen(ctx, in, out)
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+
+	if !s.drain(ShutdownTimeout) {
+		s.logger.Warn("Shutdown timed out with tool calls still running", "timeout", ShutdownTimeout)
+	}
+	s.logger.Info("MCP Quip Server stopped")
+
+	return err
+}
+
+// trackInFlight is tool middleware that records running calls so shutdown can wait for them.
+// A call is still cancelled when its client cancels it, but not when its context ends because
+// the server is shutting down, so the drain doesn't abort calls midway.
+func (s *Server) trackInFlight(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.mu.Lock()
+		if s.draining {
+			s.mu.Unlock()
+			return mcp.NewToolResultError("Server is shutting down"), nil
+		}
+		s.inFlight.Add(1)
+		s.mu.Unlock()
+		defer s.inFlight.Done()
+
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+		stop := context.AfterFunc(ctx, func() {
+			if !s.shuttingDown() {
+				cancel()
+			}
+		})
+		defer stop()
+
+		return next(callCtx, req)
+	}
+}
+
+// shuttingDown reports whether the server has started shutting down
+func (s *Server) shuttingDown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.draining || (s.serveCtx != nil && s.serveCtx.Err() != nil)
+}
+
+// logToolCall is tool middleware that logs each invocation with its name and duration at debug level,
+// and calls that fail outright at error level
+func (s *Server) logToolCall(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, req)
+
+		attrs := []any{"tool", req.Params.Name, "duration", time.Since(start)}
+		attrs = append(attrs, traceAttrs(ctx)...)
+		if err != nil {
+			s.logger.Error("Tool call failed", append(attrs, "error", err)...)
+			return result, err
+		}
+		if result != nil && result.IsError {
+			attrs = append(attrs, "is_error", true)
+		}
+		s.logger.Debug("Tool call finished", attrs...)
+
+		return result, err
+	}
+}
+
+// drain stops accepting tool calls and waits up to timeout for running ones, reporting whether they all finished
+func (s *Server) drain(timeout time.Duration) bool {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
 }
 
 // registerTools registers all the MCP tools
@@ -54,20 +321,68 @@ func (s *Server) registerTools() {
 		"search_documents",
 		mcp.WithDescription("Search for Quip documents"),
 		mcp.WithString("query", mcp.Required(), mcp.Description("Search query for documents")),
-		mcp.WithNumber("limit", mcp.Description("Maximum number of results (default: 10)")),
+		limitArg("Maximum number of results", s.searchLimit),
+		mcp.WithString("author", mcp.Description("Only return documents created by this user ID, or \"me\" for the current user")),
+		mcp.WithString("since", mcp.Description("Only return documents updated (or created, see date_field) on or after this date (YYYY-MM-DD or RFC 3339)")),
+		mcp.WithString("until", mcp.Description("Only return documents updated (or created, see date_field) on or before this date (YYYY-MM-DD or RFC 3339)")),
+		mcp.WithString("date_field", mcp.Description("Which timestamp since/until apply to (default: updated)"), mcp.Enum("updated", "created")),
+		mcp.WithBoolean("rank", mcp.Description("Order results with title matches first, then most recently updated (default: true); false keeps Quip's order")),
+		mcp.WithString("folder_id", mcp.Description("Only return documents directly inside this folder (ID or URL). Matches are taken from Quip's top 100 results for the query, so use a specific query.")),
+		includeTrashedArg(),
 	)
 
-	s.mcpServer.AddTool(searchTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(searchTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		query, err := req.RequireString("query")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid query argument: %v", err)), nil
 		}
 
-		limit := req.GetInt("limit", 10)
+		limit, err := quip.NormalizeLimit(req.GetInt("limit", s.searchLimit))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid limit argument: %v", err)), nil
+		}
+
+		opts := quip.SearchOptions{
+			Limit:          limit,
+			Rank:           req.GetBool("rank", true),
+			IncludeTrashed: req.GetBool("include_trashed", false),
+		}
 
-		result, err := s.quipClient.SearchDocuments(query, limit)
+		since, err := parseDateArg(req.GetString("since", ""), false)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to search documents: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid since argument: %v", err)), nil
+		}
+
+		until, err := parseDateArg(req.GetString("until", ""), true)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid until argument: %v", err)), nil
+		}
+
+		switch dateField := req.GetString("date_field", "updated"); dateField {
+		case "updated":
+			opts.UpdatedAfter, opts.UpdatedBefore = since, until
+		case "created":
+			opts.CreatedAfter, opts.CreatedBefore = since, until
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid date_field %q: must be updated or created", dateField)), nil
+		}
+
+		if folderID := req.GetString("folder_id", ""); folderID != "" {
+			opts.FolderID = quip.ResolveThreadID(folderID)
+		}
+
+		opts.AuthorID = req.GetString("author", "")
+		if strings.EqualFold(opts.AuthorID, "me") {
+			user, err := s.client(ctx).GetCurrentUser()
+			if err != nil {
+				return toolError("get current user", err), nil
+			}
+			opts.AuthorID = user.ID
+		}
+
+		result, err := s.client(ctx).SearchDocumentsWithOptions(query, opts)
+		if err != nil {
+			return toolError("search documents", err), nil
 		}
 
 		response := fmt.Sprintf("Found %d documents:\n\n", len(result.Documents))
@@ -76,7 +391,8 @@ func (s *Server) registerTools() {
 			response += fmt.Sprintf("   - ID: %s\n", doc.ID)
 			response += fmt.Sprintf("   - Link: %s\n", doc.Link)
 			response += fmt.Sprintf("   - Author: %s\n", doc.AuthorID)
-			response += fmt.Sprintf("   - Updated: %s\n\n", formatTimestamp(doc.Updated))
+			response += trashedNote(doc)
+			response += fmt.Sprintf("   - Updated: %s\n\n", s.formatTimestamp(doc.Updated))
 		}
 
 		return mcp.NewToolResultText(response), nil
@@ -86,63 +402,148 @@ func (s *Server) registerTools() {
 	getDocTool := mcp.NewTool(
 		"get_document",
 		mcp.WithDescription("Get a specific Quip document by ID"),
-		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID of the document to retrieve")),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to retrieve")),
+		mcp.WithNumber("max_chars", mcp.Description("Maximum number of content characters to return (default: no limit)"), mcp.Min(1)),
+		mcp.WithNumber("offset", mcp.Description("Character offset to start reading content from, for reading large documents in chunks (default: 0)"), mcp.Min(0)),
+		contentFormatArg(),
+		mcp.WithNumber("expand_links",
+			mcp.Description(fmt.Sprintf("Follow links to other Quip documents this many levels deep and list them after the content (default: 0, don't follow; at most %d documents)", maxLinkExpansions)),
+			mcp.Min(0),
+			mcp.Max(maxExpandDepth),
+		),
+		mcp.WithBoolean("expand_content", mcp.Description(fmt.Sprintf("With expand_links, include each linked document's content as markdown, up to %d characters each, instead of just its title (default: false)", maxExpandedDocChars))),
 	)
 
-	s.mcpServer.AddTool(getDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(getDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		documentID, err := req.RequireString("document_id")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
 		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		maxChars := req.GetInt("max_chars", 0)
+		if maxChars < 0 {
+			return mcp.NewToolResultError("max_chars must be positive"), nil
+		}
+
+		offset := req.GetInt("offset", 0)
+		if offset < 0 {
+			return mcp.NewToolResultError("offset must not be negative"), nil
+		}
 
-		doc, err := s.quipClient.GetDocument(documentID)
+		contentFormat, err := contentFormatFrom(req)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get document: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content_format argument: %v", err)), nil
 		}
 
-		response := fmt.Sprintf("**%s**\n\n", doc.Title)
-		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
-		response += fmt.Sprintf("- **Type:** %s\n", doc.Type)
-		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
-		response += fmt.Sprintf("- **Author:** %s\n", doc.AuthorID)
-		response += fmt.Sprintf("- **Created:** %s\n", formatTimestamp(doc.Created))
-		response += fmt.Sprintf("- **Updated:** %s\n", formatTimestamp(doc.Updated))
-		response += fmt.Sprintf("- **Access Level:** %s\n", doc.AccessLevel)
+		expandDepth := req.GetInt("expand_links", 0)
+		if expandDepth < 0 || expandDepth > maxExpandDepth {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid expand_links argument: must be between 0 and %d", maxExpandDepth)), nil
+		}
 
-		if doc.HTML != "" {
-			markdown := htmlToMarkdown(doc.HTML)
-			response += fmt.Sprintf("\n**Content:**\n%s\n", markdown)
+		client := s.client(ctx)
+		doc, err := getDocumentWithPeople(client, documentID)
+		if err != nil {
+			return toolError("get document", err), nil
 		}
 
-		return mcp.NewToolResultText(response), nil
+		response, err := s.formatDocument(doc, accessLevelNames(client, doc), contentFormat, offset, maxChars)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if expandDepth > 0 {
+			linked, capped := expandLinks(client, doc, expandDepth)
+			response += s.formatLinkedDocuments(linked, capped, req.GetBool("expand_content", false))
+		}
+
+		return mcp.NewToolResultText(threadTypeNote(doc) + response), nil
+	})
+
+	// Get document by URL tool
+	getDocByURLTool := mcp.NewTool(
+		"get_document_by_url",
+		mcp.WithDescription("Get a Quip document from a link pasted by the user, e.g. https://quip.com/AbCdEfGhIjK/Title (enterprise hosts and query strings are fine)"),
+		mcp.WithString("url", mcp.Required(), mcp.Description("The Quip link to the document")),
+		contentFormatArg(),
+	)
+
+	s.addTool(getDocByURLTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		link, err := req.RequireString("url")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid url argument: %v", err)), nil
+		}
+
+		documentID, err := quip.ParseThreadURL(link, s.threadHosts...)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid url argument: %v. Pass a Quip link such as https://quip.com/AbCdEfGhIjK, or use get_document with a document ID.", err)), nil
+		}
+
+		contentFormat, err := contentFormatFrom(req)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content_format argument: %v", err)), nil
+		}
+
+		client := s.client(ctx)
+		doc, err := getDocumentWithPeople(client, documentID)
+		if err != nil {
+			return toolError("get document", err), nil
+		}
+
+		response, err := s.formatDocument(doc, accessLevelNames(client, doc), contentFormat, 0, 0)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(threadTypeNote(doc) + response), nil
 	})
 
 	// Create document tool
 	createDocTool := mcp.NewTool(
 		"create_document",
-		mcp.WithDescription("Create a new Quip document"),
-		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the new document")),
-		mcp.WithString("content", mcp.Description("The initial content of the document (Markdown format)")),
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Create a new Quip document"),
+			mcp.WithString("title", mcp.Required(), mcp.Description("The title of the new document")),
+			mcp.WithString("content", mcp.Description("The initial content of the document"+s.contentLimitNote())),
+			mcp.WithString("format", mcp.Description(s.createFormatNote()), mcp.Enum("markdown", "html"), mcp.DefaultString(quip.DefaultContentFormat)),
+			mcp.WithString("folder_id", mcp.Description(s.createFolderNote())),
+		}, s.contentFileArg()...)...,
 	)
 
-	s.mcpServer.AddTool(createDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(createDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		title, err := req.RequireString("title")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid title argument: %v", err)), nil
 		}
 
-		content := req.GetString("content", "")
+		content, err := s.contentFromRequest(req, false)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content argument: %v", err)), nil
+		}
 
-		doc, err := s.quipClient.CreateDocument(title, content)
+		content, format, err := s.documentContent(content, req.GetString("format", quip.DefaultContentFormat))
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to create document: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content: %v", err)), nil
+		}
+
+		opts := quip.CreateOptions{Format: format}
+		if folderID := req.GetString("folder_id", ""); folderID != "" {
+			opts.FolderID = quip.ResolveThreadID(folderID)
+		}
+
+		doc, err := s.client(ctx).CreateDocumentWithOptions(title, content, opts)
+		if err != nil {
+			return toolError("create document", err), nil
 		}
 
 		response := "✅ **Document created successfully!**\n\n"
 		response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
 		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
 		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
-		response += fmt.Sprintf("- **Created:** %s\n", formatTimestamp(doc.Created))
+		response += fmt.Sprintf("- **Created:** %s\n", s.formatTimestamp(doc.Created))
+		if folderID := cmp.Or(opts.FolderID, s.quipClient.DefaultFolderID()); folderID != "" {
+			response += fmt.Sprintf("- **Folder:** %s\n", folderID)
+		}
 
 		return mcp.NewToolResultText(response), nil
 	})
@@ -151,68 +552,84 @@ func (s *Server) registerTools() {
 	getUserTool := mcp.NewTool(
 		"get_user",
 		mcp.WithDescription("Get Quip user information"),
-		mcp.WithString("user_id", mcp.Required(), mcp.Description("The ID of the user to retrieve (use 'current' for current user)")),
+		mcp.WithString("user_id", mcp.Required(), mcp.Description("The ID or email address of the user to retrieve (use 'current' for current user)")),
 	)
 
-	s.mcpServer.AddTool(getUserTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(getUserTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		userID, err := req.RequireString("user_id")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid user_id argument: %v", err)), nil
 		}
 
-		var user *quip.User
-
-		if userID == "current" {
-			user, err = s.quipClient.GetCurrentUser()
-		} else {
-			user, err = s.quipClient.GetUser(userID)
-		}
-
+		user, err := lookupUser(s.client(ctx), userID)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get user: %v", err)), nil
-		}
-
-		response := fmt.Sprintf("**%s**\n\n", user.Name)
-		response += fmt.Sprintf("- **ID:** %s\n", user.ID)
-		response += fmt.Sprintf("- **Email:** %s\n", user.Email)
-		response += fmt.Sprintf("- **Profile URL:** %s\n", user.URL)
-		response += fmt.Sprintf("- **Created:** %s\n", formatTimestamp(user.Created))
-		response += fmt.Sprintf("- **Updated:** %s\n", formatTimestamp(user.Updated))
-
-		if user.ProfilePic != "" {
-			response += fmt.Sprintf("- **Profile Picture:** %s\n", user.ProfilePic)
+			return toolError("get user", err), nil
 		}
 
-		return mcp.NewToolResultText(response), nil
+		return mcp.NewToolResultText(s.formatUser(user)), nil
 	})
 
 	// Get document comments tool
 	getCommentsTool := mcp.NewTool(
 		"get_document_comments",
 		mcp.WithDescription("Get comments for a Quip document"),
-		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID of the document to get comments for")),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to get comments for")),
+		mcp.WithNumber("count", mcp.Description("Maximum number of comments to return (default: Quip's page size)")),
+		mcp.WithString("cursor", mcp.Description("Cursor from a previous call to fetch the next page of comments, in the same sort order")),
+		mcp.WithString("updated_since", mcp.Description("Only return comments updated after this timestamp (microseconds)")),
+		mcp.WithString("sort", mcp.Description("Sort order by creation time: DESC (newest first, default) or ASC"), mcp.Enum("DESC", "ASC")),
 	)
 
-	s.mcpServer.AddTool(getCommentsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(getCommentsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		documentID, err := req.RequireString("document_id")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
 		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		opts := quip.CommentOptions{
+			Count:    req.GetInt("count", 0),
+			SortedBy: req.GetString("sort", ""),
+		}
+
+		if cursor := req.GetString("cursor", ""); cursor != "" {
+			if err := opts.ContinueFrom(cursor); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid cursor argument: %v", err)), nil
+			}
+		}
+
+		if updatedSince := req.GetString("updated_since", ""); updatedSince != "" {
+			opts.LastUpdatedSinceUsec, err = strconv.ParseInt(updatedSince, 10, 64)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid updated_since argument: %v", err)), nil
+			}
+		}
 
-		comments, err := s.quipClient.GetDocumentComments(documentID)
+		client := s.client(ctx)
+		page, err := client.GetDocumentCommentsPage(documentID, opts)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get comments: %v", err)), nil
+			return toolError("get comments", err), nil
 		}
 
+		comments := page.Comments
 		if len(comments) == 0 {
 			return mcp.NewToolResultText("No comments found for this document."), nil
 		}
 
+		names := resolveAuthorNames(client, commentAuthorIDs(comments))
+
 		response := fmt.Sprintf("Found %d comments:\n\n", len(comments))
 		for i, comment := range comments {
-			response += fmt.Sprintf("%d. **Author:** %s\n", i+1, comment.AuthorID)
-			response += fmt.Sprintf("   **Created:** %s\n", formatTimestamp(comment.Created))
-			response += fmt.Sprintf("   **Text:** %s\n\n", comment.Text)
+			response += fmt.Sprintf("%d. **%s** · %s: %s\n", i+1, authorName(names, comment.AuthorID), s.formatDate(comment.Created), comment.Text)
+		}
+		response += "\n"
+
+		if page.NextCursor != "" {
+			if strings.EqualFold(opts.SortedBy, "ASC") {
+				response += fmt.Sprintf("Newer comments available. Use cursor=%s and sort=ASC to fetch the next page.\n", page.NextCursor)
+			} else {
+				response += fmt.Sprintf("Older comments available. Use cursor=%s to fetch the next page.\n", page.NextCursor)
+			}
 		}
 
 		return mcp.NewToolResultText(response), nil
@@ -221,79 +638,105 @@ func (s *Server) registerTools() {
 	// Edit document tool
 	editDocTool := mcp.NewTool(
 		"edit_document",
-		mcp.WithDescription("Edit an existing Quip document"),
-		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID of the document to edit")),
-		mcp.WithString("content", mcp.Required(), mcp.Description("The new content for the document")),
-		mcp.WithString("operation", mcp.Description("Edit operation: REPLACE (default), APPEND, PREPEND")),
-		mcp.WithString("format", mcp.Description("Content format: markdown (default), html")),
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Edit an existing Quip document"),
+			mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to edit")),
+			s.editContentArg(),
+			mcp.WithString("operation", mcp.Description("Edit operation: REPLACE (default), APPEND, PREPEND"), mcp.Enum("REPLACE", "APPEND", "PREPEND"), mcp.DefaultString("REPLACE")),
+			mcp.WithString("format", mcp.Description("Content format: markdown (default), html"), mcp.Enum("markdown", "html"), mcp.DefaultString(quip.DefaultContentFormat)),
+		}, s.contentFileArg()...)...,
 	)
 
-	s.mcpServer.AddTool(editDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(editDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		documentID, err := req.RequireString("document_id")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
 		}
+		documentID = quip.ResolveThreadID(documentID)
 
-		content, err := req.RequireString("content")
+		content, err := s.contentFromRequest(req, true)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid content argument: %v", err)), nil
 		}
 
 		operation := req.GetString("operation", "REPLACE")
-		format := req.GetString("format", "markdown")
+		content, format, err := s.documentContent(content, req.GetString("format", quip.DefaultContentFormat))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content: %v", err)), nil
+		}
 
-		doc, err := s.quipClient.EditDocument(documentID, content, operation, format)
+		doc, err := s.client(ctx).EditDocument(documentID, content, operation, format)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to edit document: %v", err)), nil
+			return toolError("edit document", err), nil
 		}
 
 		response := "✅ **Document edited successfully!**\n\n"
 		response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
 		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
 		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
-		response += fmt.Sprintf("- **Updated:** %s\n", formatTimestamp(doc.Updated))
+		response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
 
 		return mcp.NewToolResultText(response), nil
 	})
 
+	// Append and prepend tools: narrower alternatives to edit_document that can't replace content
+	s.registerInsertTool("append_to_document", "Add content to the end of an existing Quip document without changing what's already there", "appended to", (*quip.Client).AppendToDocument)
+	s.registerInsertTool("prepend_to_document", "Add content to the start of an existing Quip document without changing what's already there", "prepended to", (*quip.Client).PrependToDocument)
+
 	// Delete document tool
 	deleteDocTool := mcp.NewTool(
 		"delete_document",
-		mcp.WithDescription("Delete a Quip document (requires confirmation)"),
-		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID of the document to delete")),
-		mcp.WithString("confirm", mcp.Required(), mcp.Description("Type 'DELETE' to confirm deletion")),
+		mcp.WithDescription("Delete a Quip document (requires confirmation). By default the document is moved to the trash and can be restored; set permanent to delete it irreversibly."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to delete")),
+		mcp.WithString("confirm", mcp.Required(), mcp.Description(fmt.Sprintf("Type '%s' to move the document to the trash, or '%s' when permanent is true", s.deleteConfirmHint(false), s.deleteConfirmHint(true)))),
+		mcp.WithBoolean("permanent", mcp.Description("Delete the document permanently instead of moving it to the trash (default: false). This cannot be undone.")),
 	)
 
-	s.mcpServer.AddTool(deleteDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.addTool(deleteDocTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		documentID, err := req.RequireString("document_id")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
 		}
+		documentID = quip.ResolveThreadID(documentID)
 
 		confirm, err := req.RequireString("confirm")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid confirm argument: %v", err)), nil
 		}
 
-		if confirm != "DELETE" {
-			return mcp.NewToolResultError("Deletion cancelled. To delete the document, you must set confirm='DELETE'"), nil
+		permanent := req.GetBool("permanent", false)
+
+		// Without a title to check, a wrong confirmation is rejected before contacting Quip
+		if !s.deleteRequiresTitle && confirm != s.deletePhraseFor(permanent) {
+			return mcp.NewToolResultError(s.deleteCancelledMessage(permanent)), nil
 		}
 
 		// Get document info before deletion for confirmation
-		doc, err := s.quipClient.GetDocument(documentID)
+		doc, err := s.client(ctx).GetDocument(documentID)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get document before deletion: %v", err)), nil
+			return toolError("get document before deletion", err), nil
 		}
 
-		err = s.quipClient.DeleteDocument(documentID)
+		if s.deleteRequiresTitle && confirm != strings.TrimSpace(s.deletePhraseFor(permanent)+" "+doc.Title) {
+			return mcp.NewToolResultError(s.deleteCancelledMessage(permanent)), nil
+		}
+
+		err = s.client(ctx).DeleteDocumentWithOptions(documentID, quip.DeleteOptions{Wipeout: permanent})
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete document: %v", err)), nil
+			return toolError("delete document", err), nil
 		}
 
-		response := "🗑️ **Document deleted successfully!**\n\n"
+		response := "🗑️ **Document moved to trash**\n\n"
+		if permanent {
+			response = "🗑️ **Document permanently deleted**\n\n"
+		}
 		response += fmt.Sprintf("- **Deleted Document:** %s\n", doc.Title)
 		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
-		response += "- **Status:** ✅ Permanently deleted\n"
+		if permanent {
+			response += "- **Status:** Permanently deleted; it cannot be restored\n"
+		} else {
+			response += "- **Status:** In the trash; restore it from the Trash folder in Quip\n"
+		}
 
 		return mcp.NewToolResultText(response), nil
 	})
@@ -302,17 +745,31 @@ func (s *Server) registerTools() {
 	getRecentTool := mcp.NewTool(
 		"get_recent_threads",
 		mcp.WithDescription("Get recent Quip threads for the current user"),
-		mcp.WithNumber("limit", mcp.Description("Maximum number of recent threads to retrieve (default: 10)")),
+		limitArg("Maximum number of recent threads to retrieve", s.recentLimit),
+		mcp.WithString("type", mcp.Description("Only return threads of this type (default: all)"), mcp.Enum("all", "document", "spreadsheet", "chat")),
+		mcp.WithNumber("max_updated_usec", mcp.Description("Cursor from a previous call: only return threads updated before this timestamp")),
+		includeTrashedArg(),
 	)
 
-	s.mcpServer.AddTool(getRecentTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		limit := req.GetInt("limit", 10)
+	s.addTool(getRecentTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limit, err := quip.NormalizeLimit(req.GetInt("limit", s.recentLimit))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid limit argument: %v", err)), nil
+		}
+
+		opts := quip.RecentOptions{
+			Limit:          limit,
+			Type:           req.GetString("type", "all"),
+			MaxUpdatedUsec: int64(req.GetFloat("max_updated_usec", 0)),
+			IncludeTrashed: req.GetBool("include_trashed", false),
+		}
 
-		threads, err := s.quipClient.GetRecentThreads(limit)
+		page, err := s.client(ctx).GetRecentThreadsWithOptions(opts)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get recent threads: %v", err)), nil
+			return toolError("get recent threads", err), nil
 		}
 
+		threads := page.Threads
 		if len(threads) == 0 {
 			return mcp.NewToolResultText("No recent threads found."), nil
 		}
@@ -323,13 +780,343 @@ func (s *Server) registerTools() {
 			response += fmt.Sprintf("   - ID: %s\n", thread.ID)
 			response += fmt.Sprintf("   - Type: %s\n", thread.Type)
 			response += fmt.Sprintf("   - Link: %s\n", thread.Link)
-			response += fmt.Sprintf("   - Updated: %s\n\n", formatTimestamp(thread.Updated))
+			response += trashedNote(thread)
+			response += fmt.Sprintf("   - Updated: %s\n\n", s.formatTimestamp(thread.Updated))
+		}
+
+		if page.NextCursor > 0 {
+			response += fmt.Sprintf("More threads available. Use max_updated_usec=%d to fetch older threads.\n", page.NextCursor)
 		}
 
 		return mcp.NewToolResultText(response), nil
 	})
 
-	log.Println("✅ All MCP tools registered successfully")
+	s.registerSpreadsheetTools()
+	s.registerFollowTools()
+	s.registerCommentTools()
+	s.registerConversionTools()
+	s.registerBatchTools()
+	s.registerHistoryTools()
+	s.registerDiffTools()
+	s.registerFolderTools()
+	s.registerContactTools()
+	s.registerLockTools()
+	s.registerStatsTools()
+	s.registerThreadTools()
+	s.registerTemplateTools()
+	s.registerRenameTools()
+	s.registerRecentEditsTools()
+	s.registerExportTools()
+	s.registerSectionTools()
+
+	s.warnUnknownTools()
+	s.logger.Debug("All MCP tools registered")
+}
+
+// includeTrashedArg declares the include_trashed argument of the listing tools
+func includeTrashedArg() mcp.ToolOption {
+	return mcp.WithBoolean("include_trashed", mcp.Description("Include threads that are in the trash (default: false)"))
+}
+
+// trashedNote marks a listed thread that is in the trash
+func trashedNote(doc quip.Document) string {
+	if !doc.IsDeleted {
+		return ""
+	}
+	return "   - In Trash: yes\n"
+}
+
+// Content formats accepted by get_document's content_format argument
+const (
+	contentFormatMarkdown = "markdown"
+	contentFormatHTML     = "html"
+	contentFormatBoth     = "both"
+)
+
+// contentFormatArg declares the content_format argument of the document reading tools
+func contentFormatArg() mcp.ToolOption {
+	return mcp.WithString("content_format",
+		mcp.Description("How to return the content: markdown (default, readable), html (Quip's original markup, for faithfully re-editing complex formatting) or both"),
+		mcp.Enum(contentFormatMarkdown, contentFormatHTML, contentFormatBoth),
+	)
+}
+
+// createFolderNote describes create_document's folder_id argument, naming the configured
+// default folder if there is one
+func (s *Server) createFolderNote() string {
+	if folderID := s.quipClient.DefaultFolderID(); folderID != "" {
+		return fmt.Sprintf("The ID or URL of the folder to create the document in (default: the configured folder %s)", folderID)
+	}
+	return "The ID or URL of the folder to create the document in (default: your private folder)"
+}
+
+// createFormatNote describes create_document's format argument. Only client-side conversion
+// turns markdown task lists into Quip checklists; Quip's own import leaves them as "[ ]" text.
+func (s *Server) createFormatNote() string {
+	if s.markdownConversion == MarkdownConversionServer {
+		return "Content format: markdown (default; rendered by Quip, task lists stay plain text), html"
+	}
+	return "Content format: markdown (default; task lists become Quip checklists), html"
+}
+
+// contentFormatFrom returns the validated content_format argument of req
+func contentFormatFrom(req mcp.CallToolRequest) (string, error) {
+	contentFormat := req.GetString("content_format", contentFormatMarkdown)
+	switch contentFormat {
+	case contentFormatMarkdown, contentFormatHTML, contentFormatBoth:
+		return contentFormat, nil
+	default:
+		return "", fmt.Errorf("%q must be markdown, html or both", contentFormat)
+	}
+}
+
+// formatDocument renders a document's metadata and content in contentFormat. A non-zero
+// offset or maxChars returns only that chunk of the content (see chunkContent); chunking
+// isn't supported for contentFormatBoth.
+func (s *Server) formatDocument(doc *quip.Document, names map[string]string, contentFormat string, offset, maxChars int) (string, error) {
+	response := fmt.Sprintf("**%s**\n\n", doc.Title)
+	response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+	response += fmt.Sprintf("- **Type:** %s\n", doc.Type)
+	response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+	response += fmt.Sprintf("- **Author:** %s\n", documentAuthor(doc))
+	response += fmt.Sprintf("- **Created:** %s\n", s.formatTimestamp(doc.Created))
+	response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+	response += fmt.Sprintf("- **Access Level:** %s\n", doc.AccessLevel)
+	response += formatAccessLevels(doc.AccessLevelsByID(), names)
+
+	// Chat threads and freshly created threads come back without HTML; say so, so an empty
+	// body isn't mistaken for a failed fetch or conversion
+	if strings.TrimSpace(doc.HTML) == "" {
+		threadType := doc.Type
+		if threadType == "" {
+			threadType = "unknown"
+		}
+		response += fmt.Sprintf("\n_This thread has no document content (thread type: %s)._\n", threadType)
+		return response, nil
+	}
+
+	markdown := s.documentMarkdown(doc)
+	response += fmt.Sprintf("- **Size:** %s\n", formatDocumentStats(computeDocumentStats(doc.HTML, markdown)))
+
+	if maxChars == 0 && offset == 0 {
+		if contentFormat != contentFormatHTML {
+			response += fmt.Sprintf("\n**Content:**\n%s\n", markdown)
+		}
+		if contentFormat == contentFormatHTML || contentFormat == contentFormatBoth {
+			response += fmt.Sprintf("\n**HTML:**\n```html\n%s\n```\n", doc.HTML)
+		}
+		return response, nil
+	}
+
+	content := markdown
+	switch contentFormat {
+	case contentFormatHTML:
+		content = doc.HTML
+	case contentFormatBoth:
+		return "", fmt.Errorf("offset and max_chars can't be used with content_format=both; request markdown or html")
+	}
+	chunk, err := chunkContent(content, offset, maxChars)
+	if err != nil {
+		return "", err
+	}
+	response += chunk
+
+	return response, nil
+}
+
+// getDocumentWithPeople retrieves a document with its author and members embedded, so their
+// names need no separate lookups. If the people can't be fetched the document is returned
+// without them.
+func getDocumentWithPeople(client *quip.Client, documentID string) (*quip.Document, error) {
+	if doc, err := client.GetDocumentExpanded(documentID); err == nil {
+		return doc, nil
+	}
+	return client.GetDocument(documentID)
+}
+
+// documentAuthor names a document's author when it was embedded, keeping the ID either way
+func documentAuthor(doc *quip.Document) string {
+	if author, ok := doc.Author(); ok && author.Name != "" {
+		return fmt.Sprintf("%s (%s)", author.Name, doc.AuthorID)
+	}
+	return doc.AuthorID
+}
+
+// accessLevelNames resolves the users in doc's access levels to display names. Users embedded
+// in an expanded response are used as they are; only the others are looked up.
+func accessLevelNames(client *quip.Client, doc *quip.Document) map[string]string {
+	levels := doc.AccessLevelsByID()
+	names := make(map[string]string, len(levels))
+	var ids []string
+	for id := range levels {
+		if user, ok := doc.Users[id]; ok && user.Name != "" {
+			names[id] = user.Name
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return names
+	}
+
+	for id, name := range resolveAuthorNames(client, ids) {
+		names[id] = name
+	}
+	return names
+}
+
+// formatAccessLevels renders per-user access levels as a nested list sorted by name.
+// IDs missing from names (groups, deleted users) are shown as-is.
+func formatAccessLevels(levels, names map[string]string) string {
+	if len(levels) == 0 {
+		return ""
+	}
+
+	type entry struct{ name, level string }
+	entries := make([]entry, 0, len(levels))
+	for id, level := range levels {
+		name := id
+		if resolved, ok := names[id]; ok {
+			name = fmt.Sprintf("%s (%s)", resolved, id)
+		}
+		entries = append(entries, entry{name: name, level: level})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].name) < strings.ToLower(entries[j].name)
+	})
+
+	response := "- **Access Levels:**\n"
+	for _, e := range entries {
+		response += fmt.Sprintf("  - %s: %s\n", e.name, e.level)
+	}
+	return response
+}
+
+// deletePhraseFor returns the confirmation phrase for trashing or permanently deleting
+func (s *Server) deletePhraseFor(permanent bool) string {
+	if permanent {
+		return "PERMANENTLY " + s.deletePhrase
+	}
+	return s.deletePhrase
+}
+
+// deleteConfirmHint describes the confirm value delete_document expects
+func (s *Server) deleteConfirmHint(permanent bool) string {
+	if s.deleteRequiresTitle {
+		return s.deletePhraseFor(permanent) + " <exact document title>"
+	}
+	return s.deletePhraseFor(permanent)
+}
+
+// deleteCancelledMessage explains which confirmation delete_document expected
+func (s *Server) deleteCancelledMessage(permanent bool) string {
+	if permanent {
+		return fmt.Sprintf("Deletion cancelled. Permanent deletion cannot be undone; to proceed you must set confirm='%s'", s.deleteConfirmHint(true))
+	}
+	return fmt.Sprintf("Deletion cancelled. To move the document to the trash, you must set confirm='%s'", s.deleteConfirmHint(false))
+}
+
+// contentLimitNote describes the client's content size limit for tool descriptions
+func (s *Server) contentLimitNote() string {
+	if limit := s.quipClient.MaxContentBytes(); limit > 0 {
+		return fmt.Sprintf(" (at most %d bytes)", limit)
+	}
+	return ""
+}
+
+// lookupUser resolves a user argument, which may be a user ID, an email address or "current"
+func lookupUser(client *quip.Client, userID string) (*quip.User, error) {
+	userID = strings.TrimSpace(userID)
+	switch {
+	case userID == "current":
+		return client.GetCurrentUser()
+	case strings.Contains(userID, "@"):
+		return client.ResolveUserByEmail(userID)
+	default:
+		return client.GetUser(userID)
+	}
+}
+
+// formatUser renders a user's profile. The optional fields are only shown when Quip filled them in.
+func (s *Server) formatUser(user *quip.User) string {
+	response := fmt.Sprintf("**%s**\n\n", user.Name)
+	response += fmt.Sprintf("- **ID:** %s\n", user.ID)
+	response += fmt.Sprintf("- **Email:** %s\n", user.Email)
+	if others := otherEmails(user); len(others) > 0 {
+		response += fmt.Sprintf("- **Other Emails:** %s\n", strings.Join(others, ", "))
+	}
+	response += fmt.Sprintf("- **Profile URL:** %s\n", user.URL)
+	response += fmt.Sprintf("- **Created:** %s\n", s.formatTimestamp(user.Created))
+	response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(user.Updated))
+
+	if user.ProfilePic != "" {
+		response += fmt.Sprintf("- **Profile Picture:** %s\n", user.ProfilePic)
+	}
+	if user.Affinity > 0 {
+		response += fmt.Sprintf("- **Affinity:** %.2f (how closely you work with them; higher is closer)\n", user.Affinity)
+	}
+	if user.ChatOnly {
+		response += "- **Chat-only account:** yes (usually a bot or integration, not a person)\n"
+	}
+	if user.Desktop {
+		response += "- **Uses the desktop app:** yes\n"
+	}
+
+	return response
+}
+
+// otherEmails returns the user's email addresses besides the primary one
+func otherEmails(user *quip.User) []string {
+	var others []string
+	for _, email := range user.Emails {
+		if email != "" && !strings.EqualFold(email, user.Email) {
+			others = append(others, email)
+		}
+	}
+	return others
+}
+
+// registerInsertTool registers a tool that adds content to one end of a document using insert.
+// verb describes where the content went, e.g. "appended to".
+func (s *Server) registerInsertTool(name, description, verb string, insert func(c *quip.Client, documentID, content, format string) (*quip.Document, error)) {
+	tool := mcp.NewTool(
+		name,
+		mcp.WithDescription(description),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document to add to")),
+		mcp.WithString("content", mcp.Required(), mcp.Description("The content to add"+s.contentLimitNote())),
+		mcp.WithString("format", mcp.Description("Content format: markdown (default), html"), mcp.Enum("markdown", "html"), mcp.DefaultString(quip.DefaultContentFormat)),
+	)
+
+	s.addTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		content, err := req.RequireString("content")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content argument: %v", err)), nil
+		}
+
+		content, format, err := s.documentContent(content, req.GetString("format", quip.DefaultContentFormat))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content: %v", err)), nil
+		}
+
+		doc, err := insert(s.client(ctx), documentID, content, format)
+		if err != nil {
+			return toolError("edit document", err), nil
+		}
+
+		response := fmt.Sprintf("✅ **Content %s document!**\n\n", verb)
+		response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+		response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+
+		return mcp.NewToolResultText(response), nil
+	})
 }
 
 // registerResources registers MCP resources
@@ -343,7 +1130,7 @@ func (s *Server) registerResources() {
 	)
 
 	s.mcpServer.AddResource(currentUserResource, func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		user, err := s.quipClient.GetCurrentUser()
+		user, err := s.client(ctx).GetCurrentUser()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get current user: %w", err)
 		}
@@ -366,54 +1153,74 @@ func (s *Server) registerResources() {
 		}, nil
 	})
 
-	log.Println("✅ All MCP resources registered successfully")
+	s.logger.Debug("All MCP resources registered")
 }
 
-// formatTimestamp converts a Unix timestamp (microseconds) to a readable format
-func formatTimestamp(timestamp int64) string {
-	if timestamp == 0 {
-		return "Unknown"
-	}
-	// Convert microseconds to seconds
-	seconds := timestamp / 1000000
-	return strconv.FormatInt(seconds, 10)
+// limitArg declares a "limit" argument constrained to a whole number between 1 and
+// quip.MaxLimit, so clients can reject bad values before calling. Handlers still pass
+// limits through quip.NormalizeLimit, since clients aren't obliged to validate.
+func limitArg(description string, defaultLimit int) mcp.ToolOption {
+	return mcp.WithNumber("limit",
+		mcp.Description(fmt.Sprintf("%s (default: %d, max: %d)", description, defaultLimit, quip.MaxLimit)),
+		mcp.Min(1),
+		mcp.Max(quip.MaxLimit),
+		mcp.MultipleOf(1),
+		mcp.DefaultNumber(float64(defaultLimit)),
+	)
 }
 
-// Helper function to truncate text
-func truncateText(text string, maxLength int) string {
-	if len(text) <= maxLength {
-		return text
+// parseDateArg parses a YYYY-MM-DD or RFC 3339 tool argument. A bare date is taken as the
+// start of that day, or its end when endOfDay is set. An empty value yields the zero time.
+func parseDateArg(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
 	}
-	return strings.TrimSpace(text[:maxLength]) + "..."
-}
 
-// htmlToMarkdown converts HTML content to clean markdown
-func htmlToMarkdown(htmlContent string) string {
-	converter := md.NewConverter("", true, nil)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
 
-	markdown, err := converter.ConvertString(htmlContent)
+	t, err := time.Parse(time.DateOnly, value)
 	if err != nil {
-		// If conversion fails, return a cleaned version of the HTML
-		cleaned := strings.ReplaceAll(htmlContent, "<", "&lt;")
-		cleaned = strings.ReplaceAll(cleaned, ">", "&gt;")
-		return cleaned
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// chunkContent renders the slice of content starting at offset (in characters) and at most
+// maxChars long (0 means no limit), noting where the next chunk starts if content remains
+func chunkContent(content string, offset, maxChars int) (string, error) {
+	total := len([]rune(content))
+	if offset > total {
+		return "", fmt.Errorf("offset %d is past the end of the content (%d characters)", offset, total)
 	}
 
-	// Clean up the markdown
-	markdown = strings.ReplaceAll(markdown, "&amp;", "&")
-	markdown = strings.ReplaceAll(markdown, "&lt;", "<")
-	markdown = strings.ReplaceAll(markdown, "&gt;", ">")
-	markdown = strings.ReplaceAll(markdown, "&nbsp;", " ")
+	rest := string([]rune(content)[offset:])
+	end := total
+	truncated := maxChars > 0 && total-offset > maxChars
+	if truncated {
+		end = offset + maxChars
+		rest = truncateText(rest, maxChars)
+	}
 
-	// Remove excessive newlines
-	lines := strings.Split(markdown, "\n")
-	var cleanLines []string
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" || (len(cleanLines) > 0 && cleanLines[len(cleanLines)-1] != "") {
-			cleanLines = append(cleanLines, trimmed)
-		}
+	response := fmt.Sprintf("\n**Content** (characters %d-%d of %d):\n%s\n", offset, end, total, rest)
+	if truncated {
+		response += fmt.Sprintf("\n- **Truncated:** yes\n- **Next offset:** %d (call get_document again with offset=%d to continue)\n", end, end)
+	} else {
+		response += "\n- **Truncated:** no\n"
 	}
 
-	return strings.Join(cleanLines, "\n")
+	return response, nil
+}
+
+// truncateText shortens text to at most maxLength characters, marking the cut with an ellipsis
+func truncateText(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxLength])) + "..."
 }
@@ -1,7 +1,21 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func TestNew(t *testing.T) {
@@ -22,8 +36,18 @@ func TestNew(t *testing.T) {
 }
 
 func TestFormatTimestamp(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
 	tests := []struct {
 		name      string
+		opts      []Option
 		timestamp int64
 		expected  string
 	}{
@@ -33,25 +57,36 @@ func TestFormatTimestamp(t *testing.T) {
 			expected:  "Unknown",
 		},
 		{
-			name:      "valid timestamp",
+			name:      "default UTC",
 			timestamp: 1640995200000000, // 2022-01-01 00:00:00 UTC in microseconds
-			expected:  "1640995200",     // Same in seconds
+			expected:  "Jan 1, 2022 00:00 UTC",
 		},
 		{
-			name:      "another valid timestamp",
+			name:      "Tokyo",
+			opts:      []Option{WithTimeFormat(tokyo, "")},
+			timestamp: 1640995200000000,
+			expected:  "Jan 1, 2022 09:00 JST",
+		},
+		{
+			name:      "New York with custom layout",
+			opts:      []Option{WithTimeFormat(newYork, "2006-01-02 15:04 MST")},
 			timestamp: 1609459200000000, // 2021-01-01 00:00:00 UTC in microseconds
-			expected:  "1609459200",     // Same in seconds
+			expected:  "2020-12-31 19:00 EST",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatTimestamp(tt.timestamp)
+			result := New("test-token", tt.opts...).formatTimestamp(tt.timestamp)
 			if result != tt.expected {
 				t.Errorf("formatTimestamp(%d) = %s, expected %s", tt.timestamp, result, tt.expected)
 			}
 		})
 	}
+
+	if got := New("test-token").formatDate(0); got != "Unknown date" {
+		t.Errorf("formatDate(0) = %s, expected Unknown date", got)
+	}
 }
 
 func TestTruncateText(t *testing.T) {
@@ -85,6 +120,12 @@ func TestTruncateText(t *testing.T) {
 			maxLength: 10,
 			expected:  "Hello Worl...",
 		},
+		{
+			name:      "multi-byte characters",
+			text:      "héllo wörld",
+			maxLength: 7,
+			expected:  "héllo w...",
+		},
 	}
 
 	for _, tt := range tests {
@@ -97,6 +138,79 @@ func TestTruncateText(t *testing.T) {
 	}
 }
 
+func TestChunkContent(t *testing.T) {
+	content := "abcdefghij"
+
+	chunk, err := chunkContent(content, 0, 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(chunk, "abcd...") || !strings.Contains(chunk, "characters 0-4 of 10") {
+		t.Errorf("Expected first chunk of 4 characters, got %q", chunk)
+	}
+	if !strings.Contains(chunk, "**Next offset:** 4") {
+		t.Errorf("Expected next offset 4, got %q", chunk)
+	}
+
+	chunk, err = chunkContent(content, 8, 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(chunk, "\nij\n") || !strings.Contains(chunk, "**Truncated:** no") {
+		t.Errorf("Expected final chunk 'ij' without truncation, got %q", chunk)
+	}
+
+	if _, err := chunkContent(content, 11, 4); err == nil {
+		t.Error("Expected error for offset past the end, got nil")
+	}
+}
+
+func TestFormatDocument_ContentFormat(t *testing.T) {
+	doc := &quip.Document{ID: "doc123", Title: "Plan", HTML: "<p id=\"a1\">Ship <b>it</b></p>"}
+	s := New("test-token")
+
+	tests := []struct {
+		format  string
+		want    []string
+		notWant []string
+	}{
+		{format: contentFormatMarkdown, want: []string{"**Content:**\nShip **it**"}, notWant: []string{"**HTML:**"}},
+		{format: contentFormatHTML, want: []string{"**HTML:**\n```html\n<p id=\"a1\">Ship <b>it</b></p>\n```"}, notWant: []string{"**Content:**"}},
+		{format: contentFormatBoth, want: []string{"**Content:**\nShip **it**", "**HTML:**\n```html\n<p id=\"a1\">"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			response, err := s.formatDocument(doc, nil, tt.format, 0, 0)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(response, want) {
+					t.Errorf("Expected response to contain %q, got %q", want, response)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(response, notWant) {
+					t.Errorf("Expected response not to contain %q, got %q", notWant, response)
+				}
+			}
+		})
+	}
+
+	chunk, err := s.formatDocument(doc, nil, contentFormatHTML, 0, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(chunk, "<p id...") {
+		t.Errorf("Expected a chunk of the HTML, got %q", chunk)
+	}
+
+	if _, err := s.formatDocument(doc, nil, contentFormatBoth, 0, 5); err == nil {
+		t.Error("Expected an error when chunking both formats, got nil")
+	}
+}
+
 // TestQuipClientIntegration tests that the server correctly integrates with the Quip client
 func TestQuipClientIntegration(t *testing.T) {
 	// This is an integration test that verifies the server properly wraps the Quip client
@@ -111,3 +225,584 @@ func TestQuipClientIntegration(t *testing.T) {
 	// We can't test the actual API calls without a real token and internet connection,
 	// but we can verify the structure is correct
 }
+
+func TestServer_Stop(t *testing.T) {
+	server := New("test-token")
+
+	// A pipe that never receives input keeps the transport blocked until Stop
+	in, w := io.Pipe()
+	defer w.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.serve(context.Background(), in, io.Discard)
+	}()
+
+	// Wait for serve to register its cancel function
+	deadline := time.Now().Add(time.Second)
+	for {
+		server.mu.Lock()
+		ready := server.cancel != nil
+		server.mu.Unlock()
+		if ready || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	server.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected clean shutdown, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected serve to return after Stop")
+	}
+}
+
+func TestServer_DrainWaitsForInFlightCalls(t *testing.T) {
+	server := New("test-token")
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := server.trackInFlight(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		if ctx.Err() != nil {
+			t.Errorf("Expected in-flight call context to survive shutdown, got %v", ctx.Err())
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	// The call's context ends because the transport is shutting down
+	ctx, cancel := context.WithCancel(context.Background())
+	server.serveCtx = ctx
+	go func() {
+		_, _ = handler(ctx, mcp.CallToolRequest{})
+	}()
+	<-started
+	cancel()
+
+	if server.drain(20 * time.Millisecond) {
+		t.Fatal("Expected drain to time out while a call is running")
+	}
+
+	close(release)
+
+	if !server.drain(time.Second) {
+		t.Fatal("Expected drain to complete once the call finished")
+	}
+
+	result, _ := handler(context.Background(), mcp.CallToolRequest{})
+	if !result.IsError {
+		t.Error("Expected new calls to be rejected while draining")
+	}
+}
+
+func TestServer_TrackInFlightPassesClientCancellation(t *testing.T) {
+	server := New("test-token")
+	serveCtx, stopServing := context.WithCancel(context.Background())
+	defer stopServing()
+	server.serveCtx = serveCtx
+
+	handler := server.trackInFlight(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return mcp.NewToolResultError("cancelled"), nil
+		case <-time.After(2 * time.Second):
+			return mcp.NewToolResultText("done"), nil
+		}
+	})
+
+	// The client cancels the call while the server keeps running
+	ctx, cancel := context.WithCancel(serveCtx)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if result, _ := handler(ctx, mcp.CallToolRequest{}); !result.IsError {
+		t.Error("Expected a call cancelled by its client to see the cancellation")
+	}
+}
+
+func TestServer_LogToolCall(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	server := New("test-token", WithLogger(logger))
+
+	handler := server.logToolCall(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "search_documents"
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "tool=search_documents") {
+		t.Errorf("Expected log to contain tool name, got %q", output)
+	}
+	if !strings.Contains(output, "duration=") {
+		t.Errorf("Expected log to contain duration, got %q", output)
+	}
+}
+
+func TestServer_StdoutStaysClean(t *testing.T) {
+	// Capture the process stdout while the server is built and serves a session
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	origStdout := os.Stdout
+	os.Stdout = stdoutW
+	defer func() { os.Stdout = origStdout }()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	server := New("test-token", WithLogger(logger))
+
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}` + "\n")
+	var out bytes.Buffer
+	if err := server.serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("Expected serve to finish cleanly, got %v", err)
+	}
+
+	os.Stdout = origStdout
+	_ = stdoutW.Close()
+	captured, _ := io.ReadAll(stdoutR)
+
+	if len(captured) != 0 {
+		t.Errorf("Expected nothing written to stdout, got %q", captured)
+	}
+	if !strings.Contains(out.String(), `"id":1`) {
+		t.Errorf("Expected initialize response on the transport, got %q", out.String())
+	}
+}
+
+func TestParseDateArg(t *testing.T) {
+	tests := []struct {
+		value    string
+		endOfDay bool
+		expected time.Time
+		wantErr  bool
+	}{
+		{value: "", expected: time.Time{}},
+		{value: "2025-01-08", expected: time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{value: "2025-01-08", endOfDay: true, expected: time.Date(2025, 1, 8, 23, 59, 59, 999999999, time.UTC)},
+		{value: "2025-01-08T10:30:00Z", endOfDay: true, expected: time.Date(2025, 1, 8, 10, 30, 0, 0, time.UTC)},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDateArg(tt.value, tt.endOfDay)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseDateArg(%q) expected error, got nil", tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDateArg(%q) returned error %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("parseDateArg(%q, %v) = %v, expected %v", tt.value, tt.endOfDay, got, tt.expected)
+		}
+	}
+}
+
+func TestServer_DeleteDocument(t *testing.T) {
+	var wipeouts []string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/threads/delete" {
+			wipeouts = append(wipeouts, r.FormValue("wipeout"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Plan"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		isError bool
+		want    string
+		wipeout string
+	}{
+		{name: "trash", args: map[string]any{"confirm": "DELETE"}, want: "moved to trash", wipeout: "false"},
+		{name: "permanent", args: map[string]any{"confirm": "PERMANENTLY DELETE", "permanent": true}, want: "permanently deleted", wipeout: "true"},
+		{name: "permanent needs stronger confirmation", args: map[string]any{"confirm": "DELETE", "permanent": true}, isError: true, want: "PERMANENTLY DELETE"},
+		{name: "missing confirmation", args: map[string]any{"confirm": "yes"}, isError: true, want: "confirm='DELETE'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wipeouts = nil
+			tt.args["document_id"] = "doc123"
+
+			text, isError := callTool(t, s, "delete_document", tt.args)
+			if isError != tt.isError {
+				t.Fatalf("Expected isError=%v, got %v: %q", tt.isError, isError, text)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("Expected result to contain %q, got %q", tt.want, text)
+			}
+
+			if tt.isError {
+				if len(wipeouts) != 0 {
+					t.Errorf("Expected no delete request, got %v", wipeouts)
+				}
+			} else if len(wipeouts) != 1 || wipeouts[0] != tt.wipeout {
+				t.Errorf("Expected one delete with wipeout=%s, got %v", tt.wipeout, wipeouts)
+			}
+		})
+	}
+}
+
+func TestServer_DeleteConfirmation(t *testing.T) {
+	var deletes int
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/threads/delete" {
+			deletes++
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Q3 Roadmap"}})
+	}))
+	defer quipServer.Close()
+
+	tests := []struct {
+		name         string
+		requireTitle bool
+		args         map[string]any
+		isError      bool
+		want         string
+	}{
+		{name: "custom phrase", args: map[string]any{"confirm": "YES, DELETE"}, want: "moved to trash"},
+		{name: "default phrase no longer accepted", args: map[string]any{"confirm": "DELETE"}, isError: true, want: "confirm='YES, DELETE'"},
+		{name: "permanent custom phrase", args: map[string]any{"confirm": "PERMANENTLY YES, DELETE", "permanent": true}, want: "permanently deleted"},
+		{name: "title required", requireTitle: true, args: map[string]any{"confirm": "YES, DELETE Q3 Roadmap"}, want: "moved to trash"},
+		{name: "wrong title", requireTitle: true, args: map[string]any{"confirm": "YES, DELETE Q4 Roadmap"}, isError: true, want: "confirm='YES, DELETE <exact document title>'"},
+		{name: "title missing", requireTitle: true, args: map[string]any{"confirm": "YES, DELETE"}, isError: true, want: "exact document title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletes = 0
+			tt.args["document_id"] = "doc123"
+
+			s := New("test-token",
+				WithDeleteConfirmation("YES, DELETE", tt.requireTitle),
+				WithClientOptions(quip.WithBaseURL(quipServer.URL)),
+			)
+
+			text, isError := callTool(t, s, "delete_document", tt.args)
+			if isError != tt.isError {
+				t.Fatalf("Expected isError=%v, got %v: %q", tt.isError, isError, text)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("Expected result to contain %q, got %q", tt.want, text)
+			}
+
+			wantDeletes := 1
+			if tt.isError {
+				wantDeletes = 0
+			}
+			if deletes != wantDeletes {
+				t.Errorf("Expected %d delete requests, got %d", wantDeletes, deletes)
+			}
+		})
+	}
+}
+
+func TestServer_DefaultLimits(t *testing.T) {
+	var counts []string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counts = append(counts, r.URL.Path+"?count="+r.URL.Query().Get("count"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithDefaultLimits(25, 40), WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	callTool(t, s, "search_documents", map[string]any{"query": "plan"})
+	callTool(t, s, "get_recent_threads", map[string]any{})
+	callTool(t, s, "search_documents", map[string]any{"query": "plan", "limit": 3})
+
+	// The configured defaults apply only when the agent doesn't pass a limit
+	expected := []string{"/threads/search?count=25", "/threads/recent?count=40", "/threads/search?count=3"}
+	if strings.Join(counts, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected requests %v, got %v", expected, counts)
+	}
+}
+
+func TestServer_GetDocumentByURL(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/AbCdEfGhIjK" {
+			t.Errorf("Expected the thread ID from the link, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "AbCdEfGhIjK", Title: "Roadmap", HTML: "<p>Q3 goals</p>"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_document_by_url", map[string]any{"url": "https://acme.quip.com/AbCdEfGhIjK/Roadmap?utm_source=slack"})
+	if isError || !strings.Contains(text, "**Roadmap**") || !strings.Contains(text, "Q3 goals") {
+		t.Errorf("Expected the linked document, got %q", text)
+	}
+
+	text, isError = callTool(t, s, "get_document_by_url", map[string]any{"url": "https://example.com/AbCdEfGhIjK"})
+	if !isError || !strings.Contains(text, "not a Quip host") {
+		t.Errorf("Expected an error for a non-Quip link, got %q", text)
+	}
+}
+
+func TestServer_GetDocument_EmptyContent(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "chat123", Title: "Team chat", Type: "chat"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_document", map[string]any{"document_id": "chat123"})
+	if isError {
+		t.Fatalf("Expected the thread's metadata, got error %q", text)
+	}
+	if !strings.Contains(text, "no document content (thread type: chat)") {
+		t.Errorf("Expected a note that the thread has no content, got %q", text)
+	}
+	if strings.Contains(text, "**Content:**") || strings.Contains(text, "**Size:**") {
+		t.Errorf("Expected no content section for an empty thread, got %q", text)
+	}
+}
+
+func TestServer_GetDocument_ExpandedPeople(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/threads/doc1" || r.URL.Query().Get("expand") == "" {
+			t.Errorf("Expected a single expanded thread request, got %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"thread": {"id": "doc1", "title": "Roadmap", "author_id": "ann"},
+			"html": "<p>Plan</p>",
+			"access_levels": {"ann": {"access_level": "OWN"}, "bob": {"access_level": "EDIT"}},
+			"expanded_user_ids": ["ann", "bob"],
+			"users": {"ann": {"id": "ann", "name": "Ann Lee"}, "bob": {"id": "bob", "name": "Bob Roy"}}
+		}`))
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_document", map[string]any{"document_id": "doc1"})
+	if isError {
+		t.Fatalf("get_document failed: %s", text)
+	}
+	for _, want := range []string{"- **Author:** Ann Lee (ann)\n", "  - Ann Lee (ann): OWN\n", "  - Bob Roy (bob): EDIT\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q from the embedded users, got %q", want, text)
+		}
+	}
+}
+
+func TestFormatAccessLevels(t *testing.T) {
+	levels := map[string]string{"user1": "OWN", "user2": "VIEW", "group1": "EDIT"}
+	names := map[string]string{"user1": "Zoe Owner", "user2": "Ann Viewer"}
+
+	expected := "- **Access Levels:**\n" +
+		"  - Ann Viewer (user2): VIEW\n" +
+		"  - group1: EDIT\n" +
+		"  - Zoe Owner (user1): OWN\n"
+	if got := formatAccessLevels(levels, names); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if got := formatAccessLevels(nil, names); got != "" {
+		t.Errorf("Expected nothing without access levels, got %q", got)
+	}
+}
+
+func TestServer_SearchDocuments_IncludeTrashed(t *testing.T) {
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]quip.SearchResponse{
+			{Thread: quip.Document{ID: "live", Title: "Live notes"}},
+			{Thread: quip.Document{ID: "trashed", Title: "Old notes", IsDeleted: true}},
+		})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, _ := callTool(t, s, "search_documents", map[string]any{"query": "notes"})
+	if !strings.Contains(text, "Found 1 documents") || strings.Contains(text, "Old notes") {
+		t.Errorf("Expected trashed threads to be left out by default, got %q", text)
+	}
+
+	text, _ = callTool(t, s, "search_documents", map[string]any{"query": "notes", "include_trashed": true})
+	if !strings.Contains(text, "Found 2 documents") || !strings.Contains(text, "In Trash: yes") {
+		t.Errorf("Expected the trashed thread to be included and marked, got %q", text)
+	}
+}
+
+func TestServer_EditDocument_DefaultFormat(t *testing.T) {
+	var formats []string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		formats = append(formats, r.FormValue("format"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Plan"}})
+	}))
+	defer quipServer.Close()
+
+	// Without client-side conversion the tool passes its format through like the client does
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)), WithMarkdownConversion(MarkdownConversionServer))
+
+	// The format the tool advertises as its default is the one Quip receives when it's omitted
+	var declared any
+	for _, tool := range listToolDefinitions(t, s) {
+		if tool.Name == "edit_document" {
+			format, _ := tool.InputSchema.Properties["format"].(map[string]any)
+			declared = format["default"]
+		}
+	}
+	if declared != quip.DefaultContentFormat {
+		t.Errorf("Expected edit_document to declare format default %q, got %v", quip.DefaultContentFormat, declared)
+	}
+
+	text, isError := callTool(t, s, "edit_document", map[string]any{"document_id": "doc123", "content": "# Plan"})
+	if isError {
+		t.Fatalf("Expected the edit to succeed, got %q", text)
+	}
+
+	client := quip.NewClient("test-token", quip.WithBaseURL(quipServer.URL))
+	if _, err := client.EditDocument("doc123", "# Plan", "APPEND", ""); err != nil {
+		t.Fatalf("EditDocument failed: %v", err)
+	}
+
+	if len(formats) != 2 || formats[0] != quip.DefaultContentFormat || formats[1] != quip.DefaultContentFormat {
+		t.Errorf("Expected both the tool and the client to send format=%s, got %v", quip.DefaultContentFormat, formats)
+	}
+}
+
+func TestServer_CreateDocument_DefaultFolder(t *testing.T) {
+	var folders []string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		folders = append(folders, r.FormValue("member_ids"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Notes"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL), quip.WithDefaultFolder("team")))
+
+	text, isError := callTool(t, s, "create_document", map[string]any{"title": "Notes"})
+	if isError || !strings.Contains(text, "- **Folder:** team") {
+		t.Errorf("Expected the document in the default folder, got %q", text)
+	}
+	text, isError = callTool(t, s, "create_document", map[string]any{"title": "Notes", "folder_id": "https://quip.com/mine"})
+	if isError || !strings.Contains(text, "- **Folder:** mine") {
+		t.Errorf("Expected the document in the given folder, got %q", text)
+	}
+
+	if len(folders) != 2 || folders[0] != "team" || folders[1] != "mine" {
+		t.Errorf("Expected the default folder only when none is given, got %v", folders)
+	}
+}
+
+func TestServer_MarkdownConversion(t *testing.T) {
+	const markdown = "# Plan\n\n- [ ] Ship it"
+
+	tests := []struct {
+		mode            string
+		expectedFormat  string
+		expectedContent string
+		checklists      bool
+	}{
+		{mode: MarkdownConversionClient, expectedFormat: "html", expectedContent: `<ul data-section-style="7">`, checklists: true},
+		{mode: MarkdownConversionServer, expectedFormat: "markdown", expectedContent: markdown},
+		// Unset, markdown is converted so task lists become checklists
+		{mode: "", expectedFormat: "html", expectedContent: `<ul data-section-style="7">`, checklists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			forms := map[string]url.Values{}
+			quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				forms[r.URL.Path] = r.PostForm
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "doc123", Title: "Plan"}})
+			}))
+			defer quipServer.Close()
+
+			s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)), WithMarkdownConversion(tt.mode))
+
+			if text, isError := callTool(t, s, "create_document", map[string]any{"title": "Plan", "content": markdown}); isError {
+				t.Fatalf("create_document failed: %s", text)
+			}
+			if text, isError := callTool(t, s, "edit_document", map[string]any{"document_id": "doc123", "content": markdown}); isError {
+				t.Fatalf("edit_document failed: %s", text)
+			}
+
+			for _, path := range []string{"/threads/new-document", "/threads/edit-document"} {
+				form := forms[path]
+				if form.Get("format") != tt.expectedFormat || !strings.Contains(form.Get("content"), tt.expectedContent) {
+					t.Errorf("Expected %s to send format=%s with %q, got format=%s content=%q",
+						path, tt.expectedFormat, tt.expectedContent, form.Get("format"), form.Get("content"))
+				}
+			}
+
+			// create_document only promises checklists when it converts task lists itself
+			for _, tool := range s.Tools() {
+				if tool.Name != "create_document" {
+					continue
+				}
+				for _, param := range tool.Parameters {
+					if param.Name == "format" && strings.Contains(param.Description, "become Quip checklists") != tt.checklists {
+						t.Errorf("Expected the format description to match conversion mode %s, got %q", tt.mode, param.Description)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFormatUser(t *testing.T) {
+	user := &quip.User{
+		ID:       "user123",
+		Name:     "Deploy Bot",
+		Email:    "bot@example.com",
+		Emails:   []string{"bot@example.com", "deploys@example.com"},
+		Affinity: 0.8,
+		ChatOnly: true,
+		Desktop:  true,
+	}
+
+	text := New("test-token").formatUser(user)
+	for _, want := range []string{
+		"- **Other Emails:** deploys@example.com\n",
+		"- **Affinity:** 0.80",
+		"- **Chat-only account:** yes",
+		"- **Uses the desktop app:** yes",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in %q", want, text)
+		}
+	}
+
+	// Unpopulated fields are left out
+	text = New("test-token").formatUser(&quip.User{ID: "user456", Name: "Ann", Email: "ann@example.com", Emails: []string{"ann@example.com"}})
+	for _, notWant := range []string{"Other Emails", "Affinity", "Chat-only", "desktop"} {
+		if strings.Contains(text, notWant) {
+			t.Errorf("Expected no %q in %q", notWant, text)
+		}
+	}
+}
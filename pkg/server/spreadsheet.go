@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerSpreadsheetTools registers the spreadsheet read/write tools
+func (s *Server) registerSpreadsheetTools() {
+	// Get spreadsheet tool
+	getSpreadsheetTool := mcp.NewTool(
+		"get_spreadsheet",
+		mcp.WithDescription("Get the cell contents of a Quip spreadsheet as tables"),
+		mcp.WithString("thread_id", mcp.Required(), mcp.Description("The ID or URL of the spreadsheet thread")),
+		mcp.WithString("sheet", mcp.Description("Name of the sheet to return (default: all sheets)")),
+	)
+
+	s.addTool(getSpreadsheetTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		threadID, err := req.RequireString("thread_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid thread_id argument: %v", err)), nil
+		}
+		threadID = quip.ResolveThreadID(threadID)
+
+		sheetName := req.GetString("sheet", "")
+
+		spreadsheet, err := s.client(ctx).GetSpreadsheetData(threadID)
+		if err != nil {
+			return toolError("get spreadsheet", err), nil
+		}
+
+		sheets := spreadsheet.Sheets
+		if sheetName != "" {
+			sheet, err := spreadsheet.FindSheet(sheetName)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sheets = []quip.Sheet{*sheet}
+		}
+
+		response := fmt.Sprintf("**%s**\n\n", spreadsheet.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", spreadsheet.ThreadID)
+		response += fmt.Sprintf("- **Sheets:** %d\n", len(spreadsheet.Sheets))
+
+		for _, sheet := range sheets {
+			response += fmt.Sprintf("\n### %s (%d rows)\n\n", sheet.Title, len(sheet.Rows))
+			response += renderMarkdownTable(sheet.Headers, sheet.Rows)
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Create spreadsheet tool
+	createSpreadsheetTool := mcp.NewTool(
+		"create_spreadsheet",
+		mcp.WithDescription("Create a new Quip spreadsheet"),
+		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the new spreadsheet")),
+		mcp.WithString("content", mcp.Description("Initial cells as a markdown table (the first row becomes the header row) or an HTML <table>; omit for a blank spreadsheet"+s.contentLimitNote())),
+		mcp.WithString("format", mcp.Description("Content format: markdown (default), html"), mcp.Enum("markdown", "html"), mcp.DefaultString(quip.DefaultContentFormat)),
+	)
+
+	s.addTool(createSpreadsheetTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		title, err := req.RequireString("title")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid title argument: %v", err)), nil
+		}
+
+		content, _, err := quipContent(req.GetString("content", ""), req.GetString("format", quip.DefaultContentFormat))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid content: %v", err)), nil
+		}
+
+		doc, err := s.client(ctx).CreateSpreadsheet(title, content)
+		if err != nil {
+			return toolError("create spreadsheet", err), nil
+		}
+
+		response := "✅ **Spreadsheet created successfully!**\n\n"
+		response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+		response += fmt.Sprintf("- **Created:** %s\n", s.formatTimestamp(doc.Created))
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Update spreadsheet cell tool
+	updateCellTool := mcp.NewTool(
+		"update_spreadsheet_cell",
+		mcp.WithDescription("Set the value of a single cell in a Quip spreadsheet"),
+		mcp.WithString("thread_id", mcp.Required(), mcp.Description("The ID or URL of the spreadsheet thread")),
+		mcp.WithString("cell", mcp.Required(), mcp.Description("Cell in A1 notation, e.g. B3 (row 1 is the first row below the headers)")),
+		mcp.WithString("value", mcp.Required(), mcp.Description("The new cell value")),
+		mcp.WithString("sheet", mcp.Description("Name of the sheet to edit (default: first sheet)")),
+	)
+
+	s.addTool(updateCellTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		threadID, err := req.RequireString("thread_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid thread_id argument: %v", err)), nil
+		}
+		threadID = quip.ResolveThreadID(threadID)
+
+		cell, err := req.RequireString("cell")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid cell argument: %v", err)), nil
+		}
+
+		value, err := req.RequireString("value")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid value argument: %v", err)), nil
+		}
+
+		sheet := req.GetString("sheet", "")
+
+		doc, err := s.client(ctx).UpdateSpreadsheetCell(threadID, sheet, cell, value)
+		if err != nil {
+			return toolError("update cell", err), nil
+		}
+
+		response := "✅ **Cell updated successfully!**\n\n"
+		response += fmt.Sprintf("- **Spreadsheet:** %s\n", doc.Title)
+		response += fmt.Sprintf("- **Cell:** %s\n", strings.ToUpper(cell))
+		response += fmt.Sprintf("- **Value:** %s\n", value)
+		response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
+
+// spreadsheetMarkdown renders each sheet of a spreadsheet thread as a heading and a markdown
+// table. It reports false when the content can't be parsed or has no sheets, so the caller can
+// fall back to the generic conversion.
+func spreadsheetMarkdown(doc *quip.Document) (string, bool) {
+	spreadsheet, err := quip.ParseSpreadsheet(doc)
+	if err != nil || len(spreadsheet.Sheets) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	for i, sheet := range spreadsheet.Sheets {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", sheet.Title)
+		b.WriteString(renderMarkdownTable(sheet.Headers, sheet.Rows))
+	}
+	return b.String(), true
+}
+
+// renderMarkdownTable renders headers and rows as a markdown table
+func renderMarkdownTable(headers []string, rows [][]string) string {
+	if len(rows) == 0 && len(headers) == 0 {
+		return "_Empty sheet_\n"
+	}
+
+	width := len(headers)
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for i := 0; i < width; i++ {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			b.WriteString(" " + escapeTableCell(cell) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(headers)
+	b.WriteString("|")
+	for i := 0; i < width; i++ {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	return b.String()
+}
+
+// escapeTableCell keeps cell text from breaking the markdown table layout
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_CreateSpreadsheet(t *testing.T) {
+	var content string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content = r.FormValue("content")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "sheet123", Title: "Budget", Link: "https://quip.com/sheet123"}})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "create_spreadsheet", map[string]any{
+		"title":   "Budget",
+		"content": "| Item | Cost |\n| --- | --- |\n| Laptop | 1200 |\n",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	for _, want := range []string{"<table>", "<th>Item</th>", "<td>Laptop</td>"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the markdown table to be sent as HTML containing %q, got %q", want, content)
+		}
+	}
+	if !strings.Contains(text, "- **ID:** sheet123") || !strings.Contains(text, "https://quip.com/sheet123") {
+		t.Errorf("Expected the new spreadsheet's ID and link, got %q", text)
+	}
+}
+
+func TestServer_GetDocument_Spreadsheet(t *testing.T) {
+	// Quip's spreadsheet HTML: an empty corner header above the row numbers, column letters in
+	// the header row, and the column headers as the first data row
+	const sheetHTML = `<table id="s1" title="Q3 Budget"><thead><tr><th></th><th>A</th><th>B</th></tr></thead>` +
+		`<tbody><tr><td id="c1">Item</td><td id="c2">Cost</td></tr>` +
+		`<tr><td id="c3">Laptop | 15"</td><td id="c4">1,200</td></tr>` +
+		`<tr><td id="c5">Desk</td><td id="c6"></td></tr></tbody></table>` +
+		`<table id="s2" title="Notes"><thead><tr><th></th><th>A</th></tr></thead><tbody><tr><td id="c7">Approved</td></tr></tbody></table>`
+
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "sheet123", Title: "Budget", Type: "spreadsheet"}, HTML: sheetHTML})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_document", map[string]any{"document_id": "sheet123"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	want := "## Q3 Budget\n\n" +
+		"| A | B |\n" +
+		"| --- | --- |\n" +
+		"| Item | Cost |\n" +
+		"| Laptop \\| 15\" | 1,200 |\n" +
+		"| Desk |  |\n" +
+		"\n## Notes\n\n" +
+		"| A |\n" +
+		"| --- |\n" +
+		"| Approved |\n"
+	if !strings.Contains(text, want) {
+		t.Errorf("Expected the sheets rendered as markdown tables:\n%s\ngot:\n%s", want, text)
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+// DefaultStartupCheckTimeout bounds the token check Start and StartHTTP make before serving
+const DefaultStartupCheckTimeout = 10 * time.Second
+
+// WithStartupCheck sets how long Start and StartHTTP wait for the startup token check.
+// A timeout of zero or less skips the check.
+func WithStartupCheck(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.startupCheckTimeout = timeout
+	}
+}
+
+// verifyToken checks the token with GetCurrentUser and logs who the server runs as.
+// Only a rejected token is fatal; timeouts and other failures are logged so that a
+// network blip doesn't stop the server from starting.
+func (s *Server) verifyToken(ctx context.Context) error {
+	if s.startupCheckTimeout <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.startupCheckTimeout)
+	defer cancel()
+
+	user, err := s.client(ctx).GetCurrentUser()
+	if quip.IsUnauthorized(err) {
+		return fmt.Errorf("Quip rejected the API token (401 Unauthorized); check QUIP_API_TOKEN or run 'quip-mcp --setup'")
+	}
+	if err != nil {
+		s.logger.Warn("Could not verify the API token; starting anyway", "error", err)
+		return nil
+	}
+
+	s.logger.Info("Authenticated with Quip", "user", user.Name, "user_id", user.ID)
+	return nil
+}
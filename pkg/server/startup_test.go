@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_VerifyToken(t *testing.T) {
+	status := int32(http.StatusOK)
+	var calls int32
+	api := newFakeQuipAPI(t, &status, &calls)
+
+	server := New("test-token", WithClientOptions(quip.WithBaseURL(api.URL)))
+	if err := server.verifyToken(context.Background()); err != nil {
+		t.Errorf("Expected a working token to pass, got %v", err)
+	}
+
+	atomic.StoreInt32(&status, http.StatusUnauthorized)
+	err := server.verifyToken(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "rejected the API token") {
+		t.Errorf("Expected a clear error for a rejected token, got %v", err)
+	}
+
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	if err := server.verifyToken(context.Background()); err != nil {
+		t.Errorf("Expected other failures to be logged only, got %v", err)
+	}
+
+	skipped := New("test-token", WithClientOptions(quip.WithBaseURL(api.URL)), WithStartupCheck(0))
+	before := atomic.LoadInt32(&calls)
+	if err := skipped.verifyToken(context.Background()); err != nil || atomic.LoadInt32(&calls) != before {
+		t.Errorf("Expected the check to be skipped, got %v after %d calls", err, atomic.LoadInt32(&calls)-before)
+	}
+}
+
+func TestServer_VerifyToken_Timeout(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer api.Close()
+
+	server := New("test-token",
+		WithClientOptions(quip.WithBaseURL(api.URL)),
+		WithStartupCheck(50*time.Millisecond),
+	)
+
+	start := time.Now()
+	if err := server.verifyToken(context.Background()); err != nil {
+		t.Errorf("Expected a timeout to be logged only, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the check to give up after its timeout, took %v", elapsed)
+	}
+}
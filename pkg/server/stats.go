@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// documentStats describes the size of a document's content
+type documentStats struct {
+	Sections int // Quip sections: paragraphs, headings, list items and other blocks with a section ID
+	Headings int
+	Words    int
+	Chars    int // length of the markdown rendering in characters, comparable to get_document's max_chars
+}
+
+// computeDocumentStats derives size statistics from a document's HTML and its markdown rendering
+func computeDocumentStats(html, markdown string) documentStats {
+	stats := documentStats{Chars: utf8.RuneCountInString(markdown)}
+
+	dom, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		stats.Words = len(strings.Fields(markdown))
+		return stats
+	}
+
+	body := dom.Find("body")
+	stats.Sections = body.Find("[id]").Length()
+	stats.Headings = body.Find("h1, h2, h3, h4, h5, h6").Length()
+	// Count per text node: Text() runs adjacent blocks together ("plan</h1><p>Ship" -> "planShip")
+	body.Find("*").AddSelection(body).Contents().Each(func(_ int, node *goquery.Selection) {
+		if goquery.NodeName(node) == "#text" {
+			stats.Words += len(strings.Fields(node.Text()))
+		}
+	})
+
+	return stats
+}
+
+// formatDocumentStats renders stats as a one-line summary
+func formatDocumentStats(stats documentStats) string {
+	return fmt.Sprintf("%s, %s, %s, %s",
+		pluralize(stats.Words, "word"),
+		pluralize(stats.Sections, "section"),
+		pluralize(stats.Headings, "heading"),
+		pluralize(stats.Chars, "character"),
+	)
+}
+
+// registerStatsTools registers the document statistics tool
+func (s *Server) registerStatsTools() {
+	// Get document stats tool
+	statsTool := mcp.NewTool(
+		"get_document_stats",
+		mcp.WithDescription("Get a Quip document's size (words, sections, headings, characters) without its content, to decide whether to read it whole or in chunks"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document")),
+	)
+
+	s.addTool(statsTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		doc, err := s.client(ctx).GetDocument(documentID)
+		if err != nil {
+			return toolError("get document", err), nil
+		}
+
+		stats := computeDocumentStats(doc.HTML, s.documentMarkdown(doc))
+
+		response := fmt.Sprintf("**%s**\n\n", doc.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Words:** %d\n", stats.Words)
+		response += fmt.Sprintf("- **Sections:** %d\n", stats.Sections)
+		response += fmt.Sprintf("- **Headings:** %d\n", stats.Headings)
+		response += fmt.Sprintf("- **Characters:** %d (markdown)\n", stats.Chars)
+		response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
@@ -0,0 +1,29 @@
+package server
+
+import "testing"
+
+func TestComputeDocumentStats(t *testing.T) {
+	html := `<h1 id="a1">Quarterly plan</h1>` +
+		`<p id="a2">Ship the new search experience.</p>` +
+		`<ul id="a3"><li id="a4">Hire two engineers</li><li id="a5">Cut costs</li></ul>` +
+		`<h2 id="a6">Risks</h2><p id="a7">None.</p>`
+
+	stats := computeDocumentStats(html, htmlToMarkdown(html, MarkdownOptions{}))
+
+	if stats.Sections != 7 {
+		t.Errorf("Expected 7 sections, got %d", stats.Sections)
+	}
+	if stats.Headings != 2 {
+		t.Errorf("Expected 2 headings, got %d", stats.Headings)
+	}
+	if stats.Words != 14 {
+		t.Errorf("Expected 14 words, got %d", stats.Words)
+	}
+	if stats.Chars == 0 {
+		t.Error("Expected a non-zero character count")
+	}
+
+	if got := formatDocumentStats(documentStats{Words: 1, Sections: 2}); got != "1 word, 2 sections, 0 headings, 0 characters" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+}
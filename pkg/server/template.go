@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerTemplateTools registers the tools for managing templates and starting documents from them
+func (s *Server) registerTemplateTools() {
+	// Create from template tool
+	createFromTemplateTool := mcp.NewTool(
+		"create_from_template",
+		mcp.WithDescription("Create a new Quip document by copying a template document (e.g. a team's design doc template) under a new title"),
+		mcp.WithString("template_id", mcp.Required(), mcp.Description("The ID or URL of the template document")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the new document")),
+	)
+
+	s.addTool(createFromTemplateTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		templateID, err := req.RequireString("template_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid template_id argument: %v", err)), nil
+		}
+		templateID = quip.ResolveThreadID(templateID)
+
+		title, err := req.RequireString("title")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid title argument: %v", err)), nil
+		}
+
+		doc, err := s.client(ctx).CreateFromTemplate(templateID, title)
+		if err != nil {
+			return toolError("create document from template", err), nil
+		}
+
+		response := "✅ **Document created from template!**\n\n"
+		response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Type:** %s\n", doc.Type)
+		response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+		response += fmt.Sprintf("- **Template:** %s\n", templateID)
+		response += fmt.Sprintf("- **Created:** %s\n", s.formatTimestamp(doc.Created))
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Mark as template tool
+	markTemplateTool := mcp.NewTool(
+		"mark_as_template",
+		mcp.WithDescription("Flag a Quip document as a template, so it appears in the team's template gallery, or remove the flag"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The ID or URL of the document")),
+		mcp.WithBoolean("is_template", mcp.Description("true to make the document a template (default), false to make it a regular document again")),
+	)
+
+	s.addTool(markTemplateTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		documentID, err := req.RequireString("document_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid document_id argument: %v", err)), nil
+		}
+		documentID = quip.ResolveThreadID(documentID)
+
+		isTemplate := req.GetBool("is_template", true)
+
+		doc, err := s.client(ctx).SetTemplate(documentID, isTemplate)
+		if err != nil {
+			return toolError("update template flag", err), nil
+		}
+
+		var response string
+		switch {
+		case doc.IsTemplate != isTemplate:
+			response = fmt.Sprintf("⚠️ **Template flag unconfirmed:** Quip accepted the request but still reports the document's template flag as %t, so the change may not have been applied.\n\n", doc.IsTemplate)
+		case doc.IsTemplate:
+			response = "✅ **Document marked as a template**\n\n"
+		default:
+			response = "✅ **Document is no longer a template**\n\n"
+		}
+		if doc.Title != "" {
+			response += fmt.Sprintf("- **Title:** %s\n", doc.Title)
+		}
+		response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+		response += fmt.Sprintf("- **Template:** %t\n", doc.IsTemplate)
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// List templates tool
+	listTemplatesTool := mcp.NewTool(
+		"list_templates",
+		mcp.WithDescription(fmt.Sprintf("List Quip documents flagged as templates, for use with create_from_template. Quip can't search by the flag, so only the top %d search results (or most recent threads, without a query) are checked.", quip.MaxLimit)),
+		mcp.WithString("query", mcp.Description("Only check documents matching this search query (default: your most recent threads)")),
+		limitArg("Maximum number of templates to return", s.searchLimit),
+	)
+
+	s.addTool(listTemplatesTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limit, err := quip.NormalizeLimit(req.GetInt("limit", s.searchLimit))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid limit argument: %v", err)), nil
+		}
+
+		query := req.GetString("query", "")
+		templates, err := s.client(ctx).ListTemplates(query, limit)
+		if err != nil {
+			return toolError("list templates", err), nil
+		}
+
+		if len(templates) == 0 {
+			if query != "" {
+				return mcp.NewToolResultText(fmt.Sprintf("No templates found matching %q.", query)), nil
+			}
+			return mcp.NewToolResultText("No templates found among your recent threads. Pass a query to search for them."), nil
+		}
+
+		response := fmt.Sprintf("Found %s:\n\n", pluralize(len(templates), "template"))
+		for i, doc := range templates {
+			response += fmt.Sprintf("%d. **%s**\n", i+1, doc.Title)
+			response += fmt.Sprintf("   - ID: %s\n", doc.ID)
+			response += fmt.Sprintf("   - Link: %s\n", doc.Link)
+			response += fmt.Sprintf("   - Updated: %s\n\n", s.formatTimestamp(doc.Updated))
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+}
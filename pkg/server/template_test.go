@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_CreateFromTemplate(t *testing.T) {
+	var threadID string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		threadID = r.FormValue("thread_id")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{
+			Thread: quip.Document{ID: "doc456", Title: r.FormValue("title"), Link: "https://quip.com/doc456"},
+		})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "create_from_template", map[string]any{
+		"template_id": "https://quip.com/tmpl123/Design-Doc-Template",
+		"title":       "Search Redesign",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	if threadID != "tmpl123" {
+		t.Errorf("Expected the template URL to be resolved to tmpl123, got %q", threadID)
+	}
+	if !strings.Contains(text, "- **Title:** Search Redesign") || !strings.Contains(text, "https://quip.com/doc456") {
+		t.Errorf("Expected the new document's title and link, got %q", text)
+	}
+}
+
+func TestServer_Templates(t *testing.T) {
+	var flagged string
+	isTemplate, ignoreFlag := false, false
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/edit-document":
+			flagged = r.FormValue("thread_id") + ":" + r.FormValue("is_template")
+			if !ignoreFlag {
+				isTemplate = r.FormValue("is_template") == "true"
+			}
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "tmpl123", Title: "Design Doc"}})
+		case "/threads/tmpl123":
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "tmpl123", Title: "Design Doc", IsTemplate: isTemplate}})
+		case "/threads/search":
+			_ = json.NewEncoder(w).Encode([]quip.SearchResponse{
+				{Thread: quip.Document{ID: "doc1", Title: "Design Review"}},
+				{Thread: quip.Document{ID: "tmpl123", Title: "Design Doc", IsTemplate: true, Link: "https://quip.com/tmpl123"}},
+			})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "mark_as_template", map[string]any{"document_id": "https://quip.com/tmpl123/Design-Doc"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if flagged != "tmpl123:true" || !strings.Contains(text, "marked as a template") || !strings.Contains(text, "- **Template:** true") {
+		t.Errorf("Expected tmpl123 to be flagged, sent %q and got %q", flagged, text)
+	}
+
+	text, _ = callTool(t, s, "mark_as_template", map[string]any{"document_id": "tmpl123", "is_template": false})
+	if flagged != "tmpl123:false" || !strings.Contains(text, "- **Template:** false") {
+		t.Errorf("Expected the flag to be removed, sent %q and got %q", flagged, text)
+	}
+
+	// Quip ignoring the flag is reported, not claimed as a success
+	ignoreFlag = true
+	text, _ = callTool(t, s, "mark_as_template", map[string]any{"document_id": "tmpl123"})
+	if !strings.Contains(text, "unconfirmed") || strings.Contains(text, "marked as a template") {
+		t.Errorf("Expected the unapplied flag to be reported as unconfirmed, got %q", text)
+	}
+
+	text, isError = callTool(t, s, "list_templates", map[string]any{"query": "design"})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+	if !strings.Contains(text, "Found 1 template:") || !strings.Contains(text, "1. **Design Doc**") || strings.Contains(text, "Design Review") {
+		t.Errorf("Expected only the template to be listed, got %q", text)
+	}
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultThreadMessages is how many chat messages get_thread lists when no count is given
+const defaultThreadMessages = 20
+
+// registerThreadTools registers the type-agnostic thread tool
+func (s *Server) registerThreadTools() {
+	// Get thread tool
+	getThreadTool := mcp.NewTool(
+		"get_thread",
+		mcp.WithDescription("Get any Quip thread, adapting to its type: chats list their recent messages, spreadsheets summarize their sheets and documents return their content like get_document. Use this when you don't know what kind of thread an ID refers to."),
+		mcp.WithString("thread_id", mcp.Required(), mcp.Description("The ID or URL of the thread")),
+		mcp.WithNumber("count", mcp.Description(fmt.Sprintf("For chats, the number of recent messages to list (default: %d)", defaultThreadMessages))),
+	)
+
+	s.addTool(getThreadTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		threadID, err := req.RequireString("thread_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid thread_id argument: %v", err)), nil
+		}
+		threadID = quip.ResolveThreadID(threadID)
+
+		client := s.client(ctx)
+
+		doc, err := client.GetDocument(threadID)
+		if err != nil {
+			return toolError("get thread", err), nil
+		}
+
+		var response string
+		switch strings.ToLower(doc.Type) {
+		case "chat":
+			page, err := client.GetThreadMessages(doc.ID, quip.CommentOptions{Count: req.GetInt("count", defaultThreadMessages)})
+			if err != nil {
+				return toolError("get chat messages", err), nil
+			}
+			response = s.formatChat(doc, page, resolveAuthorNames(client, commentAuthorIDs(page.Comments)), false)
+		case "spreadsheet":
+			spreadsheet, err := quip.ParseSpreadsheet(doc)
+			if err != nil {
+				return toolError("read spreadsheet", err), nil
+			}
+			response = s.formatSpreadsheetSummary(doc, spreadsheet)
+		default:
+			response, err = s.formatDocument(doc, accessLevelNames(client, doc), contentFormatMarkdown, 0, 0)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		return mcp.NewToolResultText(response), nil
+	})
+
+	// Get chat messages tool
+	getChatMessagesTool := mcp.NewTool(
+		"get_chat_messages",
+		mcp.WithDescription("Read the messages of a Quip chat thread with author names and times, one page at a time"),
+		mcp.WithString("thread_id", mcp.Required(), mcp.Description("The ID or URL of the chat thread")),
+		mcp.WithNumber("count", mcp.Description(fmt.Sprintf("Maximum number of messages to return (default: %d)", defaultThreadMessages)), mcp.Min(1)),
+		mcp.WithString("cursor", mcp.Description("Cursor from a previous call to fetch the next page of messages, in the same sort order")),
+		mcp.WithString("sort", mcp.Description("Order by creation time: DESC (newest first, default) or ASC"), mcp.Enum("DESC", "ASC")),
+	)
+
+	s.addTool(getChatMessagesTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		threadID, err := req.RequireString("thread_id")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid thread_id argument: %v", err)), nil
+		}
+		threadID = quip.ResolveThreadID(threadID)
+
+		opts := quip.CommentOptions{
+			Count:    req.GetInt("count", defaultThreadMessages),
+			SortedBy: req.GetString("sort", ""),
+		}
+
+		if cursor := req.GetString("cursor", ""); cursor != "" {
+			if err := opts.ContinueFrom(cursor); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid cursor argument: %v", err)), nil
+			}
+		}
+
+		client := s.client(ctx)
+
+		doc, err := client.GetDocument(threadID)
+		if err != nil {
+			return toolError("get thread", err), nil
+		}
+
+		page, err := client.GetThreadMessages(doc.ID, opts)
+		if err != nil {
+			return toolError("get chat messages", err), nil
+		}
+
+		note := ""
+		if threadType := strings.ToLower(doc.Type); threadType != "" && threadType != "chat" {
+			note = fmt.Sprintf("ℹ️ **Note:** this is a %s, not a chat; its messages are the comments on it.\n\n", threadType)
+		}
+
+		names := resolveAuthorNames(client, commentAuthorIDs(page.Comments))
+		ascending := strings.EqualFold(opts.SortedBy, "ASC")
+
+		return mcp.NewToolResultText(note + s.formatChat(doc, page, names, ascending)), nil
+	})
+}
+
+// threadTypeNote returns advice to prepend when a tool expecting a document was given a
+// thread of another type, or "" if the type is fine (or unknown)
+func threadTypeNote(doc *quip.Document) string {
+	switch threadType := strings.ToLower(doc.Type); threadType {
+	case "", "document":
+		return ""
+	case "chat":
+		return "ℹ️ **Note:** this is a chat thread, not a document; use get_chat_messages to read its messages.\n\n"
+	case "spreadsheet":
+		return "ℹ️ **Note:** this is a spreadsheet; use get_spreadsheet to read its cells or get_thread for a summary of its sheets.\n\n"
+	default:
+		return fmt.Sprintf("ℹ️ **Note:** this is a %s thread, not a document; get_thread may present it better.\n\n", threadType)
+	}
+}
+
+// formatThreadHeader renders the metadata shared by every thread type
+func (s *Server) formatThreadHeader(doc *quip.Document) string {
+	response := fmt.Sprintf("**%s**\n\n", doc.Title)
+	response += fmt.Sprintf("- **ID:** %s\n", doc.ID)
+	response += fmt.Sprintf("- **Type:** %s\n", doc.Type)
+	response += fmt.Sprintf("- **Link:** %s\n", doc.Link)
+	response += fmt.Sprintf("- **Updated:** %s\n", s.formatTimestamp(doc.Updated))
+	return response
+}
+
+// formatChat renders a chat thread and a page of its messages in the order given (newest
+// first unless ascending), naming authors from names
+func (s *Server) formatChat(doc *quip.Document, page *quip.CommentPage, names map[string]string, ascending bool) string {
+	response := s.formatThreadHeader(doc)
+
+	if len(page.Comments) == 0 {
+		return response + "\nNo messages in this chat.\n"
+	}
+
+	if ascending {
+		response += fmt.Sprintf("\n**Messages** (%d, oldest first):\n\n", len(page.Comments))
+	} else {
+		response += fmt.Sprintf("\n**Recent messages** (%d, newest first):\n\n", len(page.Comments))
+	}
+	for _, message := range page.Comments {
+		response += fmt.Sprintf("- **%s** · %s: %s\n", authorName(names, message.AuthorID), s.formatDate(message.Created), message.Text)
+	}
+
+	if page.NextCursor != "" {
+		if ascending {
+			response += fmt.Sprintf("\nNewer messages available. Use get_chat_messages with cursor=%s and sort=ASC to read them.\n", page.NextCursor)
+		} else {
+			response += fmt.Sprintf("\nOlder messages available. Use get_chat_messages with cursor=%s to read them.\n", page.NextCursor)
+		}
+	}
+
+	return response
+}
+
+// formatSpreadsheetSummary renders a spreadsheet's sheets with their sizes and column headers
+func (s *Server) formatSpreadsheetSummary(doc *quip.Document, spreadsheet *quip.Spreadsheet) string {
+	response := s.formatThreadHeader(doc)
+	response += fmt.Sprintf("- **Sheets:** %d\n", len(spreadsheet.Sheets))
+
+	for _, sheet := range spreadsheet.Sheets {
+		response += fmt.Sprintf("\n### %s\n\n", sheet.Title)
+		response += fmt.Sprintf("- **Rows:** %d\n", len(sheet.Rows))
+		if len(sheet.Headers) > 0 {
+			response += fmt.Sprintf("- **Columns:** %s\n", strings.Join(sheet.Headers, ", "))
+		}
+	}
+
+	response += "\nUse get_spreadsheet to read the cell contents.\n"
+	return response
+}
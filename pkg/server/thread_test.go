@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+func TestServer_GetThread(t *testing.T) {
+	threads := map[string]quip.Document{
+		"chat1":  {ID: "chat1", Title: "Team chat", Type: "chat"},
+		"sheet1": {ID: "sheet1", Title: "Budget", Type: "spreadsheet", HTML: `<table title="Costs"><thead><tr><th></th><th>A</th><th>B</th></tr></thead><tbody><tr><td>Item</td><td>Cost</td></tr><tr><td>Laptop</td><td>1200</td></tr></tbody></table>`},
+		"doc1":   {ID: "doc1", Title: "Plan", Type: "document", HTML: "<p>Ship it</p>"},
+	}
+
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/threads/chat1/messages" {
+			if count := r.URL.Query().Get("count"); count != "2" {
+				t.Errorf("Expected count=2, got %q", count)
+			}
+			_ = json.NewEncoder(w).Encode([]quip.Comment{
+				{ID: "m2", AuthorID: "user2", Text: "Sounds good", Created: 2000},
+				{ID: "m1", AuthorID: "user1", Text: "Lunch?", Created: 1000},
+			})
+			return
+		}
+		if r.URL.Path == "/users/" {
+			_ = json.NewEncoder(w).Encode(map[string]quip.User{"user2": {ID: "user2", Name: "Grace"}})
+			return
+		}
+		doc, ok := threads[strings.TrimPrefix(r.URL.Path, "/threads/")]
+		if !ok {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: doc})
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string
+	}{
+		{name: "chat", args: map[string]any{"thread_id": "chat1", "count": 2}, want: []string{"Recent messages", "**Grace** · Jan 1, 1970", "Sounds good", "**Unknown user (user1)**", "Lunch?"}},
+		{name: "spreadsheet", args: map[string]any{"thread_id": "sheet1"}, want: []string{"### Costs", "- **Rows:** 2", "- **Columns:** A, B"}},
+		{name: "document", args: map[string]any{"thread_id": "https://quip.com/doc1/Plan"}, want: []string{"**Plan**", "**Content:**", "Ship it"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, isError := callTool(t, s, "get_thread", tt.args)
+			if isError {
+				t.Fatalf("Expected success, got error %q", text)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected result to contain %q, got %q", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestThreadTypeNote(t *testing.T) {
+	tests := []struct {
+		threadType string
+		want       string
+	}{
+		{threadType: "", want: ""},
+		{threadType: "document", want: ""},
+		{threadType: "chat", want: "use get_chat_messages"},
+		{threadType: "SPREADSHEET", want: "use get_spreadsheet"},
+		{threadType: "slides", want: "this is a slides thread"},
+	}
+
+	for _, tt := range tests {
+		note := threadTypeNote(&quip.Document{Type: tt.threadType})
+		if tt.want == "" && note != "" {
+			t.Errorf("Expected no note for type %q, got %q", tt.threadType, note)
+		}
+		if !strings.Contains(note, tt.want) {
+			t.Errorf("Expected note for type %q to contain %q, got %q", tt.threadType, tt.want, note)
+		}
+	}
+}
+
+func TestServer_GetChatMessages(t *testing.T) {
+	// m1 was edited after m3 was posted, so it also matches a later updated-since filter
+	messages := []quip.Comment{
+		{ID: "m1", AuthorID: "user1", Text: "Lunch?", Created: 1000, Updated: 3500},
+		{ID: "m2", AuthorID: "user2", Text: "Sounds good", Created: 2000, Updated: 2000},
+		{ID: "m3", AuthorID: "user1", Text: "Noon?", Created: 3000, Updated: 3000},
+		{ID: "m4", AuthorID: "user2", Text: "See you", Created: 4000, Updated: 4000},
+	}
+
+	var queries []string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/chat1/messages":
+			query := r.URL.Query()
+			queries = append(queries, r.URL.RawQuery)
+
+			// Serve the messages the way Quip filters and orders them
+			since, _ := strconv.ParseInt(query.Get("last_updated_since_usec"), 10, 64)
+			before, _ := strconv.ParseInt(query.Get("max_created_usec"), 10, 64)
+			var page []quip.Comment
+			for _, message := range messages {
+				if message.Updated > since && (before == 0 || message.Created <= before) {
+					page = append(page, message)
+				}
+			}
+			if query.Get("sorted_by") != "ASC" {
+				slices.Reverse(page)
+			}
+			count, _ := strconv.Atoi(query.Get("count"))
+			_ = json.NewEncoder(w).Encode(page[:min(count, len(page))])
+		case "/users/":
+			_ = json.NewEncoder(w).Encode(map[string]quip.User{"user1": {ID: "user1", Name: "Ada"}, "user2": {ID: "user2", Name: "Grace"}})
+		case "/threads/chat1":
+			_ = json.NewEncoder(w).Encode(quip.RecentThreadData{Thread: quip.Document{ID: "chat1", Title: "Team chat", Type: "chat"}})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	text, isError := callTool(t, s, "get_chat_messages", map[string]any{
+		"thread_id": "https://quip.com/chat1",
+		"count":     2,
+		"sort":      "ASC",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error %q", text)
+	}
+
+	for _, param := range []string{"count=2", "sorted_by=ASC"} {
+		if !strings.Contains(queries[0], param) {
+			t.Errorf("Expected request to include %s, got %q", param, queries[0])
+		}
+	}
+
+	want := "- **Ada** · Jan 1, 1970 00:00 UTC: Lunch?\n- **Grace** · Jan 1, 1970 00:00 UTC: Sounds good\n"
+	if !strings.Contains(text, "oldest first") || !strings.Contains(text, want) {
+		t.Errorf("Expected the conversation oldest first with author names, got %q", text)
+	}
+	if strings.Contains(text, "Recent messages") || strings.Contains(text, "Older messages") {
+		t.Errorf("Expected ascending output not to describe the page as recent or the next as older, got %q", text)
+	}
+	if !strings.Contains(text, "Use get_chat_messages with cursor=2000:1 and sort=ASC to read them") {
+		t.Fatalf("Expected a cursor for the next page in ascending order, got %q", text)
+	}
+
+	// Following the cursor continues with newer messages in order. The edited m1 comes back
+	// from Quip too; the cursor asks for one extra message to make up for dropping it.
+	var read []string
+	for cursor := "2000:1"; cursor != ""; {
+		text, isError = callTool(t, s, "get_chat_messages", map[string]any{
+			"thread_id": "chat1",
+			"count":     2,
+			"cursor":    cursor,
+			"sort":      "ASC",
+		})
+		if isError || len(read) > 3 {
+			t.Fatalf("Expected paging to finish, got %q", text)
+		}
+		for _, line := range strings.Split(text, "\n") {
+			if _, message, ok := strings.Cut(line, "UTC: "); ok {
+				read = append(read, message)
+			}
+		}
+		cursor = ""
+		if _, rest, ok := strings.Cut(text, "cursor="); ok {
+			cursor, _, _ = strings.Cut(rest, " ")
+		}
+	}
+	if !strings.Contains(queries[1], "count=3") || !strings.Contains(queries[1], "last_updated_since_usec=2000") || strings.Contains(queries[1], "max_created_usec") {
+		t.Errorf("Expected the ascending cursor to ask for newer messages, got %q", queries[1])
+	}
+	if strings.Join(read, ",") != "Noon?,See you" {
+		t.Errorf("Expected the later messages oldest first without repeats, got %v", read)
+	}
+
+	// Newest first, the cursor continues with older messages
+	text, _ = callTool(t, s, "get_chat_messages", map[string]any{"thread_id": "chat1", "count": 2})
+	if !strings.Contains(text, "Use get_chat_messages with cursor=2999 to read them") {
+		t.Fatalf("Expected a cursor for older messages, got %q", text)
+	}
+	text, _ = callTool(t, s, "get_chat_messages", map[string]any{"thread_id": "chat1", "count": 2, "cursor": "2999"})
+	if query := queries[len(queries)-1]; !strings.Contains(query, "max_created_usec=2999") || strings.Index(text, "Sounds good") > strings.Index(text, "Lunch?") {
+		t.Errorf("Expected the older messages newest first, got %q for %q", text, query)
+	}
+
+	if _, isError := callTool(t, s, "get_chat_messages", map[string]any{"thread_id": "chat1", "cursor": "yesterday"}); !isError {
+		t.Error("Expected an error for an invalid cursor")
+	}
+}
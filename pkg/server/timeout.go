@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultToolTimeout bounds a tool call, including every Quip request it makes, unless
+// WithToolTimeout says otherwise
+const DefaultToolTimeout = 2 * time.Minute
+
+// WithToolTimeout sets how long a tool call may run before its Quip requests are abandoned
+// (default DefaultToolTimeout). Zero or a negative value means no limit.
+func WithToolTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.toolTimeout = timeout
+	}
+}
+
+// WithToolTimeouts overrides the timeout for the named tools, e.g. a short limit for
+// search_documents and a long one for exports. Zero or a negative value means no limit.
+func WithToolTimeouts(perTool map[string]time.Duration) Option {
+	return func(s *Server) {
+		s.toolTimeouts = perTool
+	}
+}
+
+// timeoutFor returns the timeout that applies to the named tool
+func (s *Server) timeoutFor(tool string) time.Duration {
+	if timeout, ok := s.toolTimeouts[tool]; ok {
+		return timeout
+	}
+	return s.toolTimeout
+}
+
+// timeoutToolCall is tool middleware that runs each call under its tool's timeout. A call
+// that fails because the deadline passed gets a result saying so, rather than the bare
+// "context deadline exceeded" from whichever request was cut off.
+func (s *Server) timeoutToolCall(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timeout := s.timeoutFor(req.Params.Name)
+		if timeout <= 0 {
+			return next(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := next(ctx, req)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && (err != nil || (result != nil && result.IsError)) {
+			return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s. Quip may be slow right now; retry, or narrow the request (e.g. a smaller limit).", req.Params.Name, timeout)), nil
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// addDeadlineTool registers a tool that reports how long its context has left
+func addDeadlineTool(s *Server, name string) {
+	s.addTool(mcp.NewTool(name), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return mcp.NewToolResultText("none"), nil
+		}
+		return mcp.NewToolResultText(time.Until(deadline).Round(time.Second).String()), nil
+	})
+}
+
+func TestServer_ToolTimeouts(t *testing.T) {
+	s := New("test-token",
+		WithToolTimeout(time.Minute),
+		WithToolTimeouts(map[string]time.Duration{"quick_tool": 10 * time.Second, "unbounded_tool": 0}),
+	)
+	for _, name := range []string{"quick_tool", "other_tool", "unbounded_tool"} {
+		addDeadlineTool(s, name)
+	}
+
+	tests := map[string]string{
+		"quick_tool":     "10s",
+		"other_tool":     "1m0s",
+		"unbounded_tool": "none",
+	}
+	for name, expected := range tests {
+		if text, _ := callTool(t, s, name, map[string]any{}); text != expected {
+			t.Errorf("Expected %s to have deadline %s, got %s", name, expected, text)
+		}
+	}
+
+	defaults := New("test-token")
+	addDeadlineTool(defaults, "any_tool")
+	if text, _ := callTool(t, defaults, "any_tool", map[string]any{}); text != DefaultToolTimeout.String() {
+		t.Errorf("Expected the default timeout %s, got %s", DefaultToolTimeout, text)
+	}
+}
+
+func TestServer_ToolTimeoutExceeded(t *testing.T) {
+	s := New("test-token", WithToolTimeouts(map[string]time.Duration{"slow_tool": 20 * time.Millisecond}))
+	s.addTool(mcp.NewTool("slow_tool"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to export: %v", ctx.Err())), nil
+	})
+
+	text, isError := callTool(t, s, "slow_tool", map[string]any{})
+	if !isError || !strings.Contains(text, "slow_tool timed out after 20ms") {
+		t.Errorf("Expected a timeout error naming the tool, got %q", text)
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import "time"
+
+// DefaultDateFormat is the Go time layout timestamps are shown in by default
+const DefaultDateFormat = "Jan 2, 2006 15:04 MST"
+
+// WithTimeFormat shows timestamps in tool results in loc using layout, a Go time layout such
+// as "2006-01-02 15:04 MST". A nil loc keeps the default of UTC and an empty layout keeps
+// DefaultDateFormat.
+func WithTimeFormat(loc *time.Location, layout string) Option {
+	return func(s *Server) {
+		if loc != nil {
+			s.timeLocation = loc
+		}
+		if layout != "" {
+			s.dateFormat = layout
+		}
+	}
+}
+
+// formatTimestamp renders a Quip timestamp (microseconds since the epoch) in the configured
+// timezone and layout
+func (s *Server) formatTimestamp(timestamp int64) string {
+	if timestamp == 0 {
+		return "Unknown"
+	}
+	return time.UnixMicro(timestamp).In(s.timeLocation).Format(s.dateFormat)
+}
+
+// formatDate is formatTimestamp for comment and message dates, which read better with
+// "Unknown date" when missing
+func (s *Server) formatDate(timestamp int64) string {
+	if timestamp == 0 {
+		return "Unknown date"
+	}
+	return s.formatTimestamp(timestamp)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithToolFilter limits which tools are registered. If enabled is non-empty only the named
+// tools are registered; tools named in disabled are never registered. Names that don't match
+// any tool are reported as warnings at startup.
+func WithToolFilter(enabled, disabled []string) Option {
+	return func(s *Server) {
+		s.enabledTools = toolSet(enabled)
+		s.disabledTools = toolSet(disabled)
+	}
+}
+
+// toolSet builds a lookup set from tool names, returning nil for an empty list
+func toolSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// toolAllowed reports whether the tool filter permits registering the named tool
+func (s *Server) toolAllowed(name string) bool {
+	if s.disabledTools[name] {
+		return false
+	}
+	return s.enabledTools == nil || s.enabledTools[name]
+}
+
+// ToolInfo describes a tool the server defines
+type ToolInfo struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  []ToolParameter `json:"parameters"`
+	// Enabled is false for tools the tool filter keeps from being offered to clients
+	Enabled bool `json:"enabled"`
+}
+
+// ToolParameter describes one argument of a tool
+type ToolParameter struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required"`
+	Enum        []string `json:"enum,omitempty"`
+}
+
+// Tools lists every tool the server defines, sorted by name, including those disabled by
+// the tool filter. Parameters are sorted by name.
+func (s *Server) Tools() []ToolInfo {
+	tools := make([]ToolInfo, 0, len(s.knownTools))
+	for _, tool := range s.knownTools {
+		tools = append(tools, ToolInfo{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  toolParameters(tool.InputSchema),
+			Enabled:     s.toolAllowed(tool.Name),
+		})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// toolParameters flattens a tool's input schema into its parameters
+func toolParameters(schema mcp.ToolInputSchema) []ToolParameter {
+	required := toolSet(schema.Required)
+
+	params := make([]ToolParameter, 0, len(schema.Properties))
+	for name, raw := range schema.Properties {
+		param := ToolParameter{Name: name, Required: required[name]}
+		if prop, ok := raw.(map[string]any); ok {
+			param.Type, _ = prop["type"].(string)
+			param.Description, _ = prop["description"].(string)
+			if enum, ok := prop["enum"].([]string); ok {
+				param.Enum = enum
+			}
+		}
+		params = append(params, param)
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+// addTool registers a tool unless the tool filter excludes it. Calls are checked against the
+// tool's input schema before the handler runs.
+func (s *Server) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	s.knownTools = append(s.knownTools, tool)
+	if !s.toolAllowed(tool.Name) {
+		s.logger.Debug("Tool disabled by configuration", "tool", tool.Name)
+		return
+	}
+	s.mcpServer.AddTool(tool, withArgumentValidation(tool, handler))
+}
+
+// warnUnknownTools logs filter entries that don't name any tool, which are usually typos
+func (s *Server) warnUnknownTools() {
+	known := make(map[string]bool, len(s.knownTools))
+	for _, tool := range s.knownTools {
+		known[tool.Name] = true
+	}
+	for _, set := range []map[string]bool{s.enabledTools, s.disabledTools} {
+		for name := range set {
+			if !known[name] {
+				s.logger.Warn("Tool filter names an unknown tool", "tool", name)
+			}
+		}
+	}
+}
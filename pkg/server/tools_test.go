@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// listToolDefinitions returns the tools the MCP server advertises
+func listToolDefinitions(t *testing.T, s *Server) []mcp.Tool {
+	t.Helper()
+
+	message := []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+	response, ok := s.mcpServer.HandleMessage(context.Background(), json.RawMessage(message)).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatal("Expected a successful JSON-RPC response for tools/list")
+	}
+	result, ok := response.Result.(mcp.ListToolsResult)
+	if !ok {
+		t.Fatalf("Expected a tools/list result, got %T", response.Result)
+	}
+	return result.Tools
+}
+
+// listTools returns the names of the tools the MCP server advertises
+func listTools(t *testing.T, s *Server) []string {
+	t.Helper()
+
+	tools := listToolDefinitions(t, s)
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestServer_ToolFilter(t *testing.T) {
+	contains := func(names []string, name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("no filter", func(t *testing.T) {
+		tools := listTools(t, New("test-token"))
+		if !contains(tools, "delete_document") || !contains(tools, "get_document") {
+			t.Errorf("Expected all tools to be registered, got %v", tools)
+		}
+	})
+
+	t.Run("denylist", func(t *testing.T) {
+		tools := listTools(t, New("test-token", WithToolFilter(nil, []string{"delete_document", "edit_document"})))
+		if contains(tools, "delete_document") || contains(tools, "edit_document") {
+			t.Errorf("Expected denied tools to be absent, got %v", tools)
+		}
+		if !contains(tools, "get_document") {
+			t.Errorf("Expected other tools to remain, got %v", tools)
+		}
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		tools := listTools(t, New("test-token", WithToolFilter([]string{"search_documents", "get_document"}, nil)))
+		if len(tools) != 2 || tools[0] != "get_document" || tools[1] != "search_documents" {
+			t.Errorf("Expected only the allowed tools, got %v", tools)
+		}
+	})
+
+	t.Run("denylist wins over allowlist", func(t *testing.T) {
+		tools := listTools(t, New("test-token", WithToolFilter([]string{"search_documents", "get_document"}, []string{"get_document"})))
+		if len(tools) != 1 || tools[0] != "search_documents" {
+			t.Errorf("Expected only search_documents, got %v", tools)
+		}
+	})
+}
+
+func TestServer_LimitSchema(t *testing.T) {
+	s := New("test-token", WithDefaultLimits(25, 0))
+
+	expectedDefaults := map[string]float64{"search_documents": 25, "get_recent_threads": 10}
+	for _, tool := range listToolDefinitions(t, s) {
+		expectedDefault, ok := expectedDefaults[tool.Name]
+		if !ok {
+			continue
+		}
+		delete(expectedDefaults, tool.Name)
+
+		limit, ok := tool.InputSchema.Properties["limit"].(map[string]any)
+		if !ok {
+			t.Fatalf("Expected %s to declare a limit property, got %v", tool.Name, tool.InputSchema.Properties)
+		}
+		for key, want := range map[string]float64{"minimum": 1, "maximum": 100, "multipleOf": 1, "default": expectedDefault} {
+			if got, ok := limit[key].(float64); !ok || got != want {
+				t.Errorf("Expected %s limit %s to be %v, got %v", tool.Name, key, want, limit[key])
+			}
+		}
+	}
+
+	if len(expectedDefaults) != 0 {
+		t.Errorf("Tools not advertised: %v", expectedDefaults)
+	}
+}
+
+func TestServer_Tools(t *testing.T) {
+	expected := []string{
+		"add_comment", "add_section", "append_to_document", "convert_markdown", "create_document",
+		"create_from_template", "create_spreadsheet", "delete_comment", "delete_document",
+		"delete_documents", "diff_documents", "edit_comment", "edit_document", "edit_section",
+		"export_folder", "follow_document", "get_chat_messages", "get_document", "get_document_by_url",
+		"get_document_comments", "get_document_history", "get_document_stats", "get_documents",
+		"get_folder_documents", "get_recent_threads", "get_spreadsheet", "get_thread", "get_user",
+		"list_contacts", "list_folders", "list_recent_edits", "list_sections", "list_templates",
+		"list_user_folders", "lock_document", "mark_as_template", "move_document", "prepend_to_document",
+		"rename_document", "search_documents", "unfollow_document", "update_spreadsheet_cell",
+	}
+
+	s := New("test-token", WithToolFilter(nil, []string{"delete_document"}))
+	tools := s.Tools()
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected tools %v, got %v", expected, names)
+	}
+
+	for _, tool := range tools {
+		if tool.Description == "" {
+			t.Errorf("Expected %s to have a description", tool.Name)
+		}
+		if tool.Enabled == (tool.Name == "delete_document") {
+			t.Errorf("Expected only delete_document to be disabled, got %s enabled=%v", tool.Name, tool.Enabled)
+		}
+		if tool.Name != "get_recent_threads" {
+			continue
+		}
+
+		var typeParam *ToolParameter
+		for i := range tool.Parameters {
+			if tool.Parameters[i].Name == "type" {
+				typeParam = &tool.Parameters[i]
+			}
+		}
+		if typeParam == nil || typeParam.Type != "string" || typeParam.Required || len(typeParam.Enum) != 4 {
+			t.Errorf("Expected get_recent_threads' optional type enum, got %+v", tool.Parameters)
+		}
+	}
+
+	// The listing matches what clients are offered, apart from disabled tools
+	if advertised := listTools(t, s); len(advertised) != len(expected)-1 {
+		t.Errorf("Expected %d advertised tools, got %d", len(expected)-1, len(advertised))
+	}
+}
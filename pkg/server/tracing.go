@@ -0,0 +1,19 @@
+//go:build !otel
+
+package server
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// traceToolCall is a no-op unless built with the otel tag
+func (s *Server) traceToolCall(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return next
+}
+
+// traceAttrs returns no log attributes unless built with the otel tag
+func traceAttrs(ctx context.Context) []any {
+	return nil
+}
@@ -0,0 +1,48 @@
+//go:build otel
+
+package server
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/bug-breeder/quip-mcp/pkg/server")
+
+// traceToolCall is tool middleware that wraps each invocation in a span. Quip API calls
+// made with the handler's context become child spans.
+func (s *Server) traceToolCall(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := tracer.Start(ctx, "tool "+req.Params.Name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("mcp.tool.name", req.Params.Name)),
+		)
+		defer span.End()
+
+		result, err := next(ctx, req)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, "tool returned an error result")
+		}
+
+		return result, err
+	}
+}
+
+// traceAttrs returns log attributes identifying the span in ctx, if any
+func traceAttrs(ctx context.Context) []any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []any{"trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()}
+}
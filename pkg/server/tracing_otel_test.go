@@ -0,0 +1,42 @@
+//go:build otel
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestServer_TraceToolCall(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	tracer = otel.Tracer("github.com/bug-breeder/quip-mcp/pkg/server")
+
+	server := New("test-token")
+
+	var attrs []any
+	handler := server.traceToolCall(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		attrs = traceAttrs(ctx)
+		return mcp.NewToolResultError("boom"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_document"
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "tool get_document" {
+		t.Fatalf("Expected one 'tool get_document' span, got %v", spans)
+	}
+
+	if len(attrs) != 4 || attrs[0] != "trace_id" || attrs[1] != spans[0].SpanContext().TraceID().String() {
+		t.Errorf("Expected trace attributes for the tool span, got %v", attrs)
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultMaxResponseBytes caps the text of a tool result unless WithMaxResponseBytes says otherwise
+const DefaultMaxResponseBytes = 256 << 10
+
+// WithMaxResponseBytes changes the largest tool result, in bytes of text, sent to the MCP client
+// (default DefaultMaxResponseBytes). Longer results are cut with a marker saying how much was
+// left out. Zero or a negative value disables the limit.
+func WithMaxResponseBytes(n int) Option {
+	return func(s *Server) {
+		s.maxResponseBytes = n
+	}
+}
+
+// truncateToolResult is tool middleware that enforces the response size limit on every tool
+func (s *Server) truncateToolResult(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if err == nil && result != nil && s.maxResponseBytes > 0 {
+			truncateResult(result, s.maxResponseBytes)
+		}
+		return result, err
+	}
+}
+
+// truncateResult cuts the text content of result to at most limit bytes (plus the marker),
+// dropping any text blocks after the cut. Non-text content is left alone.
+func truncateResult(result *mcp.CallToolResult, limit int) {
+	remaining := limit
+	cutAt := -1
+	omitted := 0
+
+	kept := result.Content[:0]
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			kept = append(kept, content)
+			continue
+		}
+
+		switch {
+		case cutAt >= 0:
+			omitted += len(text.Text)
+		case len(text.Text) <= remaining:
+			remaining -= len(text.Text)
+			kept = append(kept, text)
+		default:
+			cut := remaining
+			for cut > 0 && !utf8.RuneStart(text.Text[cut]) {
+				cut--
+			}
+			omitted += len(text.Text) - cut
+			text.Text = text.Text[:cut]
+			cutAt = len(kept)
+			kept = append(kept, text)
+		}
+	}
+
+	if cutAt >= 0 {
+		text := kept[cutAt].(mcp.TextContent)
+		text.Text += fmt.Sprintf("\n\n...(truncated, %d more bytes; ask for fewer results or read the document in chunks with max_chars/offset)", omitted)
+		kept[cutAt] = text
+	}
+	result.Content = kept
+}
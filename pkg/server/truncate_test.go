@@ -0,0 +1,54 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTruncateResult(t *testing.T) {
+	result := &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.TextContent{Type: "text", Text: "héllo"}, // 6 bytes
+		mcp.ImageContent{Type: "image", Data: "abc", MIMEType: "image/png"},
+		mcp.TextContent{Type: "text", Text: "world"},
+		mcp.TextContent{Type: "text", Text: "dropped"},
+	}}
+
+	truncateResult(result, 8)
+
+	if len(result.Content) != 3 {
+		t.Fatalf("Expected 3 content blocks after truncation, got %d", len(result.Content))
+	}
+	if _, ok := result.Content[1].(mcp.ImageContent); !ok {
+		t.Errorf("Expected non-text content to be kept, got %T", result.Content[1])
+	}
+
+	text := result.Content[2].(mcp.TextContent).Text
+	if !strings.HasPrefix(text, "wo\n\n...(truncated, 10 more bytes") {
+		t.Errorf("Expected the second text block cut after 2 bytes with a marker, got %q", text)
+	}
+
+	// A cut in the middle of a multi-byte character backs up to the character boundary
+	result = mcp.NewToolResultText("héllo")
+	truncateResult(result, 2)
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.HasPrefix(text, "h\n\n...(truncated, 5 more bytes") {
+		t.Errorf("Expected the cut before é, got %q", text)
+	}
+
+	// Results within the limit are untouched
+	result = mcp.NewToolResultText("short")
+	truncateResult(result, 100)
+	if text := result.Content[0].(mcp.TextContent).Text; text != "short" {
+		t.Errorf("Expected an untouched result, got %q", text)
+	}
+}
+
+func TestServer_MaxResponseBytes(t *testing.T) {
+	s := New("test-token", WithMaxResponseBytes(20))
+
+	text, _ := callTool(t, s, "convert_markdown", map[string]any{"markdown": strings.Repeat("word ", 100)})
+	if !strings.Contains(text, "...(truncated,") || len(text) > 200 {
+		t.Errorf("Expected a truncated result, got %q", text)
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// withArgumentValidation wraps a tool handler so calls whose arguments don't match the tool's
+// input schema are rejected before the handler runs. mcp-go doesn't enforce the schema, and
+// the request's Get* accessors quietly substitute the default for a value of the wrong type,
+// so without this a typo like operation "APPPEND" would run with the default operation.
+func withArgumentValidation(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := validateArguments(tool.InputSchema, req.GetArguments()); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid %s argument: %s", err.name, err.reason)), nil
+		}
+		return handler(ctx, req)
+	}
+}
+
+// argumentError says which argument of a tool call is invalid and why
+type argumentError struct {
+	name   string
+	reason string
+}
+
+func (e *argumentError) Error() string {
+	return fmt.Sprintf("invalid %s argument: %s", e.name, e.reason)
+}
+
+// validateArguments checks args against schema: required arguments are present, and each
+// argument has its declared type and respects its enum, minimum/maximum and item count. Enum
+// values are matched regardless of case and rewritten in args to their declared spelling. Numbers
+// and booleans sent as strings ("5", "true") are accepted, as the Get* accessors parse them.
+// Arguments the schema doesn't declare are left to the handler.
+func validateArguments(schema mcp.ToolInputSchema, args map[string]any) *argumentError {
+	for _, name := range schema.Required {
+		if value, ok := args[name]; !ok || value == nil {
+			return &argumentError{name: name, reason: "required argument is missing"}
+		}
+	}
+
+	// Check in a fixed order so a call with several bad arguments always reports the same one
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name].(map[string]any)
+		if !ok || args[name] == nil {
+			continue
+		}
+		if err := validateProperty(property, args[name]); err != nil {
+			return &argumentError{name: name, reason: err.Error()}
+		}
+		// Hand the handler the declared spelling of an enum value matched in another case
+		if allowed, ok := property["enum"].([]string); ok {
+			if value, ok := args[name].(string); ok {
+				args[name], _ = enumValue(allowed, value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateProperty checks one argument value against its property schema
+func validateProperty(property map[string]any, value any) error {
+	switch property["type"] {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a string, got %s", describeValue(value))
+		}
+		if allowed, ok := property["enum"].([]string); ok {
+			if _, ok := enumValue(allowed, s); !ok {
+				return fmt.Errorf("must be one of %s, got %q", strings.Join(allowed, ", "), s)
+			}
+		}
+
+	case "number", "integer":
+		n, ok := numberValue(value)
+		if !ok {
+			return fmt.Errorf("must be a number, got %s", describeValue(value))
+		}
+		minimum, hasMin := property["minimum"].(float64)
+		maximum, hasMax := property["maximum"].(float64)
+		switch {
+		case hasMin && hasMax && (n < minimum || n > maximum):
+			return fmt.Errorf("must be between %v and %v, got %v", minimum, maximum, n)
+		case hasMin && n < minimum:
+			return fmt.Errorf("must be at least %v, got %v", minimum, n)
+		case hasMax && n > maximum:
+			return fmt.Errorf("must be at most %v, got %v", maximum, n)
+		}
+
+	case "boolean":
+		if _, ok := boolValue(value); !ok {
+			return fmt.Errorf("must be true or false, got %s", describeValue(value))
+		}
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("must be an array, got %s", describeValue(value))
+		}
+		if minItems, ok := property["minItems"].(int); ok && len(items) < minItems {
+			return fmt.Errorf("must have at least %s, got %d", pluralize(minItems, "item"), len(items))
+		}
+		if maxItems, ok := property["maxItems"].(int); ok && len(items) > maxItems {
+			return fmt.Errorf("must have at most %s, got %d", pluralize(maxItems, "item"), len(items))
+		}
+		if itemSchema, ok := property["items"].(map[string]any); ok {
+			for i, item := range items {
+				if err := validateProperty(itemSchema, item); err != nil {
+					return fmt.Errorf("item %d %v", i+1, err)
+				}
+			}
+		}
+
+	case "object":
+		if _, ok := value.(map[string]any); !ok {
+			return fmt.Errorf("must be an object, got %s", describeValue(value))
+		}
+	}
+
+	return nil
+}
+
+// enumValue returns the allowed value matching value regardless of case, so "asc" is accepted
+// for ASC and "Markdown" for markdown
+func enumValue(allowed []string, value string) (string, bool) {
+	for _, candidate := range allowed {
+		if strings.EqualFold(candidate, value) {
+			return candidate, true
+		}
+	}
+	return value, false
+}
+
+// numberValue returns value as a number if it is one or a string holding one
+func numberValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil && !math.IsNaN(n) && !math.IsInf(n, 0)
+	default:
+		return 0, false
+	}
+}
+
+// boolValue returns value as a boolean if it is one or a string holding one
+func boolValue(value any) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	default:
+		return false, false
+	}
+}
+
+// describeValue names the JSON type of value, quoting strings, for error messages
+func describeValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64, int, int64:
+		return fmt.Sprintf("%v", v)
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
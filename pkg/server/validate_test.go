@@ -0,0 +1,131 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestValidateArguments(t *testing.T) {
+	tool := mcp.NewTool("example",
+		mcp.WithString("document_id", mcp.Required()),
+		mcp.WithString("operation", mcp.Enum("REPLACE", "APPEND", "PREPEND")),
+		mcp.WithNumber("limit", mcp.Min(1), mcp.Max(100)),
+		mcp.WithNumber("offset", mcp.Min(0)),
+		mcp.WithBoolean("permanent"),
+		mcp.WithArray("document_ids", mcp.WithStringItems(), mcp.MinItems(1), mcp.MaxItems(2)),
+	)
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		expected string // empty when the arguments are valid
+	}{
+		{name: "valid", args: map[string]any{"document_id": "doc1", "operation": "APPEND", "limit": 10.0, "permanent": true, "document_ids": []any{"a"}}},
+		{name: "numbers and booleans as strings", args: map[string]any{"document_id": "doc1", "limit": "10", "permanent": "false"}},
+		{name: "undeclared arguments are ignored", args: map[string]any{"document_id": "doc1", "extra": 1.0}},
+		{name: "missing required", args: map[string]any{"operation": "APPEND"}, expected: "invalid document_id argument: required argument is missing"},
+		{name: "null required", args: map[string]any{"document_id": nil}, expected: "invalid document_id argument: required argument is missing"},
+		{name: "enum", args: map[string]any{"document_id": "doc1", "operation": "REMOVE"}, expected: `invalid operation argument: must be one of REPLACE, APPEND, PREPEND, got "REMOVE"`},
+		{name: "enum ignores case", args: map[string]any{"document_id": "doc1", "operation": "append"}},
+		{name: "wrong type for string", args: map[string]any{"document_id": 12.0}, expected: "invalid document_id argument: must be a string, got 12"},
+		{name: "non-numeric", args: map[string]any{"document_id": "doc1", "limit": "ten"}, expected: `invalid limit argument: must be a number, got "ten"`},
+		{name: "out of range", args: map[string]any{"document_id": "doc1", "limit": 500.0}, expected: "invalid limit argument: must be between 1 and 100, got 500"},
+		{name: "below minimum", args: map[string]any{"document_id": "doc1", "offset": -1.0}, expected: "invalid offset argument: must be at least 0, got -1"},
+		{name: "not a boolean", args: map[string]any{"document_id": "doc1", "permanent": "maybe"}, expected: `invalid permanent argument: must be true or false, got "maybe"`},
+		{name: "not an array", args: map[string]any{"document_id": "doc1", "document_ids": "a,b"}, expected: `invalid document_ids argument: must be an array, got "a,b"`},
+		{name: "too many items", args: map[string]any{"document_id": "doc1", "document_ids": []any{"a", "b", "c"}}, expected: "invalid document_ids argument: must have at most 2 items, got 3"},
+		{name: "bad item", args: map[string]any{"document_id": "doc1", "document_ids": []any{"a", 2.0}}, expected: "invalid document_ids argument: item 2 must be a string, got 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateArguments(tool.InputSchema, tt.args)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestServer_RejectsInvalidArguments(t *testing.T) {
+	var requests int32
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	tests := []struct {
+		tool     string
+		args     map[string]any
+		expected string
+	}{
+		{"edit_document", map[string]any{"document_id": "doc1", "content": "x", "operation": "REMOVE"}, `Invalid operation argument: must be one of REPLACE, APPEND, PREPEND, got "REMOVE"`},
+		{"edit_document", map[string]any{"document_id": "doc1", "content": "x", "format": "rtf"}, `Invalid format argument: must be one of markdown, html, got "rtf"`},
+		{"search_documents", map[string]any{"query": "plan", "limit": "lots"}, `Invalid limit argument: must be a number, got "lots"`},
+		{"get_document", map[string]any{"document_id": "doc1", "expand_links": 7}, "Invalid expand_links argument: must be between 0 and 3, got 7"},
+		{"get_document", map[string]any{}, "Invalid document_id argument: required argument is missing"},
+	}
+
+	for _, tt := range tests {
+		text, isError := callTool(t, s, tt.tool, tt.args)
+		if !isError || text != tt.expected {
+			t.Errorf("%s %v: expected error %q, got %q (error: %v)", tt.tool, tt.args, tt.expected, text, isError)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("Expected invalid calls not to reach Quip, got %d requests", got)
+	}
+}
+
+func TestServer_AcceptsEnumsInAnyCase(t *testing.T) {
+	var sortedBy, location string
+	quipServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/threads/doc1/messages":
+			sortedBy = r.URL.Query().Get("sorted_by")
+			_, _ = w.Write([]byte("[]"))
+		case "/threads/edit-document":
+			location = r.FormValue("location")
+			_, _ = w.Write([]byte(`{"thread": {"id": "doc1"}}`))
+		default:
+			_, _ = w.Write([]byte("[]"))
+		}
+	}))
+	defer quipServer.Close()
+
+	s := New("test-token", WithClientOptions(quip.WithBaseURL(quipServer.URL)))
+
+	if text, isError := callTool(t, s, "get_document_comments", map[string]any{"document_id": "doc1", "sort": "asc"}); isError {
+		t.Fatalf("Expected lower-case sort to be accepted, got %q", text)
+	}
+	if sortedBy != "ASC" {
+		t.Errorf("Expected sorted_by ASC, got %q", sortedBy)
+	}
+
+	if text, isError := callTool(t, s, "edit_document", map[string]any{"document_id": "doc1", "content": "x", "operation": "prepend", "format": "HTML"}); isError {
+		t.Fatalf("Expected lower-case operation to be accepted, got %q", text)
+	}
+	if location != "1" {
+		t.Errorf("Expected the prepend location, got %q", location)
+	}
+
+	if text, isError := callTool(t, s, "get_recent_threads", map[string]any{"type": "Document"}); isError {
+		t.Fatalf("Expected a capitalized type to be accepted, got %q", text)
+	}
+}
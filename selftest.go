@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bug-breeder/quip-mcp/pkg/config"
+	"github.com/bug-breeder/quip-mcp/pkg/quip"
+)
+
+// selftestQuery is the search --selftest runs; any query works, the point is the endpoint
+const selftestQuery = "meeting"
+
+// selftestCheck is one read-only call made by --selftest. run returns a short description
+// of what came back.
+type selftestCheck struct {
+	name string
+	run  func(client *quip.Client) (string, error)
+}
+
+// selftestChecks are the calls --selftest makes, in order
+var selftestChecks = []selftestCheck{
+	{name: "Current user", run: func(client *quip.Client) (string, error) {
+		user, err := client.GetCurrentUser()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s (ID: %s)", user.Name, user.ID), nil
+	}},
+	{name: "Recent threads", run: func(client *quip.Client) (string, error) {
+		threads, err := client.GetRecentThreads(5)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d threads", len(threads)), nil
+	}},
+	{name: "Search", run: func(client *quip.Client) (string, error) {
+		result, err := client.SearchDocuments(selftestQuery, 5)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d results for %q", len(result.Documents), selftestQuery), nil
+	}},
+}
+
+// selftest loads the configuration and runs selftestChecks against Quip, reporting on stdout
+func selftest(configManager *config.ConfigManager) bool {
+	fmt.Println("🩺 Quip MCP self-test")
+	fmt.Println("=====================")
+	fmt.Println()
+
+	cfg, err := configManager.Load()
+	if err != nil {
+		fmt.Printf("❌ Error loading configuration: %v\n", err)
+		return false
+	}
+	if cfg.QuipAPIToken == "" {
+		fmt.Println("❌ No Quip API token configured")
+		fmt.Println()
+		fmt.Println("Run 'quip-mcp --setup' to configure your API token.")
+		return false
+	}
+
+	fmt.Printf("🔑 API Token: %s\n\n", maskToken(cfg.QuipAPIToken))
+
+	return runSelftest(quip.NewClient(cfg.QuipAPIToken, clientOptions(cfg, configManager)...), selftestChecks, os.Stdout)
+}
+
+// runSelftest runs every check, carrying on past failures so one run shows everything that is
+// broken, and reports whether all of them passed. Write requests are blocked the same way a
+// dry run blocks them, so a check can never change anything in Quip.
+func runSelftest(client *quip.Client, checks []selftestCheck, w io.Writer) bool {
+	client = client.WithContext(quip.WithDryRunRecorder(context.Background(), &quip.DryRunRecorder{}))
+
+	passed := 0
+	for _, check := range checks {
+		start := time.Now()
+		detail, err := check.run(client)
+		elapsed := time.Since(start).Round(time.Millisecond)
+
+		if err != nil {
+			fmt.Fprintf(w, "❌ %s (%s): %v\n", check.name, elapsed, err)
+			continue
+		}
+		passed++
+		fmt.Fprintf(w, "✅ %s (%s): %s\n", check.name, elapsed, detail)
+	}
+
+	if rl, ok := client.RateLimit(); ok {
+		fmt.Fprintf(w, "\n⏱️  Rate limit: %d of %d requests remaining\n", rl.Remaining, rl.Limit)
+	}
+
+	fmt.Fprintf(w, "\n%d of %d checks passed\n", passed, len(checks))
+	return passed == len(checks)
+}